@@ -20,6 +20,8 @@ const SkipDeepSync = "!deepsync"
 const (
 	NewSheetItemID = unison.UserBaseID + iota
 	NewTemplateItemID
+	NewVehicleItemID
+	ImportStatBlockItemID
 	NewTraitsLibraryItemID
 	NewTraitModifiersLibraryItemID
 	NewEquipmentLibraryItemID
@@ -37,6 +39,11 @@ const (
 	ExportAsWEBPItemID
 	ExportAsPNGItemID
 	ExportAsJPEGItemID
+	ExportAsStatBlockItemID
+	ExportAsFantasyGroundsItemID
+	ExportAsHTMLItemID
+	ExportAsCardsItemID
+	ExportAsBundleItemID
 	PrintItemID
 	UndoItemID
 	RedoItemID
@@ -47,6 +54,11 @@ const (
 	DecrementItemID
 	IncrementUsesItemID
 	DecrementUsesItemID
+	BuyItemID
+	SellItemID
+	FireWeaponItemID
+	ReloadWeaponItemID
+	CastSpellItemID
 	IncrementSkillLevelItemID
 	DecrementSkillLevelItemID
 	IncrementTechLevelItemID
@@ -54,12 +66,17 @@ const (
 	SwapDefaultsItemID
 	ItemMenuID
 	AddNaturalAttacksItemID
+	AdvanceTimeItemID
+	ActiveSpellsItemID
+	EffectsItemID
 	OpenEditorItemID
 	CopyToSheetItemID
 	CopyToTemplateItemID
 	ApplyTemplateItemID
 	OpenOnePageReferenceItemID
 	OpenEachPageReferenceItemID
+	SearchSourcebooksItemID
+	FillMissingPageReferencesItemID
 	SettingsMenuID
 	PerSheetSettingsItemID
 	PerSheetAttributeSettingsItemID
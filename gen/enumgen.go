@@ -126,6 +126,29 @@ func main() {
 			},
 		},
 	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "model/paper",
+		Name: "card_size",
+		Desc: "holds a standard playing card dimension",
+		Values: []enumValue{
+			{
+				Key:    "poker",
+				String: "Poker (2.5 × 3.5 in)",
+			},
+			{
+				Key:    "bridge",
+				String: "Bridge (2.25 × 3.5 in)",
+			},
+			{
+				Key:    "tarot",
+				String: "Tarot (2.75 × 4.75 in)",
+			},
+			{
+				Key:    "index",
+				String: "Index Card (3 × 5 in)",
+			},
+		},
+	})
 	processSourceTemplate(enumTmpl, &enumInfo{
 		Pkg:        "model/settings/display",
 		Name:       "option",
@@ -910,6 +933,124 @@ func main() {
 			},
 		},
 	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:        "model/gurps/measure",
+		Name:       "volume_units",
+		Desc:       "holds the volume unit type. Note that conversions to/from metric are done using the simplified GURPS metric conversion of 1 yd = 1 meter. For consistency, all metric volumes are converted to cubic meters, then to cubic inches, rather than the variations at different volumes that the GURPS rules suggest",
+		StandAlone: true,
+		Values: []enumValue{
+			{
+				Name:       "CubicFoot",
+				Key:        "cu ft",
+				String:     "cu ft",
+				NoLocalize: true,
+			},
+			{
+				Name:       "CubicInch",
+				Key:        "cu in",
+				String:     "cu in",
+				NoLocalize: true,
+			},
+			{
+				Name:       "CubicYard",
+				Key:        "cu yd",
+				String:     "cu yd",
+				NoLocalize: true,
+			},
+			{
+				Name:       "Gallon",
+				Key:        "gal",
+				String:     "gal",
+				NoLocalize: true,
+			},
+			{
+				Name:       "Quart",
+				Key:        "qt",
+				String:     "qt",
+				NoLocalize: true,
+			},
+			{
+				Name:       "CubicMeter",
+				Key:        "cu m",
+				String:     "cu m",
+				NoLocalize: true,
+			},
+			{
+				Name:       "Milliliter",
+				Key:        "ml",
+				String:     "ml",
+				NoLocalize: true,
+			},
+			{
+				Name:       "Liter",
+				Key:        "l",
+				String:     "l",
+				NoLocalize: true,
+			},
+		},
+	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:        "model/gurps/measure",
+		Name:       "area_units",
+		Desc:       "holds the area unit type. Note that conversions to/from metric are done using the simplified GURPS metric conversion of 1 yd = 1 meter. For consistency, all metric areas are converted to square meters, then to square inches, rather than the variations at different areas that the GURPS rules suggest",
+		StandAlone: true,
+		Values: []enumValue{
+			{
+				Name:       "SquareYard",
+				Key:        "sq yd",
+				String:     "sq yd",
+				NoLocalize: true,
+			},
+			{
+				Name:       "SquareInch",
+				Key:        "sq in",
+				String:     "sq in",
+				NoLocalize: true,
+			},
+			{
+				Name:       "SquareFoot",
+				Key:        "sq ft",
+				String:     "sq ft",
+				NoLocalize: true,
+			},
+			{
+				Name:       "Acre",
+				Key:        "ac",
+				String:     "ac",
+				NoLocalize: true,
+			},
+			{
+				Name:       "SquareMile",
+				Key:        "sq mi",
+				String:     "sq mi",
+				NoLocalize: true,
+			},
+			{
+				Name:       "SquareCentimeter",
+				Key:        "sq cm",
+				String:     "sq cm",
+				NoLocalize: true,
+			},
+			{
+				Name:       "SquareMeter",
+				Key:        "sq m",
+				String:     "sq m",
+				NoLocalize: true,
+			},
+			{
+				Name:       "Hectare",
+				Key:        "ha",
+				String:     "ha",
+				NoLocalize: true,
+			},
+			{
+				Name:       "SquareKilometer",
+				Key:        "sq km",
+				String:     "sq km",
+				NoLocalize: true,
+			},
+		},
+	})
 	processSourceTemplate(enumTmpl, &enumInfo{
 		Pkg:        "model/gurps",
 		Name:       "cell_type",
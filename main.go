@@ -18,8 +18,12 @@ import (
 	"github.com/richardwilkes/gcs/v5/model/convert"
 	"github.com/richardwilkes/gcs/v5/model/export"
 	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/statblock"
 	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/gcs/v5/model/schema"
 	"github.com/richardwilkes/gcs/v5/model/settings"
+	"github.com/richardwilkes/gcs/v5/model/spreadsheet"
+	"github.com/richardwilkes/gcs/v5/model/verify"
 	"github.com/richardwilkes/gcs/v5/setup"
 	"github.com/richardwilkes/gcs/v5/setup/early"
 	"github.com/richardwilkes/gcs/v5/ui"
@@ -40,9 +44,37 @@ Translations dir: "%s"`), settings.Path(), i18n.Dir)
 	var textTmplPath string
 	cl.NewGeneralOption(&textTmplPath).SetName("text").SetSingle('x').SetArg("file").
 		SetUsage(i18n.Text("Export sheets using the specified template file"))
+	var fantasyGrounds bool
+	cl.NewGeneralOption(&fantasyGrounds).SetName("fantasy-grounds").
+		SetUsage(i18n.Text("Export sheets in the Fantasy Grounds GURPS ruleset XML format"))
 	var convertFiles bool
 	cl.NewGeneralOption(&convertFiles).SetName("convert").SetSingle('c').
 		SetUsage(i18n.Text("Converts all files specified on the command line to the current data format. If a directory is specified, it will be traversed recursively and all files found will be converted. This operation is intended to easily bring files up to the current version's data format. After all files have been processed, GCS will exit"))
+	var importStatBlocks bool
+	cl.NewGeneralOption(&importStatBlocks).SetName("statblock").SetSingle('s').
+		SetUsage(i18n.Text("Imports the plain text stat blocks in the files specified on the command line, writing a character sheet next to each one and printing a report of the items that could not be matched against the libraries. After all files have been processed, GCS will exit"))
+	var toCSV, toTSV bool
+	cl.NewGeneralOption(&toCSV).SetName("to-csv").
+		SetUsage(i18n.Text("Exports the equipment, skill, spell, trait and modifier list files specified on the command line to CSV spreadsheets, writing each one next to its list file with .csv appended to the name. After all files have been processed, GCS will exit"))
+	cl.NewGeneralOption(&toTSV).SetName("to-tsv").
+		SetUsage(i18n.Text("Exports the equipment, skill, spell, trait and modifier list files specified on the command line to TSV spreadsheets, writing each one next to its list file with .tsv appended to the name. After all files have been processed, GCS will exit"))
+	var fromSpreadsheet bool
+	cl.NewGeneralOption(&fromSpreadsheet).SetName("from-spreadsheet").
+		SetUsage(i18n.Text("Imports the CSV or TSV spreadsheets specified on the command line, writing the list file named by removing the .csv or .tsv extension, e.g. Gear.eqp.csv produces Gear.eqp. Rows whose ID matches an item already in that list file update it in place, while items in the list file without a matching row are kept unless --replace is also given. After all files have been processed, GCS will exit"))
+	var replaceUnmatched bool
+	cl.NewGeneralOption(&replaceUnmatched).SetName("replace").
+		SetUsage(i18n.Text("When used with --from-spreadsheet, removes the items in the list file that have no matching row in the spreadsheet"))
+	var schemaDir string
+	cl.NewGeneralOption(&schemaDir).SetName("schemas").SetArg("dir").
+		SetUsage(i18n.Text("Writes the JSON Schema for each of the GCS data file types into the specified directory. After the schemas have been written, GCS will exit"))
+	var validate bool
+	cl.NewGeneralOption(&validate).SetName("validate").
+		SetUsage(i18n.Text("Validates the GCS data files specified on the command line against their JSON Schemas and by loading them, printing each problem found along with its file and line. If a directory is specified, it will be traversed recursively and all GCS data files found will be validated. After all files have been processed, GCS will exit, with a non-zero status if any problems were found"))
+	var verifySheets, verifyAsJSON bool
+	cl.NewGeneralOption(&verifySheets).SetName("verify").
+		SetUsage(i18n.Text("Loads and recalculates the character sheets specified on the command line, reporting every calculated value stored in the file that differs from the value calculated now. If a directory is specified, it will be traversed recursively and all character sheets found will be verified. After all files have been processed, GCS will exit, with a non-zero status if any differences were found"))
+	cl.NewGeneralOption(&verifyAsJSON).SetName("verify-json").
+		SetUsage(i18n.Text("Writes the report produced by --verify as JSON rather than text"))
 	cl.NewGeneralOption(&dbg.VariableResolver).SetName("debug-variable-resolver")
 	fileList := jotrotate.ParseAndSetup(cl)
 	setup.Setup()
@@ -55,6 +87,61 @@ Translations dir: "%s"`), settings.Path(), i18n.Dir)
 		if err := convert.Convert(fileList...); err != nil {
 			cl.FatalMsg(err.Error())
 		}
+	case importStatBlocks:
+		if len(fileList) == 0 {
+			cl.FatalMsg(i18n.Text("No files to process."))
+		}
+		if err := statblock.ImportFiles(fileList...); err != nil {
+			cl.FatalMsg(err.Error())
+		}
+	case toCSV, toTSV:
+		if len(fileList) == 0 {
+			cl.FatalMsg(i18n.Text("No files to process."))
+		}
+		ext := spreadsheet.CSVExt
+		if toTSV {
+			ext = spreadsheet.TSVExt
+		}
+		if err := spreadsheet.ExportFiles(ext, fileList...); err != nil {
+			cl.FatalMsg(err.Error())
+		}
+	case fromSpreadsheet:
+		if len(fileList) == 0 {
+			cl.FatalMsg(i18n.Text("No files to process."))
+		}
+		if err := spreadsheet.ImportFiles(replaceUnmatched, fileList...); err != nil {
+			cl.FatalMsg(err.Error())
+		}
+	case schemaDir != "":
+		written, err := schema.WriteAll(schemaDir)
+		for _, one := range written {
+			fmt.Printf(i18n.Text("Wrote %s\n"), one)
+		}
+		if err != nil {
+			cl.FatalMsg(err.Error())
+		}
+	case validate:
+		if len(fileList) == 0 {
+			cl.FatalMsg(i18n.Text("No files to process."))
+		}
+		failed, err := schema.ValidateFiles(fileList...)
+		if err != nil {
+			cl.FatalMsg(err.Error())
+		}
+		if failed != 0 {
+			atexit.Exit(1)
+		}
+	case verifySheets:
+		if len(fileList) == 0 {
+			cl.FatalMsg(i18n.Text("No files to process."))
+		}
+		failed, err := verify.Files(verifyAsJSON, fileList...)
+		if err != nil {
+			cl.FatalMsg(err.Error())
+		}
+		if failed != 0 {
+			atexit.Exit(1)
+		}
 	case textTmplPath != "":
 		if len(fileList) == 0 {
 			cl.FatalMsg(i18n.Text("No files to process."))
@@ -67,6 +154,18 @@ Translations dir: "%s"`), settings.Path(), i18n.Dir)
 		if err := export.ToText(textTmplPath, fileList); err != nil {
 			cl.FatalMsg(err.Error())
 		}
+	case fantasyGrounds:
+		if len(fileList) == 0 {
+			cl.FatalMsg(i18n.Text("No files to process."))
+		}
+		for _, one := range fileList {
+			if !library.FileInfoFor(one).IsExportable {
+				cl.FatalMsg(one + i18n.Text(" is not exportable."))
+			}
+		}
+		if err := export.ToFantasyGrounds(fileList); err != nil {
+			cl.FatalMsg(err.Error())
+		}
 	default:
 		ui.Start(fileList) // Never returns
 	}
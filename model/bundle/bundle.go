@@ -0,0 +1,312 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/ancestry"
+	"github.com/richardwilkes/gcs/v5/model/gurps/trait"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/gcs/v5/model/settings"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/log/jot"
+	"github.com/richardwilkes/toolbox/xio"
+	xfs "github.com/richardwilkes/toolbox/xio/fs"
+)
+
+// settingsDir is the directory within both the bundle and a library that holds the files a bundle carries.
+const settingsDir = "Settings"
+
+// settingsExts holds the extensions of the files a bundle carries in its settings directory.
+var settingsExts = []string{library.AncestryExt, library.CalendarExt, library.NamesExt, library.PageRefSettingsExt}
+
+// Bundle holds the contents of a portable character bundle: a sheet plus the library files it refers to by name.
+type Bundle struct {
+	SheetName string
+	Entity    *gurps.Entity
+	Files     []*File
+}
+
+// File holds a library file carried within a bundle.
+type File struct {
+	// Path is relative to the root of a library, using forward slashes.
+	Path string
+	Data []byte
+}
+
+// New creates a bundle for the entity, gathering the ancestries, name generators and calendar it refers to from the
+// libraries. The page reference keys and their offsets, which are only present in the settings, are carried along as a
+// page reference settings file; the paths to the PDFs and any bookmarks or highlights made within them are left behind,
+// since they are only meaningful on the sender's machine. The sheet already embeds its attributes and body type, so
+// those aren't carried separately.
+func New(entity *gurps.Entity, sheetName string, pageRefs []*settings.PageRef) (*Bundle, error) {
+	b := &Bundle{
+		SheetName: xfs.TrimExtension(sheetName) + library.SheetExt,
+		Entity:    entity,
+	}
+	libraries := gurps.SettingsProvider.Libraries()
+	seen := make(map[string]bool)
+	var nameGenerators []string
+	var err error
+	gurps.Traverse(func(t *gurps.Trait) bool {
+		if t.Container() && t.ContainerType == trait.Race && t.Ancestry != "" {
+			if ref := lookupLibraryFile(t.Ancestry, libraries, library.AncestryExt); ref != nil {
+				if a, loadErr := ancestry.NewAncestryFromFile(ref.FileSystem, ref.FilePath); loadErr != nil {
+					jot.Warn(loadErr)
+				} else {
+					if a.CommonOptions != nil {
+						nameGenerators = append(nameGenerators, a.CommonOptions.NameGenerators...)
+					}
+					for _, one := range a.GenderOptions {
+						if one.Value != nil {
+							nameGenerators = append(nameGenerators, one.Value.NameGenerators...)
+						}
+					}
+				}
+				if err = b.add(settingsDir, ref.FileSystem, ref.FilePath, seen); err != nil {
+					return true
+				}
+			}
+		}
+		return false
+	}, true, false, entity.Traits...)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range nameGenerators {
+		if ref := lookupLibraryFile(name, libraries, library.NamesExt); ref != nil {
+			if err = b.add(settingsDir, ref.FileSystem, ref.FilePath, seen); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if ref := lookupLibraryFile(entity.CalendarRef().Name, libraries, library.CalendarExt); ref != nil {
+		if err = b.add(settingsDir, ref.FileSystem, ref.FilePath, seen); err != nil {
+			return nil, err
+		}
+	}
+	if len(pageRefs) != 0 {
+		var refs settings.PageRefs
+		for _, one := range pageRefs {
+			refs.Set(&settings.PageRef{
+				ID:     one.ID,
+				Offset: one.Offset,
+			})
+		}
+		var buffer bytes.Buffer
+		if err = jio.Save(context.Background(), &buffer, &refs); err != nil {
+			return nil, err
+		}
+		b.Files = append(b.Files, &File{
+			Path: path.Join(settingsDir, xfs.TrimExtension(b.SheetName)+library.PageRefSettingsExt),
+			Data: buffer.Bytes(),
+		})
+	}
+	return b, nil
+}
+
+// lookupLibraryFile returns the file with the given name and extension from the libraries. Built-in files are not
+// considered, since every copy of GCS already has them.
+func lookupLibraryFile(name string, libraries library.Libraries, ext string) *library.NamedFileRef {
+	for _, set := range library.ScanForNamedFileSets(nil, "", true, libraries, ext) {
+		for _, one := range set.List {
+			if one.Name == name {
+				return one
+			}
+		}
+	}
+	return nil
+}
+
+func (b *Bundle) add(dir string, fileSystem fs.FS, filePath string, seen map[string]bool) error {
+	p := path.Join(dir, path.Base(filePath))
+	if seen[p] {
+		return nil
+	}
+	seen[p] = true
+	data, err := fs.ReadFile(fileSystem, filePath)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	b.Files = append(b.Files, &File{Path: p, Data: data})
+	return nil
+}
+
+// Save writes the bundle to a zip archive.
+func (b *Bundle) Save(filePath string) error {
+	var buffer bytes.Buffer
+	w := zip.NewWriter(&buffer)
+	f, err := w.Create(b.SheetName)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	if err = jio.Save(context.Background(), f, b.Entity); err != nil {
+		return err
+	}
+	for _, one := range b.Files {
+		if f, err = w.Create(one.Path); err != nil {
+			return errs.Wrap(err)
+		}
+		if _, err = f.Write(one.Data); err != nil {
+			return errs.Wrap(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		return errs.Wrap(err)
+	}
+	if err = os.WriteFile(filePath, buffer.Bytes(), 0o640); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
+
+// NewFromFile loads a bundle from a zip archive. Only the entries a bundle would have been written with are accepted.
+func NewFromFile(filePath string) (*Bundle, error) {
+	r, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	defer xio.CloseIgnoringErrors(r)
+	b := &Bundle{}
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		dir, ok := entryDir(f.Name)
+		switch {
+		case !ok:
+			return nil, errs.Newf(i18n.Text("bundle contains an unexpected entry: %s"), f.Name)
+		case dir == "":
+			if b.Entity != nil {
+				return nil, errs.New(i18n.Text("bundle contains more than one sheet"))
+			}
+			if b.Entity, err = gurps.NewEntityFromFile(&r.Reader, f.Name); err != nil {
+				return nil, err
+			}
+			b.SheetName = f.Name
+		default:
+			var data []byte
+			if data, err = readZipFile(f); err != nil {
+				return nil, err
+			}
+			b.Files = append(b.Files, &File{Path: f.Name, Data: data})
+		}
+	}
+	if b.Entity == nil {
+		return nil, errs.New(i18n.Text("bundle does not contain a sheet"))
+	}
+	sort.Slice(b.Files, func(i, j int) bool { return b.Files[i].Path < b.Files[j].Path })
+	return b, nil
+}
+
+// entryDir returns the directory of a bundle entry, which is empty for the sheet. Returns false if the entry isn't one
+// that a bundle is written with: a sheet at the top level or a file with a permitted extension directly within the
+// settings directory.
+func entryDir(name string) (dir string, ok bool) {
+	if strings.ContainsAny(name, `\:`) {
+		return "", false
+	}
+	parts := strings.Split(name, "/")
+	for _, part := range parts {
+		if part == "" || part == "." || part == ".." {
+			return "", false
+		}
+	}
+	switch len(parts) {
+	case 1:
+		return "", hasExt(name, []string{library.SheetExt})
+	case 2:
+		if parts[0] == settingsDir {
+			return settingsDir, hasExt(name, settingsExts)
+		}
+	}
+	return "", false
+}
+
+func hasExt(name string, exts []string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, one := range exts {
+		if ext == one {
+			return true
+		}
+	}
+	return false
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	defer xio.CloseIgnoringErrors(r)
+	var data []byte
+	if data, err = io.ReadAll(r); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return data, nil
+}
+
+// TargetPath returns the path the file would be installed to within the library. Returns an error if that path would
+// lie outside of the library.
+func (f *File) TargetPath(lib *library.Library) (string, error) {
+	root := filepath.Clean(lib.Path())
+	rootWithTrailingSep := root
+	if !strings.HasSuffix(rootWithTrailingSep, string(filepath.Separator)) {
+		rootWithTrailingSep += string(filepath.Separator)
+	}
+	p := filepath.Join(root, filepath.FromSlash(f.Path))
+	if !strings.HasPrefix(p, rootWithTrailingSep) {
+		return "", errs.Newf(i18n.Text("path outside of the library is not permitted: %s"), f.Path)
+	}
+	return p, nil
+}
+
+// Status returns whether the file already exists within the library and, if so, whether its content is identical.
+func (f *File) Status(lib *library.Library) (exists, identical bool) {
+	p, err := f.TargetPath(lib)
+	if err != nil {
+		return false, false
+	}
+	var data []byte
+	if data, err = os.ReadFile(p); err != nil {
+		return false, false
+	}
+	return true, bytes.Equal(data, f.Data)
+}
+
+// Install writes the file into the library, replacing any existing file of the same name.
+func (f *File) Install(lib *library.Library) error {
+	p, err := f.TargetPath(lib)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return errs.Wrap(err)
+	}
+	if err = os.WriteFile(p, f.Data, 0o640); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
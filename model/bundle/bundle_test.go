@@ -0,0 +1,63 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package bundle_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/bundle"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFromFileRejectsUnexpectedEntries(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"../Sheet.gcs",
+		"/Sheet.gcs",
+		"C:Sheet.gcs",
+		`Settings\Elf.ancestry`,
+		"Settings/../../Elf.ancestry",
+		"Settings/Nested/Elf.ancestry",
+		"Settings/Elf.exe",
+		"Output Templates/Export.txt",
+		"Other/Elf.ancestry",
+	} {
+		p := filepath.Join(dir, "test"+library.BundleExt)
+		f, err := os.Create(p)
+		if !assert.NoError(t, err) {
+			return
+		}
+		w := zip.NewWriter(f)
+		_, err = w.Create(name)
+		assert.NoError(t, err)
+		assert.NoError(t, w.Close())
+		assert.NoError(t, f.Close())
+		_, err = bundle.NewFromFile(p)
+		assert.ErrorContains(t, err, "bundle contains an unexpected entry: "+name, name)
+	}
+}
+
+func TestTargetPath(t *testing.T) {
+	dir := t.TempDir()
+	lib := library.NewLibrary("Test", "test", "test", dir)
+	p, err := (&bundle.File{Path: "Settings/Elf.ancestry"}).TargetPath(lib)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Settings", "Elf.ancestry"), p)
+	for _, one := range []string{"../Elf.ancestry", "Settings/../../Elf.ancestry", ".."} {
+		_, err = (&bundle.File{Path: one}).TargetPath(lib)
+		assert.Error(t, err, one)
+	}
+}
@@ -114,6 +114,14 @@ func Convert(paths ...string) error {
 			if err = tmpl.Save(p); err != nil {
 				return err
 			}
+		case library.VehiclesExt:
+			var vehicle *gurps.Vehicle
+			if vehicle, err = gurps.NewVehicleFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+				return err
+			}
+			if err = vehicle.Save(p); err != nil {
+				return err
+			}
 		case library.SheetExt:
 			var entity *gurps.Entity
 			if entity, err = gurps.NewEntityFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
@@ -156,6 +164,14 @@ func Convert(paths ...string) error {
 			if err = data.Save(p); err != nil {
 				return err
 			}
+		case library.CurrencyExt:
+			var data *gsettings.CurrencyRef
+			if data, err = gsettings.NewCurrencyRefFromFS(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+				return err
+			}
+			if err = data.Save(p); err != nil {
+				return err
+			}
 		case library.FontSettingsExt:
 			var data *theme.Fonts
 			if data, err = theme.NewFontsFromFS(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
@@ -21,10 +21,12 @@ type Numeric struct {
 	NumericData
 }
 
-// NumericData holds the criteria for matching a number that should be written to disk.
+// NumericData holds the criteria for matching a number that should be written to disk. Upper is only used by the
+// Between comparison.
 type NumericData struct {
 	Compare   NumericCompareType `json:"compare,omitempty"`
 	Qualifier fxp.Int            `json:"qualifier,omitempty"`
+	Upper     fxp.Int            `json:"upper,omitempty"`
 }
 
 // ShouldOmit implements json.Omitter.
@@ -41,9 +43,9 @@ func (n *Numeric) UnmarshalJSON(data []byte) error {
 
 // Matches performs a comparison and returns true if the data matches.
 func (n Numeric) Matches(value fxp.Int) bool {
-	return n.Compare.Matches(n.Qualifier, value)
+	return n.Compare.Matches(n.Qualifier, n.Upper, value)
 }
 
 func (n Numeric) String() string {
-	return n.Compare.Describe(n.Qualifier)
+	return n.Compare.Describe(n.Qualifier, n.Upper)
 }
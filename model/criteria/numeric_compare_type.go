@@ -12,6 +12,7 @@
 package criteria
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model/fxp"
@@ -25,6 +26,7 @@ const (
 	NotEquals = NumericCompareType("is_not")
 	AtLeast   = NumericCompareType("at_least")
 	AtMost    = NumericCompareType("at_most")
+	Between   = NumericCompareType("between")
 )
 
 // AllNumericCompareTypes is the complete set of NumericCompareType values.
@@ -34,6 +36,7 @@ var AllNumericCompareTypes = []NumericCompareType{
 	NotEquals,
 	AtLeast,
 	AtMost,
+	Between,
 }
 
 // NumericCompareType holds the type for a numeric comparison.
@@ -62,22 +65,31 @@ func (n NumericCompareType) String() string {
 		return i18n.Text("is at least")
 	case AtMost:
 		return i18n.Text("is at most")
+	case Between:
+		return i18n.Text("is between")
 	default:
 		return AnyNumber.String()
 	}
 }
 
-// Describe returns a description of this NumericCompareType using a qualifier.
-func (n NumericCompareType) Describe(qualifier fxp.Int) string {
+// Describe returns a description of this NumericCompareType using a qualifier. The upper qualifier is only used by the
+// Between comparison.
+func (n NumericCompareType) Describe(qualifier, upper fxp.Int) string {
 	v := n.EnsureValid()
-	if v == AnyNumber {
+	switch v {
+	case AnyNumber:
 		return v.String()
+	case Between:
+		lower, higher := orderedRange(qualifier, upper)
+		return fmt.Sprintf(i18n.Text("%s %s and %s"), v.String(), lower.String(), higher.String())
+	default:
+		return v.String() + " " + qualifier.String()
 	}
-	return v.String() + " " + qualifier.String()
 }
 
-// Matches performs a comparison and returns true if the data matches.
-func (n NumericCompareType) Matches(qualifier, data fxp.Int) bool {
+// Matches performs a comparison and returns true if the data matches. The upper qualifier is only used by the Between
+// comparison, which includes both ends of the range.
+func (n NumericCompareType) Matches(qualifier, upper, data fxp.Int) bool {
 	switch n {
 	case AnyNumber:
 		return true
@@ -89,9 +101,19 @@ func (n NumericCompareType) Matches(qualifier, data fxp.Int) bool {
 		return data >= qualifier
 	case AtMost:
 		return data <= qualifier
+	case Between:
+		lower, higher := orderedRange(qualifier, upper)
+		return data >= lower && data <= higher
 	default:
-		return AnyNumber.Matches(qualifier, data)
+		return AnyNumber.Matches(qualifier, upper, data)
+	}
+}
+
+func orderedRange(a, b fxp.Int) (lower, upper fxp.Int) {
+	if a > b {
+		return b, a
 	}
+	return a, b
 }
 
 // ExtractNumericCompareTypeIndex extracts the index from a string.
@@ -0,0 +1,63 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package criteria_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/criteria"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/measure"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumericBetweenMatching(t *testing.T) {
+	for i, one := range []struct {
+		lower    fxp.Int
+		upper    fxp.Int
+		data     fxp.Int
+		expected bool
+	}{
+		{fxp.From(2), fxp.From(5), fxp.From(2), true},
+		{fxp.From(2), fxp.From(5), fxp.From(5), true},
+		{fxp.From(2), fxp.From(5), fxp.From(3), true},
+		{fxp.From(2), fxp.From(5), fxp.From(1), false},
+		{fxp.From(2), fxp.From(5), fxp.From(6), false},
+		{fxp.From(5), fxp.From(2), fxp.From(3), true},
+		{fxp.From(5), fxp.From(2), fxp.From(6), false},
+		{fxp.From(3), fxp.From(3), fxp.From(3), true},
+	} {
+		assert.Equal(t, one.expected, criteria.Between.Matches(one.lower, one.upper, one.data), "index %d", i)
+		n := criteria.Numeric{NumericData: criteria.NumericData{
+			Compare:   criteria.Between,
+			Qualifier: one.lower,
+			Upper:     one.upper,
+		}}
+		assert.Equal(t, one.expected, n.Matches(one.data), "index %d", i)
+	}
+}
+
+func TestNumericUpperIgnoredOutsideBetween(t *testing.T) {
+	assert.True(t, criteria.AtLeast.Matches(fxp.From(3), fxp.From(1), fxp.From(4)))
+	assert.False(t, criteria.AtMost.Matches(fxp.From(3), fxp.From(10), fxp.From(4)))
+	assert.True(t, criteria.Equals.Matches(fxp.From(3), fxp.From(10), fxp.From(3)))
+}
+
+func TestWeightBetweenMatching(t *testing.T) {
+	w := criteria.Weight{WeightData: criteria.WeightData{
+		Compare:   criteria.Between,
+		Qualifier: measure.WeightFromInteger(2, measure.Pound),
+		Upper:     measure.WeightFromInteger(10, measure.Pound),
+	}}
+	assert.True(t, w.Matches(measure.WeightFromInteger(10, measure.Pound)))
+	assert.False(t, w.Matches(measure.WeightFromInteger(11, measure.Pound)))
+}
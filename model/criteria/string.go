@@ -54,14 +54,10 @@ func (s String) MatchesList(value ...string) bool {
 			matches++
 		}
 	}
-	switch s.Compare {
-	case Any, Is, Contains, StartsWith, EndsWith:
-		return matches > 0
-	case IsNot, DoesNotContain, DoesNotStartWith, DoesNotEndWith:
+	if s.Compare.IsNegated() {
 		return matches == len(value)
-	default:
-		return matches > 0
 	}
+	return matches > 0
 }
 
 func (s String) String() string {
@@ -12,24 +12,46 @@
 package criteria
 
 import (
+	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/richardwilkes/toolbox/i18n"
 )
 
 // Possible StringCompareType values.
 const (
-	Any              = StringCompareType("")
-	Is               = StringCompareType("is")
-	IsNot            = StringCompareType("is_not")
-	Contains         = StringCompareType("contains")
-	DoesNotContain   = StringCompareType("does_not_contain")
-	StartsWith       = StringCompareType("starts_with")
-	DoesNotStartWith = StringCompareType("does_not_start_with")
-	EndsWith         = StringCompareType("ends_with")
-	DoesNotEndWith   = StringCompareType("does_not_end_with")
+	Any               = StringCompareType("")
+	Is                = StringCompareType("is")
+	IsNot             = StringCompareType("is_not")
+	Contains          = StringCompareType("contains")
+	DoesNotContain    = StringCompareType("does_not_contain")
+	StartsWith        = StringCompareType("starts_with")
+	DoesNotStartWith  = StringCompareType("does_not_start_with")
+	EndsWith          = StringCompareType("ends_with")
+	DoesNotEndWith    = StringCompareType("does_not_end_with")
+	MatchesRegex      = StringCompareType("matches_regex")
+	DoesNotMatchRegex = StringCompareType("does_not_match_regex")
+	IsOneOf           = StringCompareType("is_one_of")
+	IsNotOneOf        = StringCompareType("is_not_one_of")
 )
 
+// OneOfSeparator separates the choices in the qualifier for the IsOneOf and IsNotOneOf comparisons.
+const OneOfSeparator = ","
+
+// maxRegexCacheSize is the number of compiled regular expressions that will be held before the cache is flushed.
+const maxRegexCacheSize = 256
+
+var (
+	regexCacheLock sync.Mutex
+	regexCache     = make(map[string]*compiledRegex)
+)
+
+type compiledRegex struct {
+	re  *regexp.Regexp
+	err error
+}
+
 // AllStringCompareTypes is the complete set of StringCompareType values.
 var AllStringCompareTypes = []StringCompareType{
 	Any,
@@ -41,6 +63,10 @@ var AllStringCompareTypes = []StringCompareType{
 	DoesNotStartWith,
 	EndsWith,
 	DoesNotEndWith,
+	MatchesRegex,
+	DoesNotMatchRegex,
+	IsOneOf,
+	IsNotOneOf,
 }
 
 // StringCompareType holds the type for a string comparison.
@@ -77,11 +103,39 @@ func (s StringCompareType) String() string {
 		return i18n.Text("ends with")
 	case DoesNotEndWith:
 		return i18n.Text("does not end with")
+	case MatchesRegex:
+		return i18n.Text("matches regex")
+	case DoesNotMatchRegex:
+		return i18n.Text("does not match regex")
+	case IsOneOf:
+		return i18n.Text("is one of")
+	case IsNotOneOf:
+		return i18n.Text("is not one of")
 	default:
 		return Any.String()
 	}
 }
 
+// IsNegated returns true if this is one of the "not" comparisons.
+func (s StringCompareType) IsNegated() bool {
+	switch s {
+	case IsNot, DoesNotContain, DoesNotStartWith, DoesNotEndWith, DoesNotMatchRegex, IsNotOneOf:
+		return true
+	default:
+		return false
+	}
+}
+
+// UsesRegex returns true if the qualifier is a regular expression.
+func (s StringCompareType) UsesRegex() bool {
+	return s == MatchesRegex || s == DoesNotMatchRegex
+}
+
+// UsesList returns true if the qualifier is a list of choices.
+func (s StringCompareType) UsesList() bool {
+	return s == IsOneOf || s == IsNotOneOf
+}
+
 // AltString provides a variant of String() for the not cases.
 func (s StringCompareType) AltString() string {
 	switch s {
@@ -93,6 +147,10 @@ func (s StringCompareType) AltString() string {
 		return i18n.Text("do not start with")
 	case DoesNotEndWith:
 		return i18n.Text("do not end with")
+	case DoesNotMatchRegex:
+		return i18n.Text("do not match regex")
+	case IsNotOneOf:
+		return i18n.Text("are not one of")
 	default:
 		return s.String()
 	}
@@ -128,11 +186,60 @@ func (s StringCompareType) Matches(qualifier, data string) bool {
 		return strings.HasSuffix(strings.ToLower(data), strings.ToLower(qualifier))
 	case DoesNotEndWith:
 		return !strings.HasSuffix(strings.ToLower(data), strings.ToLower(qualifier))
+	case MatchesRegex:
+		re, err := CompileRegex(qualifier)
+		return err == nil && re.MatchString(data)
+	case DoesNotMatchRegex:
+		re, err := CompileRegex(qualifier)
+		return err == nil && !re.MatchString(data)
+	case IsOneOf:
+		return isOneOf(qualifier, data)
+	case IsNotOneOf:
+		return !isOneOf(qualifier, data)
 	default:
 		return Any.Matches(qualifier, data)
 	}
 }
 
+// CompileRegex compiles the qualifier used by the regular expression comparisons. Matching is done without regard to
+// case. Results are cached, since the same qualifiers are evaluated repeatedly. The cache is bounded, being flushed once
+// it fills, so that qualifiers typed in one character at a time don't accumulate without limit.
+func CompileRegex(qualifier string) (*regexp.Regexp, error) {
+	regexCacheLock.Lock()
+	defer regexCacheLock.Unlock()
+	if cached, ok := regexCache[qualifier]; ok {
+		return cached.re, cached.err
+	}
+	re, err := regexp.Compile("(?i)" + qualifier)
+	if len(regexCache) >= maxRegexCacheSize {
+		regexCache = make(map[string]*compiledRegex)
+	}
+	regexCache[qualifier] = &compiledRegex{re: re, err: err}
+	return re, err
+}
+
+// SplitOneOf splits the qualifier used by the IsOneOf and IsNotOneOf comparisons into its choices.
+func SplitOneOf(qualifier string) []string {
+	parts := strings.Split(qualifier, OneOfSeparator)
+	list := make([]string, 0, len(parts))
+	for _, one := range parts {
+		if one = strings.TrimSpace(one); one != "" {
+			list = append(list, one)
+		}
+	}
+	return list
+}
+
+func isOneOf(qualifier, data string) bool {
+	data = strings.TrimSpace(data)
+	for _, one := range SplitOneOf(qualifier) {
+		if strings.EqualFold(one, data) {
+			return true
+		}
+	}
+	return false
+}
+
 // ExtractStringCompareTypeIndex extracts the index from a string.
 func ExtractStringCompareTypeIndex(str string) int {
 	for i, one := range AllStringCompareTypes {
@@ -147,7 +254,7 @@ func ExtractStringCompareTypeIndex(str string) int {
 func PrefixedStringCompareTypeChoices(prefix, notPrefix string) []string {
 	choices := make([]string, len(AllStringCompareTypes))
 	for i, choice := range AllStringCompareTypes {
-		if prefix == notPrefix || choice == Any || choice == Is || choice == Contains || choice == StartsWith || choice == EndsWith {
+		if prefix == notPrefix || !choice.IsNegated() {
 			choices[i] = prefix + " " + choice.String()
 		} else {
 			choices[i] = notPrefix + " " + choice.AltString()
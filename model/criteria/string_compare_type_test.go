@@ -0,0 +1,77 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package criteria_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/criteria"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringRegexMatching(t *testing.T) {
+	for i, one := range []struct {
+		compare   criteria.StringCompareType
+		qualifier string
+		data      string
+		expected  bool
+	}{
+		{criteria.MatchesRegex, "^broad", "Broadsword", true},
+		{criteria.MatchesRegex, "sword$", "Broadsword", true},
+		{criteria.MatchesRegex, "^sword", "Broadsword", false},
+		{criteria.MatchesRegex, "axe|mace", "Mace", true},
+		{criteria.MatchesRegex, "[", "[", false},
+		{criteria.DoesNotMatchRegex, "^broad", "Broadsword", false},
+		{criteria.DoesNotMatchRegex, "^sword", "Broadsword", true},
+		{criteria.DoesNotMatchRegex, "[", "[", false},
+	} {
+		assert.Equal(t, one.expected, one.compare.Matches(one.qualifier, one.data), "index %d", i)
+	}
+}
+
+func TestStringOneOfMatching(t *testing.T) {
+	for i, one := range []struct {
+		compare   criteria.StringCompareType
+		qualifier string
+		data      string
+		expected  bool
+	}{
+		{criteria.IsOneOf, "Axe, Mace, Broadsword", "mace", true},
+		{criteria.IsOneOf, "Axe, Mace, Broadsword", " Axe ", true},
+		{criteria.IsOneOf, "Axe, Mace, Broadsword", "Sword", false},
+		{criteria.IsOneOf, "Axe,,", "", false},
+		{criteria.IsNotOneOf, "Axe, Mace", "Mace", false},
+		{criteria.IsNotOneOf, "Axe, Mace", "Spear", true},
+	} {
+		assert.Equal(t, one.expected, one.compare.Matches(one.qualifier, one.data), "index %d", i)
+	}
+	assert.Equal(t, []string{"Axe", "Mace"}, criteria.SplitOneOf(" Axe,, Mace ,"))
+}
+
+func TestCompileRegex(t *testing.T) {
+	re, err := criteria.CompileRegex("^a+$")
+	assert.NoError(t, err)
+	again, err := criteria.CompileRegex("^a+$")
+	assert.NoError(t, err)
+	assert.Same(t, re, again)
+	assert.True(t, re.MatchString("AAA"))
+	_, err = criteria.CompileRegex("(")
+	assert.Error(t, err)
+	_, err = criteria.CompileRegex("(")
+	assert.Error(t, err)
+	for i := 0; i < 1000; i++ {
+		re, err = criteria.CompileRegex("^" + strconv.Itoa(i) + "$")
+		assert.NoError(t, err)
+		assert.True(t, re.MatchString(strconv.Itoa(i)))
+	}
+}
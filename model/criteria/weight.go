@@ -22,10 +22,12 @@ type Weight struct {
 	WeightData
 }
 
-// WeightData holds the criteria for matching a number that should be written to disk.
+// WeightData holds the criteria for matching a number that should be written to disk. Upper is only used by the
+// Between comparison.
 type WeightData struct {
 	Compare   NumericCompareType `json:"compare,omitempty"`
 	Qualifier measure.Weight     `json:"qualifier,omitempty"`
+	Upper     measure.Weight     `json:"upper,omitempty"`
 }
 
 // ShouldOmit implements json.Omitter.
@@ -42,9 +44,9 @@ func (w *Weight) UnmarshalJSON(data []byte) error {
 
 // Matches performs a comparison and returns true if the data matches.
 func (w Weight) Matches(value measure.Weight) bool {
-	return w.Compare.Matches(fxp.Int(w.Qualifier), fxp.Int(value))
+	return w.Compare.Matches(fxp.Int(w.Qualifier), fxp.Int(w.Upper), fxp.Int(value))
 }
 
 func (w Weight) String() string {
-	return w.Compare.Describe(fxp.Int(w.Qualifier))
+	return w.Compare.Describe(fxp.Int(w.Qualifier), fxp.Int(w.Upper))
 }
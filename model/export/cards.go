@@ -0,0 +1,179 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package export
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/weapon"
+	"github.com/richardwilkes/gcs/v5/model/paper"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// CardOptions holds the options for producing cards.
+type CardOptions struct {
+	Size      paper.CardSize
+	PaperSize paper.Size
+	Spells    bool
+	Powers    bool
+	Equipment bool
+}
+
+// Card holds the content of a single card.
+type Card struct {
+	Kind     string
+	Title    string
+	Subtitle string
+	Fields   []CardField
+	Weapons  []CardField
+	Notes    string
+}
+
+// CardField holds a labeled value on a card.
+type CardField struct {
+	Label string
+	Value string
+}
+
+// Cards returns the cards for the entity. Spells produce one card each, as do traits that carry weapons (powers) and
+// equipment. The text is taken from the same cell data the sheet displays, so the entity should have been
+// recalculated beforehand.
+func Cards(entity *gurps.Entity, options CardOptions) []*Card {
+	var cards []*Card
+	if options.Spells {
+		gurps.Traverse(func(s *gurps.Spell) bool {
+			cards = append(cards, spellCard(s))
+			return false
+		}, true, true, entity.Spells...)
+	}
+	if options.Powers {
+		gurps.Traverse(func(t *gurps.Trait) bool {
+			if len(t.Weapons) != 0 {
+				cards = append(cards, traitCard(t))
+			}
+			return false
+		}, true, true, entity.Traits...)
+	}
+	if options.Equipment {
+		for _, list := range [][]*gurps.Equipment{entity.CarriedEquipment, entity.OtherEquipment} {
+			gurps.Traverse(func(e *gurps.Equipment) bool {
+				cards = append(cards, equipmentCard(e))
+				return false
+			}, false, false, list...)
+		}
+	}
+	return cards
+}
+
+func cardCell[T gurps.NodeTypes](node T, column int) *gurps.CellData {
+	var data gurps.CellData
+	gurps.AsNode(node).CellData(column, &data)
+	return &data
+}
+
+// appendCardField adds the field for the column, if it has a value.
+func appendCardField[T gurps.NodeTypes](fields []CardField, node T, label string, column int) []CardField {
+	if value := strings.TrimSpace(cardCell(node, column).Primary); value != "" && value != "-" {
+		fields = append(fields, CardField{Label: label, Value: value})
+	}
+	return fields
+}
+
+func spellCard(s *gurps.Spell) *Card {
+	desc := cardCell(s, gurps.SpellDescriptionColumn)
+	card := &Card{
+		Kind:     i18n.Text("Spell"),
+		Title:    desc.Primary,
+		Subtitle: cardCell(s, gurps.SpellCollegeColumn).Primary,
+		Notes:    desc.Secondary,
+	}
+	card.Fields = appendCardField(card.Fields, s, i18n.Text("Class"), gurps.SpellClassColumn)
+	card.Fields = appendCardField(card.Fields, s, i18n.Text("Cost"), gurps.SpellCastCostColumn)
+	card.Fields = appendCardField(card.Fields, s, i18n.Text("Maintain"), gurps.SpellMaintainCostColumn)
+	card.Fields = appendCardField(card.Fields, s, i18n.Text("Time"), gurps.SpellCastTimeColumn)
+	card.Fields = appendCardField(card.Fields, s, i18n.Text("Duration"), gurps.SpellDurationColumn)
+	card.Fields = appendCardField(card.Fields, s, i18n.Text("Resist"), gurps.SpellResistColumn)
+	card.Fields = appendCardField(card.Fields, s, i18n.Text("Level"), gurps.SpellLevelColumn)
+	card.Fields = appendCardField(card.Fields, s, i18n.Text("Relative"), gurps.SpellRelativeLevelColumn)
+	card.Weapons = cardWeapons(s.Weapons)
+	return card
+}
+
+func traitCard(t *gurps.Trait) *Card {
+	desc := cardCell(t, gurps.TraitDescriptionColumn)
+	card := &Card{
+		Kind:  i18n.Text("Power"),
+		Title: desc.Primary,
+		Notes: desc.Secondary,
+	}
+	card.Fields = appendCardField(card.Fields, t, i18n.Text("Points"), gurps.TraitPointsColumn)
+	card.Weapons = cardWeapons(t.Weapons)
+	return card
+}
+
+func equipmentCard(e *gurps.Equipment) *Card {
+	desc := cardCell(e, gurps.EquipmentDescriptionColumn)
+	card := &Card{
+		Kind:  i18n.Text("Equipment"),
+		Title: desc.Primary,
+		Notes: desc.Secondary,
+	}
+	card.Fields = appendCardField(card.Fields, e, i18n.Text("Quantity"), gurps.EquipmentQuantityColumn)
+	card.Fields = appendCardField(card.Fields, e, i18n.Text("Weight"), gurps.EquipmentExtendedWeightColumn)
+	card.Fields = appendCardField(card.Fields, e, i18n.Text("Value"), gurps.EquipmentExtendedCostColumn)
+	card.Fields = appendCardField(card.Fields, e, i18n.Text("TL"), gurps.EquipmentTLColumn)
+	card.Fields = appendCardField(card.Fields, e, i18n.Text("LC"), gurps.EquipmentLCColumn)
+	if e.MaxUses > 0 {
+		card.Fields = appendCardField(card.Fields, e, i18n.Text("Uses"), gurps.EquipmentUsesColumn)
+	}
+	card.Weapons = cardWeapons(e.Weapons)
+	return card
+}
+
+// cardWeapons returns a one line summary of each weapon, labeled with its usage.
+func cardWeapons(list []*gurps.Weapon) []CardField {
+	var fields []CardField
+	for _, w := range list {
+		var columns []CardField
+		if w.Type == weapon.Melee {
+			columns = []CardField{
+				{Label: i18n.Text("SL"), Value: cardCell(w, gurps.WeaponSLColumn).Primary},
+				{Label: i18n.Text("Dmg"), Value: cardCell(w, gurps.WeaponDamageColumn).Primary},
+				{Label: i18n.Text("Reach"), Value: cardCell(w, gurps.WeaponReachColumn).Primary},
+				{Label: i18n.Text("Parry"), Value: cardCell(w, gurps.WeaponParryColumn).Primary},
+				{Label: i18n.Text("Block"), Value: cardCell(w, gurps.WeaponBlockColumn).Primary},
+			}
+		} else {
+			columns = []CardField{
+				{Label: i18n.Text("SL"), Value: cardCell(w, gurps.WeaponSLColumn).Primary},
+				{Label: i18n.Text("Dmg"), Value: cardCell(w, gurps.WeaponDamageColumn).Primary},
+				{Label: i18n.Text("Acc"), Value: cardCell(w, gurps.WeaponAccColumn).Primary},
+				{Label: i18n.Text("Range"), Value: cardCell(w, gurps.WeaponRangeColumn).Primary},
+				{Label: i18n.Text("RoF"), Value: cardCell(w, gurps.WeaponRoFColumn).Primary},
+				{Label: i18n.Text("Shots"), Value: cardCell(w, gurps.WeaponShotsColumn).Primary},
+			}
+		}
+		parts := make([]string, 0, len(columns))
+		for _, one := range columns {
+			if value := strings.TrimSpace(one.Value); value != "" && value != "-" {
+				parts = append(parts, one.Label+" "+value)
+			}
+		}
+		usage := w.Usage
+		if usage == "" {
+			usage = w.Type.String()
+		}
+		fields = append(fields, CardField{Label: usage, Value: strings.Join(parts, ", ")})
+	}
+	return fields
+}
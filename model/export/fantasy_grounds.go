@@ -0,0 +1,414 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/gid"
+	"github.com/richardwilkes/gcs/v5/model/gurps/measure"
+	"github.com/richardwilkes/gcs/v5/model/gurps/trait"
+	"github.com/richardwilkes/gcs/v5/model/gurps/weapon"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/log/jot"
+	"github.com/richardwilkes/toolbox/xio/fs"
+)
+
+// FantasyGroundsExt is the file extension used for Fantasy Grounds character exports.
+const FantasyGroundsExt = ".xml"
+
+// fgAttributes maps attribute IDs to the element names used by the Fantasy Grounds GURPS ruleset.
+var fgAttributes = []struct {
+	attrID string
+	tag    string
+}{
+	{attrID: gid.Strength, tag: "strength"},
+	{attrID: gid.Dexterity, tag: "dexterity"},
+	{attrID: gid.Intelligence, tag: "intelligence"},
+	{attrID: gid.Health, tag: "health"},
+	{attrID: gid.HitPoints, tag: "hitpoints"},
+	{attrID: gid.Will, tag: "will"},
+	{attrID: gid.Perception, tag: "perception"},
+	{attrID: gid.FatiguePoints, tag: "fatiguepoints"},
+	{attrID: gid.BasicSpeed, tag: "basicspeed"},
+	{attrID: gid.BasicMove, tag: "basicmove"},
+}
+
+type fgWriter struct {
+	entity *gurps.Entity
+	buffer strings.Builder
+	depth  int
+}
+
+// ToFantasyGrounds exports the files to the Fantasy Grounds GURPS ruleset XML format, writing each one alongside its
+// source file.
+func ToFantasyGrounds(fileList []string) error {
+	for _, one := range fileList {
+		switch strings.ToLower(filepath.Ext(one)) {
+		case library.SheetExt:
+			entity, err := gurps.NewEntityFromFile(os.DirFS(filepath.Dir(one)), filepath.Base(one))
+			if err != nil {
+				return err
+			}
+			if err = ToFantasyGroundsFile(entity, fs.TrimExtension(one)+FantasyGroundsExt); err != nil {
+				return err
+			}
+		default:
+			jot.Warn("ignoring: " + one)
+		}
+	}
+	return nil
+}
+
+// ToFantasyGroundsFile writes the entity to a file in the Fantasy Grounds GURPS ruleset XML format.
+func ToFantasyGroundsFile(entity *gurps.Entity, filePath string) error {
+	if err := os.WriteFile(filePath, FantasyGrounds(entity), 0o640); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
+
+// FantasyGrounds produces the entity in the Fantasy Grounds GURPS ruleset XML format. The entity should have been
+// recalculated beforehand.
+func FantasyGrounds(entity *gurps.Entity) []byte {
+	w := &fgWriter{entity: entity}
+	w.buffer.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	w.buffer.WriteString(`<root version="4.1">` + "\n")
+	w.depth++
+	w.open("character")
+	w.text("name", entity.Profile.Name)
+	w.text("playername", entity.Profile.PlayerName)
+	w.number("totalpoints", entity.TotalPoints)
+	w.number("unspentpoints", entity.UnspentPoints())
+	w.attributes()
+	w.traits()
+	w.abilities()
+	w.combat()
+	w.inventory()
+	w.notes()
+	w.close("character")
+	w.depth--
+	w.buffer.WriteString("</root>\n")
+	return []byte(w.buffer.String())
+}
+
+func (w *fgWriter) indent() {
+	for i := 0; i < w.depth; i++ {
+		w.buffer.WriteByte('\t')
+	}
+}
+
+func (w *fgWriter) open(tag string) {
+	w.indent()
+	fmt.Fprintf(&w.buffer, "<%s>\n", tag)
+	w.depth++
+}
+
+func (w *fgWriter) close(tag string) {
+	w.depth--
+	w.indent()
+	fmt.Fprintf(&w.buffer, "</%s>\n", tag)
+}
+
+func (w *fgWriter) value(tag, kind, value string) {
+	w.indent()
+	fmt.Fprintf(&w.buffer, `<%s type="%s">`, tag, kind)
+	if err := xml.EscapeText(&w.buffer, []byte(value)); err != nil {
+		jot.Error(errs.Wrap(err))
+	}
+	fmt.Fprintf(&w.buffer, "</%s>\n", tag)
+}
+
+func (w *fgWriter) text(tag, value string) {
+	w.value(tag, "string", value)
+}
+
+func (w *fgWriter) number(tag string, value fxp.Int) {
+	w.value(tag, "number", value.String())
+}
+
+func (w *fgWriter) formattedText(tag, value string) {
+	w.indent()
+	fmt.Fprintf(&w.buffer, `<%s type="formattedtext">`, tag)
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			w.buffer.WriteString("<p>")
+			if err := xml.EscapeText(&w.buffer, []byte(line)); err != nil {
+				jot.Error(errs.Wrap(err))
+			}
+			w.buffer.WriteString("</p>")
+		}
+	}
+	fmt.Fprintf(&w.buffer, "</%s>\n", tag)
+}
+
+// list writes the elements of a Fantasy Grounds list, each of which is wrapped in a uniquely numbered element.
+func (w *fgWriter) list(tag string, count int, f func(i int)) {
+	w.open(tag)
+	for i := 0; i < count; i++ {
+		id := fmt.Sprintf("id-%05d", i+1)
+		w.open(id)
+		f(i)
+		w.close(id)
+	}
+	w.close(tag)
+}
+
+func (w *fgWriter) weight(tag string, weight measure.Weight) {
+	w.number(tag, fxp.Int(weight))
+}
+
+func (w *fgWriter) attributes() {
+	w.open("attributes")
+	for _, one := range fgAttributes {
+		attr := w.entity.ResolveAttribute(one.attrID)
+		if attr == nil {
+			continue
+		}
+		w.number(one.tag, attr.Maximum())
+		w.number(one.tag+"_points", attr.PointCost())
+		switch one.attrID {
+		case gid.HitPoints:
+			w.number("hps", attr.Current())
+		case gid.FatiguePoints:
+			w.number("fps", attr.Current())
+		}
+	}
+	enc := w.entity.EncumbranceLevel(false)
+	w.number("move", fxp.From(w.entity.Move(enc)))
+	w.text("thrust", w.entity.Thrust().String())
+	w.text("swing", w.entity.Swing().String())
+	w.weight("basiclift", w.entity.BasicLift())
+	w.close("attributes")
+}
+
+func (w *fgWriter) traits() {
+	var advantages, disadvantages []*gurps.Trait
+	var walk func(list []*gurps.Trait)
+	walk = func(list []*gurps.Trait) {
+		for _, t := range list {
+			if !t.Enabled() {
+				continue
+			}
+			if t.Container() && t.ContainerType == trait.Group {
+				walk(t.Children)
+				continue
+			}
+			if t.AdjustedPoints() < 0 {
+				disadvantages = append(disadvantages, t)
+			} else {
+				advantages = append(advantages, t)
+			}
+		}
+	}
+	walk(w.entity.Traits)
+	w.open("traits")
+	for _, one := range []struct {
+		tag  string
+		list []*gurps.Trait
+	}{
+		{tag: "adslist", list: advantages},
+		{tag: "disadslist", list: disadvantages},
+	} {
+		list := one.list
+		w.list(one.tag, len(list), func(i int) {
+			t := list[i]
+			name := t.String()
+			if notes := t.ModifierNotes(); notes != "" {
+				name += " (" + notes + ")"
+			}
+			w.text("name", name)
+			w.number("points", t.AdjustedPoints())
+			w.formattedText("text", t.Notes())
+		})
+	}
+	w.close("traits")
+}
+
+func (w *fgWriter) abilities() {
+	var skills []*gurps.Skill
+	gurps.Traverse(func(s *gurps.Skill) bool {
+		skills = append(skills, s)
+		return false
+	}, true, true, w.entity.Skills...)
+	var spells []*gurps.Spell
+	gurps.Traverse(func(s *gurps.Spell) bool {
+		spells = append(spells, s)
+		return false
+	}, true, true, w.entity.Spells...)
+	w.open("abilities")
+	w.list("skilllist", len(skills), func(i int) {
+		s := skills[i]
+		w.text("name", s.String())
+		w.text("type", s.Difficulty.Description(w.entity))
+		w.number("level", s.LevelData.Level.Trunc())
+		w.text("relativelevel", s.RelativeLevel())
+		w.number("points", s.AdjustedPoints(nil))
+		w.formattedText("text", s.Notes())
+	})
+	w.list("spelllist", len(spells), func(i int) {
+		s := spells[i]
+		w.text("name", s.String())
+		w.text("type", s.Difficulty.Description(w.entity))
+		w.text("class", s.Class)
+		w.text("college", strings.Join(s.College, ", "))
+		w.text("resist", s.Resist)
+		w.text("costmaintain", strings.Trim(s.CastingCost+"/"+s.MaintenanceCost, "/"))
+		w.text("time", s.CastingTime)
+		w.text("duration", s.Duration)
+		w.number("level", s.LevelData.Level.Trunc())
+		w.text("relativelevel", s.RelativeLevel())
+		w.number("points", s.AdjustedPoints(nil))
+		w.formattedText("text", s.Notes())
+	})
+	w.close("abilities")
+}
+
+// groupWeapons groups the weapons by their owner, since Fantasy Grounds lists each of a weapon's modes under a single
+// entry.
+func groupWeapons(list []*gurps.Weapon) [][]*gurps.Weapon {
+	var groups [][]*gurps.Weapon
+	index := make(map[string]int)
+	for _, one := range list {
+		name := one.String()
+		if i, exists := index[name]; exists {
+			groups[i] = append(groups[i], one)
+		} else {
+			index[name] = len(groups)
+			groups = append(groups, []*gurps.Weapon{one})
+		}
+	}
+	return groups
+}
+
+func (w *fgWriter) weaponHeader(first *gurps.Weapon) {
+	w.text("name", first.String())
+	if st := first.ResolvedMinimumStrength(); st > 0 {
+		w.number("st", st)
+	}
+	if eqp, ok := first.Owner.(*gurps.Equipment); ok {
+		w.weight("weight", eqp.AdjustedWeight(false, w.entity.SheetSettings.DefaultWeightUnits))
+		w.number("cost", eqp.AdjustedValue())
+		w.text("lc", eqp.LegalityClass)
+		w.text("tl", eqp.TechLevel)
+	}
+	w.formattedText("notes", first.Notes())
+}
+
+func (w *fgWriter) combat() {
+	w.open("combat")
+	w.number("dodge", fxp.From(w.entity.Dodge(w.entity.EncumbranceLevel(false))))
+	melee := groupWeapons(w.entity.EquippedWeapons(weapon.Melee))
+	w.list("meleecombatlist", len(melee), func(i int) {
+		w.weaponHeader(melee[i][0])
+		w.list("meleemodelist", len(melee[i]), func(j int) {
+			one := melee[i][j]
+			w.text("name", one.Usage)
+			w.number("level", one.SkillLevel(nil).Trunc())
+			w.text("damage", one.Damage.ResolvedDamage(nil))
+			w.text("reach", one.ResolvedReach())
+			w.text("parry", one.ResolvedParry(nil))
+			w.text("block", one.ResolvedBlock(nil))
+		})
+	})
+	ranged := groupWeapons(w.entity.EquippedWeapons(weapon.Ranged))
+	w.list("rangedcombatlist", len(ranged), func(i int) {
+		w.weaponHeader(ranged[i][0])
+		w.list("rangedmodelist", len(ranged[i]), func(j int) {
+			one := ranged[i][j]
+			w.text("name", one.Usage)
+			w.number("level", one.SkillLevel(nil).Trunc())
+			w.text("damage", one.Damage.ResolvedDamage(nil))
+			w.text("acc", one.ResolvedAccuracy())
+			w.text("range", one.ResolvedRange())
+			w.text("rof", one.ResolvedRateOfFire())
+			w.text("shots", one.ShotsText())
+			w.text("bulk", one.ResolvedBulk())
+			w.text("rcl", one.ResolvedRecoil())
+		})
+	})
+	locations := w.entity.SheetSettings.BodyType.Locations
+	w.list("hitlocations", len(locations), func(i int) {
+		loc := locations[i]
+		w.text("location", loc.TableName)
+		w.text("roll", loc.RollRange)
+		w.text("penalty", strconv.Itoa(loc.HitPenalty))
+		w.text("dr", loc.DisplayDR(w.entity, nil))
+	})
+	w.close("combat")
+}
+
+type fgInventoryItem struct {
+	equipment *gurps.Equipment
+	location  string
+	carried   int
+}
+
+func (w *fgWriter) inventory() {
+	var items []fgInventoryItem
+	var walk func(list []*gurps.Equipment, location string, carried bool)
+	walk = func(list []*gurps.Equipment, location string, carried bool) {
+		for _, one := range list {
+			item := fgInventoryItem{
+				equipment: one,
+				location:  location,
+			}
+			if carried {
+				item.carried = 1
+				if one.Equipped {
+					item.carried = 2
+				}
+			}
+			items = append(items, item)
+			if one.HasChildren() {
+				walk(one.Children, one.Description(), carried)
+			}
+		}
+	}
+	walk(w.entity.CarriedEquipment, "", true)
+	walk(w.entity.OtherEquipment, "", false)
+	defUnits := w.entity.SheetSettings.DefaultWeightUnits
+	w.list("inventorylist", len(items), func(i int) {
+		item := items[i]
+		eqp := item.equipment
+		w.text("name", eqp.Description())
+		w.number("count", eqp.Quantity)
+		w.weight("weight", eqp.AdjustedWeight(false, defUnits))
+		w.number("cost", eqp.AdjustedValue())
+		w.text("location", item.location)
+		w.number("carried", fxp.From(item.carried))
+		w.text("tl", eqp.TechLevel)
+		w.text("lc", eqp.LegalityClass)
+		w.formattedText("notes", eqp.Notes())
+	})
+	w.weight("totalweight", w.entity.WeightCarried(false))
+}
+
+func (w *fgWriter) notes() {
+	var buffer strings.Builder
+	gurps.Traverse(func(n *gurps.Note) bool {
+		if buffer.Len() != 0 {
+			buffer.WriteByte('\n')
+		}
+		buffer.WriteString(n.Text)
+		return false
+	}, false, true, w.entity.Notes...)
+	w.formattedText("notes", buffer.String())
+}
@@ -0,0 +1,402 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package export
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/attribute"
+	"github.com/richardwilkes/gcs/v5/model/gurps/weapon"
+	"github.com/richardwilkes/gcs/v5/model/theme"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xio"
+	"github.com/richardwilkes/unison"
+)
+
+// HTMLExt is the file extension used for interactive HTML exports.
+const HTMLExt = ".html"
+
+// htmlColumn describes a column of one of the lists in the HTML export.
+type htmlColumn struct {
+	title  string
+	column int
+	width  string
+}
+
+type htmlWriter struct {
+	entity *gurps.Entity
+	buffer strings.Builder
+}
+
+// ToHTMLFile writes the entity to a file as a self-contained, interactive HTML page.
+func ToHTMLFile(entity *gurps.Entity, filePath string) error {
+	if err := os.WriteFile(filePath, []byte(HTML(entity)), 0o640); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
+
+// HTML produces a self-contained, interactive HTML page for the entity. Containers may be expanded and collapsed,
+// values that have a breakdown on the sheet reveal it when hovered or tapped, and the page's colors and fonts follow
+// the current theme. No scripting is required. The entity should have been recalculated beforehand.
+func HTML(entity *gurps.Entity) string {
+	h := &htmlWriter{entity: entity}
+	h.buffer.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	h.buffer.WriteString(`<meta name="viewport" content="width=device-width, initial-scale=1">` + "\n")
+	fmt.Fprintf(&h.buffer, "<title>%s</title>\n", html.EscapeString(entity.Profile.Name))
+	h.buffer.WriteString("<style>\n")
+	h.stylesheet()
+	h.buffer.WriteString("</style>\n</head>\n<body>\n")
+	h.header()
+	h.attributes()
+	h.hitLocations()
+	htmlList(h, i18n.Text("Traits"), entity.Traits, []htmlColumn{
+		{title: i18n.Text("Trait"), column: gurps.TraitDescriptionColumn},
+		{title: i18n.Text("Pts"), column: gurps.TraitPointsColumn, width: "3.5em"},
+	})
+	htmlList(h, i18n.Text("Skills"), entity.Skills, []htmlColumn{
+		{title: i18n.Text("Skill"), column: gurps.SkillDescriptionColumn},
+		{title: i18n.Text("SL"), column: gurps.SkillLevelColumn, width: "2.5em"},
+		{title: i18n.Text("RSL"), column: gurps.SkillRelativeLevelColumn, width: "4em"},
+		{title: i18n.Text("Pts"), column: gurps.SkillPointsColumn, width: "3em"},
+	})
+	htmlList(h, i18n.Text("Spells"), entity.Spells, []htmlColumn{
+		{title: i18n.Text("Spell"), column: gurps.SpellDescriptionColumn},
+		{title: i18n.Text("Cost"), column: gurps.SpellCastCostColumn, width: "3.5em"},
+		{title: i18n.Text("Time"), column: gurps.SpellCastTimeColumn, width: "3.5em"},
+		{title: i18n.Text("SL"), column: gurps.SpellLevelColumn, width: "2.5em"},
+		{title: i18n.Text("Pts"), column: gurps.SpellPointsColumn, width: "3em"},
+	})
+	htmlList(h, i18n.Text("Melee Weapons"), entity.EquippedWeapons(weapon.Melee), []htmlColumn{
+		{title: i18n.Text("Melee Weapon"), column: gurps.WeaponDescriptionColumn},
+		{title: i18n.Text("Usage"), column: gurps.WeaponUsageColumn, width: "5em"},
+		{title: i18n.Text("SL"), column: gurps.WeaponSLColumn, width: "2.5em"},
+		{title: i18n.Text("Parry"), column: gurps.WeaponParryColumn, width: "3em"},
+		{title: i18n.Text("Block"), column: gurps.WeaponBlockColumn, width: "3em"},
+		{title: i18n.Text("Damage"), column: gurps.WeaponDamageColumn, width: "6em"},
+		{title: i18n.Text("Reach"), column: gurps.WeaponReachColumn, width: "3em"},
+	})
+	htmlList(h, i18n.Text("Ranged Weapons"), entity.EquippedWeapons(weapon.Ranged), []htmlColumn{
+		{title: i18n.Text("Ranged Weapon"), column: gurps.WeaponDescriptionColumn},
+		{title: i18n.Text("SL"), column: gurps.WeaponSLColumn, width: "2.5em"},
+		{title: i18n.Text("Acc"), column: gurps.WeaponAccColumn, width: "2.5em"},
+		{title: i18n.Text("Damage"), column: gurps.WeaponDamageColumn, width: "6em"},
+		{title: i18n.Text("Range"), column: gurps.WeaponRangeColumn, width: "5em"},
+		{title: i18n.Text("RoF"), column: gurps.WeaponRoFColumn, width: "2.5em"},
+		{title: i18n.Text("Shots"), column: gurps.WeaponShotsColumn, width: "3.5em"},
+		{title: i18n.Text("Bulk"), column: gurps.WeaponBulkColumn, width: "2.5em"},
+		{title: i18n.Text("Rcl"), column: gurps.WeaponRecoilColumn, width: "2.5em"},
+	})
+	equipmentColumns := []htmlColumn{
+		{title: i18n.Text("Qty"), column: gurps.EquipmentQuantityColumn, width: "2.5em"},
+		{title: i18n.Text("Equipment"), column: gurps.EquipmentDescriptionColumn},
+		{title: i18n.Text("Cost"), column: gurps.EquipmentExtendedCostColumn, width: "4em"},
+		{title: i18n.Text("Weight"), column: gurps.EquipmentExtendedWeightColumn, width: "5em"},
+	}
+	htmlList(h, i18n.Text("Carried Equipment"), entity.CarriedEquipment, append([]htmlColumn{
+		{title: "✓", column: gurps.EquipmentEquippedColumn, width: "1.5em"},
+	}, equipmentColumns...))
+	htmlList(h, i18n.Text("Other Equipment"), entity.OtherEquipment, equipmentColumns)
+	htmlList(h, i18n.Text("Notes"), entity.Notes, []htmlColumn{{title: i18n.Text("Note"), column: gurps.NoteTextColumn}})
+	h.buffer.WriteString("</body>\n</html>\n")
+	return h.buffer.String()
+}
+
+func (h *htmlWriter) stylesheet() {
+	h.buffer.WriteString(":root {\n")
+	for _, c := range theme.CurrentColors {
+		fmt.Fprintf(&h.buffer, "  --%s: %s;\n", cssName(c.ID), c.Color.Light.String())
+	}
+	for _, f := range theme.CurrentFonts {
+		fmt.Fprintf(&h.buffer, "  --font-%s: %s;\n", cssName(f.ID), cssFont(f.Font.Descriptor(), "px"))
+	}
+	h.buffer.WriteString("}\n@media screen and (prefers-color-scheme: dark) {\n  :root {\n")
+	for _, c := range theme.CurrentColors {
+		fmt.Fprintf(&h.buffer, "    --%s: %s;\n", cssName(c.ID), c.Color.Dark.String())
+	}
+	h.buffer.WriteString("  }\n}\n@media print {\n  :root {\n")
+	for _, f := range theme.CurrentFonts {
+		fmt.Fprintf(&h.buffer, "    --font-%s: %s;\n", cssName(f.ID), cssFont(f.Font.Descriptor(), "pt"))
+	}
+	h.buffer.WriteString(`  }
+  body { background: var(--page); color: var(--on-page); font: var(--font-page-field-primary); margin: 0; }
+  h1 { font: var(--font-page-label-primary); font-weight: bold; }
+  h2, .row.header { font: var(--font-page-label-primary); }
+  .secondary { font: var(--font-page-field-secondary); }
+  section { break-inside: avoid-page; }
+  details > summary::before { content: none; }
+  .tip::after { display: none !important; }
+}
+body { background: var(--page); color: var(--on-page); font: var(--font-field); margin: 0.5em; }
+h1 { font: var(--font-system-emphasized); font-size: 1.4em; margin: 0 0 0.25em 0; }
+h2 { background: var(--header); color: var(--on-header); font: var(--font-label); font-weight: bold; margin: 0;
+  padding: 0.2em 0.4em; }
+section { border: 1px solid var(--header); margin: 0 0 0.75em 0; }
+.profile { color: var(--hint); margin: 0 0 0.75em 0; }
+.row { display: grid; grid-template-columns: var(--cols); column-gap: 0.5em; padding: 0.15em 0.4em;
+  align-items: start; }
+.row.header { font: var(--font-label); color: var(--on-banding); background: var(--banding); }
+.row > span { overflow-wrap: anywhere; }
+.row > span.number { text-align: right; }
+.list > .row:nth-of-type(odd), .list details:nth-of-type(odd) > summary { background: var(--banding); }
+.secondary { display: block; font: var(--font-field-secondary); color: var(--hint); white-space: pre-wrap; }
+.dim { opacity: 0.5; }
+.unsatisfied { color: var(--error); }
+details > summary { list-style: none; cursor: pointer; }
+details > summary::-webkit-details-marker { display: none; }
+details > summary > span:first-child::before { content: "\25B8\00a0"; color: var(--accent); }
+details[open] > summary > span:first-child::before { content: "\25BE\00a0"; }
+.children { margin-left: 1em; border-left: 1px solid var(--interior-divider); }
+.tip { position: relative; text-decoration: underline dotted; cursor: help; }
+.tip:hover::after, .tip:focus::after { content: attr(data-tip); position: absolute; z-index: 10; right: 0; top: 100%;
+  min-width: 12em; max-width: 80vw; white-space: pre-wrap; text-align: left; padding: 0.4em;
+  background: var(--tooltip); color: var(--on-tooltip); border: 1px solid var(--control-edge);
+  font: var(--font-field-secondary); }
+`)
+}
+
+// cssName converts a theme ID into a name suitable for use as a CSS custom property.
+func cssName(id string) string {
+	return strings.NewReplacer("_", "-", ".", "-").Replace(id)
+}
+
+func cssFont(desc unison.FontDescriptor, units string) string {
+	var buffer strings.Builder
+	if desc.Slant != unison.NoSlant {
+		buffer.WriteString("italic ")
+	}
+	weight := 400
+	switch {
+	case desc.Weight >= unison.BlackFontWeight:
+		weight = 900
+	case desc.Weight >= unison.BoldFontWeight:
+		weight = 700
+	case desc.Weight >= unison.MediumFontWeight:
+		weight = 500
+	case desc.Weight < unison.NormalFontWeight:
+		weight = 300
+	}
+	fmt.Fprintf(&buffer, `%d %s%s "%s", sans-serif`, weight,
+		strconv.FormatFloat(float64(desc.Size), 'f', -1, 32), units, strings.ReplaceAll(desc.Family, `"`, ""))
+	return buffer.String()
+}
+
+func (h *htmlWriter) escape(text string) string {
+	return html.EscapeString(text)
+}
+
+// value writes a value, making its tooltip available when it has one.
+func (h *htmlWriter) value(class, text, tooltip string) {
+	if tooltip != "" {
+		class = strings.TrimSpace(class + " tip")
+	}
+	h.buffer.WriteString("<span")
+	if class != "" {
+		fmt.Fprintf(&h.buffer, ` class="%s"`, class)
+	}
+	if tooltip != "" {
+		fmt.Fprintf(&h.buffer, ` tabindex="0" data-tip="%s"`, h.escape(tooltip))
+	}
+	h.buffer.WriteByte('>')
+	h.buffer.WriteString(h.escape(text))
+	h.buffer.WriteString("</span>")
+}
+
+func (h *htmlWriter) header() {
+	p := h.entity.Profile
+	fmt.Fprintf(&h.buffer, "<h1>%s</h1>\n<div class=\"profile\">", h.escape(p.Name))
+	var parts []string
+	if p.Title != "" {
+		parts = append(parts, h.escape(p.Title))
+	}
+	if p.PlayerName != "" {
+		parts = append(parts, h.escape(fmt.Sprintf(i18n.Text("Player: %s"), p.PlayerName)))
+	}
+	parts = append(parts, h.escape(fmt.Sprintf(i18n.Text("%s points (%s unspent)"), h.entity.TotalPoints.String(),
+		h.entity.UnspentPoints().String())))
+	h.buffer.WriteString(strings.Join(parts, " &middot; "))
+	h.buffer.WriteString("</div>\n")
+}
+
+func (h *htmlWriter) startSection(title string, columns []string) {
+	fmt.Fprintf(&h.buffer, "<section class=\"list\" style=\"--cols: %s\">\n<h2>%s</h2>\n",
+		strings.Join(columns, " "), h.escape(title))
+}
+
+func (h *htmlWriter) attributes() {
+	h.startSection(i18n.Text("Attributes"), []string{"minmax(0, 1fr)", "5em", "3.5em"})
+	h.buffer.WriteString(`<div class="row header"><span></span><span class="number">`)
+	h.buffer.WriteString(h.escape(i18n.Text("Value")))
+	h.buffer.WriteString(`</span><span class="number">`)
+	h.buffer.WriteString(h.escape(i18n.Text("Pts")))
+	h.buffer.WriteString("</span></div>\n")
+	for _, attr := range h.entity.Attributes.List() {
+		def := attr.AttributeDef()
+		if def == nil || def.IsSeparator() {
+			continue
+		}
+		value := attr.Maximum().String()
+		if def.Type == attribute.Pool {
+			value = attr.Current().String() + "/" + value
+		}
+		h.buffer.WriteString(`<div class="row">`)
+		h.value("", def.CombinedName(), "")
+		h.value("number", value, "")
+		h.value("number", attr.PointCost().String(), "")
+		h.buffer.WriteString("</div>\n")
+	}
+	enc := h.entity.EncumbranceLevel(false)
+	for _, one := range []struct {
+		title string
+		value string
+	}{
+		{title: i18n.Text("Basic Lift"), value: h.entity.SheetSettings.DefaultWeightUnits.Format(h.entity.BasicLift())},
+		{title: i18n.Text("Thrust"), value: h.entity.Thrust().String()},
+		{title: i18n.Text("Swing"), value: h.entity.Swing().String()},
+		{title: i18n.Text("Encumbrance"), value: enc.String()},
+		{title: i18n.Text("Move"), value: strconv.Itoa(h.entity.Move(enc))},
+		{title: i18n.Text("Dodge"), value: strconv.Itoa(h.entity.Dodge(enc))},
+	} {
+		h.buffer.WriteString(`<div class="row">`)
+		h.value("", one.title, "")
+		h.value("number", one.value, "")
+		h.buffer.WriteString("<span></span></div>\n")
+	}
+	h.buffer.WriteString("</section>\n")
+}
+
+func (h *htmlWriter) hitLocations() {
+	locations := h.entity.SheetSettings.BodyType.Locations
+	if len(locations) == 0 {
+		return
+	}
+	h.startSection(i18n.Text("Hit Locations"), []string{"3em", "minmax(0, 1fr)", "3em", "3.5em"})
+	h.buffer.WriteString(`<div class="row header">`)
+	for _, title := range []string{i18n.Text("Roll"), i18n.Text("Location"), i18n.Text("-"), i18n.Text("DR")} {
+		h.value("", title, "")
+	}
+	h.buffer.WriteString("</div>\n")
+	for _, loc := range locations {
+		var tooltip xio.ByteBuffer
+		dr := loc.DisplayDR(h.entity, &tooltip)
+		tip := ""
+		if tooltip.Len() != 0 {
+			tip = gurps.IncludesModifiersFrom + ":" + tooltip.String()
+		}
+		h.buffer.WriteString(`<div class="row">`)
+		h.value("", loc.RollRange, "")
+		h.value("", loc.TableName, loc.Description)
+		h.value("number", strconv.Itoa(loc.HitPenalty), "")
+		h.value("number", dr, tip)
+		h.buffer.WriteString("</div>\n")
+	}
+	h.buffer.WriteString("</section>\n")
+}
+
+// htmlList writes a list of nodes, using the same cell data the sheet displays. Containers become collapsible.
+func htmlList[T gurps.NodeTypes](h *htmlWriter, title string, list []T, columns []htmlColumn) {
+	if len(list) == 0 {
+		return
+	}
+	widths := make([]string, len(columns))
+	for i, col := range columns {
+		if col.width == "" {
+			widths[i] = "minmax(0, 1fr)"
+		} else {
+			widths[i] = col.width
+		}
+	}
+	h.startSection(title, widths)
+	h.buffer.WriteString(`<div class="row header">`)
+	for _, col := range columns {
+		class := ""
+		if col.width != "" {
+			class = "number"
+		}
+		h.value(class, col.title, "")
+	}
+	h.buffer.WriteString("</div>\n")
+	htmlRows(h, list, columns)
+	h.buffer.WriteString("</section>\n")
+}
+
+func htmlRows[T gurps.NodeTypes](h *htmlWriter, list []T, columns []htmlColumn) {
+	for _, one := range list {
+		node := gurps.AsNode(one)
+		if node.HasChildren() {
+			h.buffer.WriteString("<details")
+			if node.Open() {
+				h.buffer.WriteString(" open")
+			}
+			h.buffer.WriteString(`><summary class="row">`)
+			htmlCells(h, node, columns)
+			h.buffer.WriteString("</summary>\n<div class=\"children\">\n")
+			htmlRows(h, node.NodeChildren(), columns)
+			h.buffer.WriteString("</div>\n</details>\n")
+		} else {
+			h.buffer.WriteString(`<div class="row">`)
+			htmlCells(h, node, columns)
+			h.buffer.WriteString("</div>\n")
+		}
+	}
+}
+
+func htmlCells[T gurps.NodeTypes](h *htmlWriter, node gurps.Node[T], columns []htmlColumn) {
+	for _, col := range columns {
+		var data gurps.CellData
+		node.CellData(col.column, &data)
+		var classes []string
+		if col.width != "" {
+			classes = append(classes, "number")
+		}
+		if data.Dim || data.Disabled {
+			classes = append(classes, "dim")
+		}
+		tooltip := data.Tooltip
+		if data.UnsatisfiedReason != "" {
+			classes = append(classes, "unsatisfied")
+			if tooltip != "" {
+				tooltip += "\n\n"
+			}
+			tooltip += data.UnsatisfiedReason
+		}
+		text := data.Primary
+		if data.Type == gurps.Toggle {
+			text = ""
+			if data.Checked {
+				text = "✓"
+			}
+		}
+		if tooltip != "" {
+			classes = append(classes, "tip")
+		}
+		h.buffer.WriteString("<span")
+		if len(classes) != 0 {
+			fmt.Fprintf(&h.buffer, ` class="%s"`, strings.Join(classes, " "))
+		}
+		if tooltip != "" {
+			fmt.Fprintf(&h.buffer, ` tabindex="0" data-tip="%s"`, h.escape(tooltip))
+		}
+		h.buffer.WriteByte('>')
+		h.buffer.WriteString(h.escape(text))
+		if data.Secondary != "" && data.Type == gurps.Text {
+			fmt.Fprintf(&h.buffer, `<span class="secondary">%s</span>`, h.escape(data.Secondary))
+		}
+		h.buffer.WriteString("</span>")
+	}
+}
@@ -0,0 +1,380 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package export
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/trait"
+	"github.com/richardwilkes/gcs/v5/model/gurps/weapon"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// StatBlockFormat holds the output format of a stat block.
+type StatBlockFormat int
+
+// Possible StatBlockFormat values.
+const (
+	PlainTextStatBlock StatBlockFormat = iota
+	MarkdownStatBlock
+	HTMLStatBlock
+)
+
+// AllStatBlockFormats holds all possible values.
+var AllStatBlockFormats = []StatBlockFormat{
+	PlainTextStatBlock,
+	MarkdownStatBlock,
+	HTMLStatBlock,
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "#", `\#`,
+	"|", `\|`)
+
+// String implements fmt.Stringer.
+func (f StatBlockFormat) String() string {
+	switch f {
+	case MarkdownStatBlock:
+		return i18n.Text("Markdown")
+	case HTMLStatBlock:
+		return i18n.Text("HTML")
+	default:
+		return i18n.Text("Plain Text")
+	}
+}
+
+// Extension returns the file extension normally used for this format.
+func (f StatBlockFormat) Extension() string {
+	switch f {
+	case MarkdownStatBlock:
+		return ".md"
+	case HTMLStatBlock:
+		return ".html"
+	default:
+		return ".txt"
+	}
+}
+
+// StatBlockOptions holds the options for producing a stat block.
+type StatBlockOptions struct {
+	Format        StatBlockFormat
+	IncludePoints bool
+}
+
+type statBlock struct {
+	entity  *gurps.Entity
+	options StatBlockOptions
+	buffer  strings.Builder
+}
+
+// ToStatBlockFile writes a stat block for the entity to a file.
+func ToStatBlockFile(entity *gurps.Entity, options StatBlockOptions, filePath string) error {
+	if err := os.WriteFile(filePath, []byte(StatBlock(entity, options)), 0o640); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
+
+// StatBlock produces the compact, paragraph-style stat block typically used for NPCs in published adventures. The
+// entity should have been recalculated beforehand.
+func StatBlock(entity *gurps.Entity, options StatBlockOptions) string {
+	sb := &statBlock{
+		entity:  entity,
+		options: options,
+	}
+	if options.Format == HTMLStatBlock {
+		sb.buffer.WriteString(`<div class="stat-block">` + "\n")
+	}
+	sb.name()
+	sb.attributes()
+	sb.traits()
+	sb.paragraph(i18n.Text("Skills"), sb.skills())
+	sb.paragraph(i18n.Text("Spells"), sb.spells())
+	sb.paragraph(i18n.Text("Melee Weapons"), sb.meleeWeapons())
+	sb.paragraph(i18n.Text("Ranged Weapons"), sb.rangedWeapons())
+	sb.paragraph(i18n.Text("Equipment"), sb.equipment(entity.CarriedEquipment))
+	sb.notes()
+	if options.Format == HTMLStatBlock {
+		sb.buffer.WriteString("</div>\n")
+	}
+	return sb.buffer.String()
+}
+
+func (sb *statBlock) escape(text string) string {
+	switch sb.options.Format {
+	case MarkdownStatBlock:
+		return markdownEscaper.Replace(text)
+	case HTMLStatBlock:
+		return html.EscapeString(text)
+	default:
+		return text
+	}
+}
+
+func (sb *statBlock) emphasize(text string) string {
+	switch sb.options.Format {
+	case MarkdownStatBlock:
+		return "**" + sb.escape(text) + "**"
+	case HTMLStatBlock:
+		return "<b>" + sb.escape(text) + "</b>"
+	default:
+		return text
+	}
+}
+
+func (sb *statBlock) startParagraph() {
+	if sb.options.Format == HTMLStatBlock {
+		sb.buffer.WriteString("<p>")
+	}
+}
+
+func (sb *statBlock) endParagraph() {
+	if sb.options.Format == HTMLStatBlock {
+		sb.buffer.WriteString("</p>\n")
+	} else {
+		sb.buffer.WriteString("\n\n")
+	}
+}
+
+// paragraph writes a labeled paragraph of items, separated by semicolons. Nothing is written if there are no items.
+func (sb *statBlock) paragraph(label string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	sb.startParagraph()
+	if label != "" {
+		sb.buffer.WriteString(sb.emphasize(label + ":"))
+		sb.buffer.WriteByte(' ')
+	}
+	for i, item := range items {
+		if i != 0 {
+			sb.buffer.WriteString("; ")
+		}
+		sb.buffer.WriteString(sb.escape(item))
+	}
+	sb.buffer.WriteByte('.')
+	sb.endParagraph()
+}
+
+func (sb *statBlock) points(text string, points fxp.Int) string {
+	if !sb.options.IncludePoints {
+		return text
+	}
+	return fmt.Sprintf("%s [%s]", text, points.String())
+}
+
+func (sb *statBlock) name() {
+	name := strings.TrimSpace(sb.entity.Profile.Name)
+	if name == "" {
+		return
+	}
+	sb.startParagraph()
+	if sb.options.IncludePoints {
+		name = fmt.Sprintf(i18n.Text("%s (%s points)"), name, sb.entity.TotalPoints.String())
+	}
+	sb.buffer.WriteString(sb.emphasize(name))
+	sb.endParagraph()
+}
+
+func (sb *statBlock) attributes() {
+	var primary, secondary []string
+	for _, attr := range sb.entity.Attributes.List() {
+		def := attr.AttributeDef()
+		if def == nil || def.IsSeparator() {
+			continue
+		}
+		text := sb.points(def.Name+" "+attr.Maximum().String(), attr.PointCost())
+		if def.Primary() {
+			primary = append(primary, text)
+		} else {
+			secondary = append(secondary, text)
+		}
+	}
+	sb.paragraph("", primary)
+	derived := []string{
+		fmt.Sprintf(i18n.Text("Damage %s/%s"), sb.entity.Thrust().String(), sb.entity.Swing().String()),
+		fmt.Sprintf(i18n.Text("BL %s"), sb.entity.SheetSettings.DefaultWeightUnits.Format(sb.entity.BasicLift())),
+	}
+	derived = append(derived, secondary...)
+	derived = append(derived, fmt.Sprintf(i18n.Text("Dodge %d"),
+		sb.entity.Dodge(sb.entity.EncumbranceLevel(false))))
+	sb.paragraph("", derived)
+}
+
+func (sb *statBlock) traits() {
+	var advantages, perks, disadvantages, quirks []string
+	var walk func(list []*gurps.Trait)
+	walk = func(list []*gurps.Trait) {
+		for _, t := range list {
+			if !t.Enabled() {
+				continue
+			}
+			if t.Container() && t.ContainerType == trait.Group {
+				walk(t.Children)
+				continue
+			}
+			text := t.String()
+			if notes := t.ModifierNotes(); notes != "" {
+				text += " (" + notes + ")"
+			}
+			points := t.AdjustedPoints()
+			text = sb.points(text, points)
+			switch {
+			case points == fxp.One:
+				perks = append(perks, text)
+			case points == -fxp.One:
+				quirks = append(quirks, text)
+			case points < 0:
+				disadvantages = append(disadvantages, text)
+			default:
+				advantages = append(advantages, text)
+			}
+		}
+	}
+	walk(sb.entity.Traits)
+	sb.paragraph(i18n.Text("Advantages"), advantages)
+	sb.paragraph(i18n.Text("Perks"), perks)
+	sb.paragraph(i18n.Text("Disadvantages"), disadvantages)
+	sb.paragraph(i18n.Text("Quirks"), quirks)
+}
+
+func (sb *statBlock) skills() []string {
+	var list []string
+	gurps.Traverse(func(s *gurps.Skill) bool {
+		text := s.String()
+		if s.LevelData.Level > 0 {
+			text += "-" + s.LevelData.Level.Trunc().String()
+		}
+		list = append(list, sb.points(text, s.AdjustedPoints(nil)))
+		return false
+	}, false, true, sb.entity.Skills...)
+	return list
+}
+
+func (sb *statBlock) spells() []string {
+	var list []string
+	gurps.Traverse(func(s *gurps.Spell) bool {
+		text := s.String()
+		if s.LevelData.Level > 0 {
+			text += "-" + s.LevelData.Level.Trunc().String()
+		}
+		list = append(list, sb.points(text, s.AdjustedPoints(nil)))
+		return false
+	}, false, true, sb.entity.Spells...)
+	return list
+}
+
+func (sb *statBlock) weaponName(w *gurps.Weapon) string {
+	var buffer strings.Builder
+	buffer.WriteString(w.String())
+	if w.Usage != "" {
+		buffer.WriteString(" – ")
+		buffer.WriteString(w.Usage)
+	}
+	fmt.Fprintf(&buffer, " (%s): %s", w.SkillLevel(nil).Trunc().String(), w.Damage.ResolvedDamage(nil))
+	return buffer.String()
+}
+
+func (sb *statBlock) meleeWeapons() []string {
+	var list []string
+	for _, w := range sb.entity.EquippedWeapons(weapon.Melee) {
+		parts := []string{sb.weaponName(w)}
+		if reach := w.ResolvedReach(); reach != "" {
+			parts = append(parts, fmt.Sprintf(i18n.Text("Reach %s"), reach))
+		}
+		if parry := w.ResolvedParry(nil); parry != "" && parry != "No" {
+			parts = append(parts, fmt.Sprintf(i18n.Text("Parry %s"), parry))
+		}
+		if block := w.ResolvedBlock(nil); block != "" && block != "No" {
+			parts = append(parts, fmt.Sprintf(i18n.Text("Block %s"), block))
+		}
+		list = append(list, strings.Join(parts, ", "))
+	}
+	return list
+}
+
+func (sb *statBlock) rangedWeapons() []string {
+	var list []string
+	for _, w := range sb.entity.EquippedWeapons(weapon.Ranged) {
+		parts := []string{sb.weaponName(w)}
+		for _, one := range []struct {
+			format string
+			value  string
+		}{
+			{format: i18n.Text("Acc %s"), value: w.ResolvedAccuracy()},
+			{format: i18n.Text("Range %s"), value: w.ResolvedRange()},
+			{format: i18n.Text("RoF %s"), value: w.ResolvedRateOfFire()},
+			{format: i18n.Text("Shots %s"), value: w.ShotsText()},
+			{format: i18n.Text("Bulk %s"), value: w.ResolvedBulk()},
+			{format: i18n.Text("Rcl %s"), value: w.ResolvedRecoil()},
+		} {
+			if one.value != "" {
+				parts = append(parts, fmt.Sprintf(one.format, one.value))
+			}
+		}
+		list = append(list, strings.Join(parts, ", "))
+	}
+	return list
+}
+
+// equipment returns the equipment descriptions. The contents of containers are listed in parentheses after the
+// container, so that nesting is preserved.
+func (sb *statBlock) equipment(list []*gurps.Equipment) []string {
+	items := make([]string, 0, len(list))
+	for _, eqp := range list {
+		var buffer strings.Builder
+		if eqp.Quantity != fxp.One {
+			buffer.WriteString(eqp.Quantity.String())
+			buffer.WriteString("× ")
+		}
+		buffer.WriteString(eqp.Description())
+		var details []string
+		if notes := eqp.ModifierNotes(); notes != "" {
+			details = append(details, notes)
+		}
+		if eqp.Container() && len(eqp.Children) != 0 {
+			details = append(details, sb.equipment(eqp.Children)...)
+		}
+		if len(details) != 0 {
+			buffer.WriteString(" (")
+			buffer.WriteString(strings.Join(details, "; "))
+			buffer.WriteByte(')')
+		}
+		items = append(items, buffer.String())
+	}
+	return items
+}
+
+func (sb *statBlock) notes() {
+	var list []string
+	gurps.Traverse(func(n *gurps.Note) bool {
+		if text := strings.TrimSpace(n.Text); text != "" {
+			list = append(list, strings.Join(strings.Fields(text), " "))
+		}
+		return false
+	}, false, true, sb.entity.Notes...)
+	if len(list) == 0 {
+		return
+	}
+	sb.startParagraph()
+	sb.buffer.WriteString(sb.emphasize(i18n.Text("Notes") + ":"))
+	for _, one := range list {
+		sb.buffer.WriteByte(' ')
+		sb.buffer.WriteString(sb.escape(one))
+	}
+	sb.endParagraph()
+}
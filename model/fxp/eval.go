@@ -33,26 +33,31 @@ func NewEvaluator(resolver eval.VariableResolver) *eval.Evaluator {
 	}
 }
 
-// EvaluateToNumber evaluates the provided expression and returns a number.
+// EvaluateToNumber evaluates the provided expression and returns a number. Any errors are suppressed, resulting in a
+// return value of 0.
 func EvaluateToNumber(expression string, resolver eval.VariableResolver) Int {
+	value, err := TryEvaluateToNumber(expression, resolver)
+	if err != nil && dbg.VariableResolver {
+		jot.Warn(err)
+	}
+	return value
+}
+
+// TryEvaluateToNumber evaluates the provided expression and returns a number, or an error if the expression can't be
+// resolved to a number.
+func TryEvaluateToNumber(expression string, resolver eval.VariableResolver) (Int, error) {
 	result, err := NewEvaluator(resolver).Evaluate(expression)
 	if err != nil {
-		if dbg.VariableResolver {
-			jot.Warn(errs.NewWithCausef(err, "unable to resolve '%s'", expression))
-		}
-		return 0
+		return 0, errs.NewWithCausef(err, "unable to resolve '%s'", expression)
 	}
 	if value, ok := result.(Int); ok {
-		return value
+		return value, nil
 	}
 	if str, ok := result.(string); ok {
 		var value Int
 		if value, err = FromString(str); err == nil {
-			return value
+			return value, nil
 		}
 	}
-	if dbg.VariableResolver {
-		jot.Warn(errs.NewWithCausef(err, "unable to resolve '%s' to a number", expression))
-	}
-	return 0
+	return 0, errs.Newf("unable to resolve '%s' to a number", expression)
 }
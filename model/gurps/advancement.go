@@ -0,0 +1,53 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package gurps
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// Advancement records character points earned, dated against the in-game calendar.
+type Advancement struct {
+	Date   string  `json:"date,omitempty"`
+	Points fxp.Int `json:"points"`
+	Reason string  `json:"reason,omitempty"`
+}
+
+func (a *Advancement) String() string {
+	var buffer strings.Builder
+	if a.Date != "" {
+		buffer.WriteString(a.Date)
+		buffer.WriteString(": ")
+	}
+	fmt.Fprintf(&buffer, i18n.Text("%s points"), a.Points.StringWithSign())
+	if a.Reason != "" {
+		fmt.Fprintf(&buffer, " (%s)", a.Reason)
+	}
+	return buffer.String()
+}
+
+// EarnPoints adds the points to the total and records them as an advancement dated with the current in-game date.
+func (e *Entity) EarnPoints(points fxp.Int, reason string) {
+	if points == 0 {
+		return
+	}
+	e.TotalPoints += points
+	e.Advancements = append(e.Advancements, &Advancement{
+		Date:   e.CurrentDate,
+		Points: points,
+		Reason: strings.TrimSpace(reason),
+	})
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package gurps
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/settings"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// CashTag is the tag used to identify equipment that represents spendable cash.
+const CashTag = "Cash"
+
+// Currencies returns the currently configured set of currencies.
+func Currencies() *settings.CurrencyRef {
+	return SettingsProvider.GeneralSettings().CurrencyRef(SettingsProvider.Libraries())
+}
+
+// ConvertCurrency converts an amount from one currency to another.
+func ConvertCurrency(amount fxp.Int, from, to string) fxp.Int {
+	if amount == 0 || strings.EqualFold(strings.TrimSpace(from), strings.TrimSpace(to)) {
+		return amount
+	}
+	return Currencies().Convert(amount, from, to)
+}
+
+// FormatCurrency formats an amount using the given currency.
+func FormatCurrency(amount fxp.Int, currency string) string {
+	return Currencies().Format(amount, currency)
+}
+
+// IsCash returns true if this equipment represents spendable cash.
+func (e *Equipment) IsCash() bool {
+	if e.Container() {
+		return false
+	}
+	for _, tag := range e.Tags {
+		if strings.EqualFold(tag, CashTag) {
+			return true
+		}
+	}
+	return false
+}
+
+// CashValue returns the amount of cash this equipment represents, converted to the given currency.
+func (e *Equipment) CashValue(currency string) fxp.Int {
+	return ConvertCurrency(e.ExtendedValue(), e.Currency, currency)
+}
+
+// CashItem returns the first equipment item that represents spendable cash, looking first in the carried equipment
+// and then in the other equipment. Returns nil if none can be found.
+func (e *Entity) CashItem() *Equipment {
+	var found *Equipment
+	f := func(eqp *Equipment) bool {
+		if eqp.IsCash() {
+			found = eqp
+			return true
+		}
+		return false
+	}
+	Traverse(f, false, false, e.CarriedEquipment...)
+	if found == nil {
+		Traverse(f, false, false, e.OtherEquipment...)
+	}
+	return found
+}
+
+// Cash returns the total amount of spendable cash, converted to the display currency.
+func (e *Entity) Cash() fxp.Int {
+	var total fxp.Int
+	currency := e.DisplayCurrency()
+	f := func(eqp *Equipment) bool {
+		if eqp.IsCash() {
+			total += eqp.CashValue(currency)
+		}
+		return false
+	}
+	Traverse(f, false, false, e.CarriedEquipment...)
+	Traverse(f, false, false, e.OtherEquipment...)
+	return total
+}
+
+// AdjustCash adds the amount, which is expressed in the given currency, to the entity's cash item. Negative amounts
+// debit the cash item. If the cash item has a quantity of one, its value is adjusted; otherwise its quantity is
+// adjusted by whole units based on the per-unit value, with any remainder placed in a separate change item alongside
+// it.
+func (e *Entity) AdjustCash(amount fxp.Int, currency string) error {
+	if amount == 0 {
+		return nil
+	}
+	cash := e.CashItem()
+	if cash == nil {
+		return errs.Newf(i18n.Text("No equipment tagged with '%s' is available."), CashTag)
+	}
+	amount = ConvertCurrency(amount, currency, cash.Currency)
+	unitValue := cash.AdjustedValue()
+	if cash.Quantity == fxp.One || unitValue <= 0 {
+		if cash.Value+amount < 0 {
+			return errs.New(i18n.Text("Insufficient funds."))
+		}
+		cash.Value += amount
+		if cash.Quantity <= 0 {
+			cash.Quantity = fxp.One
+		}
+		return nil
+	}
+	// Round toward negative infinity, so that debits take enough whole units to cover the amount
+	units := fxp.ApplyRounding(amount.Div(unitValue), true)
+	qty := cash.Quantity + units
+	if qty < 0 {
+		return errs.New(i18n.Text("Insufficient funds."))
+	}
+	cash.Quantity = qty
+	if remainder := amount - units.Mul(unitValue); remainder > 0 {
+		e.addChange(cash, remainder)
+	}
+	return nil
+}
+
+// addChange adds the amount, which is expressed in the cash item's currency, to the change item next to the cash item,
+// creating it if necessary.
+func (e *Entity) addChange(cash *Equipment, amount fxp.Int) {
+	siblings, setSiblings := e.equipmentSiblings(cash)
+	name := i18n.Text("Change")
+	for _, one := range siblings {
+		if one != cash && one.IsCash() && one.Name == name && one.Quantity == fxp.One &&
+			strings.EqualFold(one.Currency, cash.Currency) {
+			one.Value += amount
+			return
+		}
+	}
+	change := NewEquipment(e, cash.Parent(), false)
+	change.Name = name
+	change.Currency = cash.Currency
+	change.Value = amount
+	change.Tags = []string{CashTag}
+	change.Equipped = cash.Equipped
+	list := make([]*Equipment, 0, len(siblings)+1)
+	for _, one := range siblings {
+		list = append(list, one)
+		if one == cash {
+			list = append(list, change)
+		}
+	}
+	setSiblings(list)
+}
+
+// equipmentSiblings returns the list the equipment belongs to, along with a function that replaces that list.
+func (e *Entity) equipmentSiblings(eqp *Equipment) (siblings []*Equipment, set func([]*Equipment)) {
+	if parent := eqp.Parent(); parent != nil {
+		return parent.Children, func(list []*Equipment) { parent.Children = list }
+	}
+	for _, one := range e.CarriedEquipment {
+		if one == eqp {
+			return e.CarriedEquipment, func(list []*Equipment) { e.CarriedEquipment = list }
+		}
+	}
+	return e.OtherEquipment, func(list []*Equipment) { e.OtherEquipment = list }
+}
+
+// DisplayCurrency returns the currency used for displaying totals.
+func (e *Entity) DisplayCurrency() string {
+	return SheetSettingsFor(e).DisplayCurrency
+}
+
+// FormatWealth formats an amount, expressed in the display currency, for display.
+func (e *Entity) FormatWealth(amount fxp.Int) string {
+	return FormatCurrency(amount, e.DisplayCurrency())
+}
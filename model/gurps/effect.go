@@ -0,0 +1,214 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package gurps
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/feature"
+	"github.com/richardwilkes/gcs/v5/model/gurps/gid"
+	"github.com/richardwilkes/gcs/v5/model/id"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xmath"
+)
+
+var _ fmt.Stringer = &Effect{}
+
+// Effect holds a temporary state affecting an entity, such as an affliction, a posture or a spell that was cast upon
+// it. While present, its features contribute to the entity just as those of a trait do. Features that are per level are
+// multiplied by the number of stacks. A MaxStacks of 0 means there is no limit on the number of stacks.
+type Effect struct {
+	ID        uuid.UUID        `json:"id"`
+	Name      string           `json:"name"`
+	Source    string           `json:"source,omitempty"`
+	Notes     string           `json:"notes,omitempty"`
+	Stacks    int              `json:"stacks,omitempty"`
+	Stackable bool             `json:"stackable,omitempty"`
+	MaxStacks int              `json:"max_stacks,omitempty"`
+	Duration  int              `json:"duration,omitempty"`
+	Remaining int              `json:"remaining,omitempty"`
+	Features  feature.Features `json:"features,omitempty"`
+}
+
+// NewEffect creates a new Effect. A duration of 0 means the effect lasts until it is removed.
+func NewEffect(name, source string, duration int, stackable bool, features ...feature.Feature) *Effect {
+	return &Effect{
+		ID:        id.NewUUID(),
+		Name:      name,
+		Source:    source,
+		Stacks:    1,
+		Stackable: stackable,
+		Duration:  duration,
+		Remaining: duration,
+		Features:  features,
+	}
+}
+
+// Clone creates a copy of this effect with a new ID.
+func (e *Effect) Clone() *Effect {
+	other := *e
+	other.ID = id.NewUUID()
+	other.Features = e.Features.Clone()
+	return &other
+}
+
+// String implements fmt.Stringer.
+func (e *Effect) String() string {
+	if e.Stackable && e.Stacks > 1 {
+		return fmt.Sprintf("%s ×%d", e.Name, e.Stacks)
+	}
+	return e.Name
+}
+
+// Level returns the level to use for per level features.
+func (e *Effect) Level() fxp.Int {
+	return fxp.From(xmath.Max(e.Stacks, 1))
+}
+
+// AddStacks adjusts the number of stacks by the given amount, keeping the result between 1 and MaxStacks, if set.
+func (e *Effect) AddStacks(amount int) {
+	e.Stacks = xmath.Max(xmath.Max(e.Stacks, 1)+amount, 1)
+	if e.MaxStacks > 0 && e.Stacks > e.MaxStacks {
+		e.Stacks = e.MaxStacks
+	}
+}
+
+// CanAddStack returns true if the effect is stackable and hasn't reached its maximum number of stacks.
+func (e *Effect) CanAddStack() bool {
+	return e.Stackable && (e.MaxStacks <= 0 || e.Stacks < e.MaxStacks)
+}
+
+// Timed returns true if the effect expires on its own.
+func (e *Effect) Timed() bool {
+	return e.Duration > 0
+}
+
+// ApplyEffect adds the effect to the entity. If an effect with the same name and source is already present, it is
+// refreshed instead, gaining a stack if it is stackable.
+func (e *Entity) ApplyEffect(effect *Effect) {
+	for _, one := range e.Effects {
+		if strings.EqualFold(one.Name, effect.Name) && strings.EqualFold(one.Source, effect.Source) {
+			if one.Stackable {
+				one.AddStacks(xmath.Max(effect.Stacks, 1))
+			}
+			one.Duration = effect.Duration
+			one.Remaining = xmath.Max(one.Remaining, effect.Remaining)
+			return
+		}
+	}
+	e.Effects = append(e.Effects, effect)
+}
+
+// RemoveEffect removes the effect from the entity.
+func (e *Entity) RemoveEffect(effect *Effect) {
+	for i, one := range e.Effects {
+		if one == effect {
+			e.Effects = append(e.Effects[:i], e.Effects[i+1:]...)
+			return
+		}
+	}
+}
+
+// AdvanceEffects moves time forward by the given number of seconds for all timed effects, removing those that have
+// expired. The expired effects are returned.
+func (e *Entity) AdvanceEffects(seconds int) []*Effect {
+	var expired []*Effect
+	list := make([]*Effect, 0, len(e.Effects))
+	for _, one := range e.Effects {
+		if one.Timed() {
+			one.Remaining -= seconds
+			if one.Remaining <= 0 {
+				expired = append(expired, one)
+				continue
+			}
+		}
+		list = append(list, one)
+	}
+	e.Effects = list
+	return expired
+}
+
+// CommonEffects returns a fresh set of commonly needed effects, suitable for applying to an entity.
+func CommonEffects() []*Effect {
+	source := i18n.Text("Condition")
+	shock := NewEffect(i18n.Text("Shock"), source, SecondsPerTurn, true,
+		effectAttributeBonus(gid.Dexterity, -1, true),
+		effectAttributeBonus(gid.Intelligence, -1, true))
+	shock.MaxStacks = 4 // Shock penalties can't exceed -4
+	return []*Effect{
+		shock,
+		NewEffect(i18n.Text("Stunned"), source, 0, false,
+			effectAttributeBonus(gid.Dodge, -4, false),
+			effectAttributeBonus(gid.Parry, -4, false),
+			effectAttributeBonus(gid.Block, -4, false)),
+		NewEffect(i18n.Text("Moderate Pain"), source, 0, false,
+			effectAttributeBonus(gid.Dexterity, -2, false),
+			effectAttributeBonus(gid.Intelligence, -2, false)),
+		NewEffect(i18n.Text("Severe Pain"), source, 0, false,
+			effectAttributeBonus(gid.Dexterity, -4, false),
+			effectAttributeBonus(gid.Intelligence, -4, false)),
+		NewEffect(i18n.Text("Terrible Pain"), source, 0, false,
+			effectAttributeBonus(gid.Dexterity, -6, false),
+			effectAttributeBonus(gid.Intelligence, -6, false)),
+		NewEffect(i18n.Text("Coughing"), source, 0, false,
+			effectAttributeBonus(gid.Dexterity, -3, false),
+			effectAttributeBonus(gid.Intelligence, -1, false)),
+		NewEffect(i18n.Text("Nauseated"), source, 0, false,
+			effectAttributeBonus(gid.Dexterity, -2, false),
+			effectAttributeBonus(gid.Intelligence, -2, false),
+			effectAttributeBonus(gid.Health, -2, false),
+			effectAttributeBonus(gid.Dodge, -1, false),
+			effectAttributeBonus(gid.Parry, -1, false),
+			effectAttributeBonus(gid.Block, -1, false)),
+		NewEffect(i18n.Text("Tipsy"), source, 0, false,
+			effectAttributeBonus(gid.Dexterity, -1, false),
+			effectConditionalModifier(i18n.Text("to self-control rolls"), -1)),
+		NewEffect(i18n.Text("Drunk"), source, 0, false,
+			effectAttributeBonus(gid.Dexterity, -2, false),
+			effectAttributeBonus(gid.Intelligence, -2, false),
+			effectConditionalModifier(i18n.Text("to self-control rolls"), -4)),
+		NewEffect(i18n.Text("Crouching"), i18n.Text("Posture"), 0, false,
+			effectConditionalModifier(i18n.Text("to melee attacks"), -2)),
+		NewEffect(i18n.Text("Kneeling"), i18n.Text("Posture"), 0, false,
+			effectConditionalModifier(i18n.Text("to melee attacks"), -2),
+			effectAttributeBonus(gid.Dodge, -2, false),
+			effectAttributeBonus(gid.Parry, -2, false),
+			effectAttributeBonus(gid.Block, -2, false)),
+		NewEffect(i18n.Text("Sitting"), i18n.Text("Posture"), 0, false,
+			effectConditionalModifier(i18n.Text("to melee attacks"), -2),
+			effectAttributeBonus(gid.Dodge, -2, false),
+			effectAttributeBonus(gid.Parry, -2, false),
+			effectAttributeBonus(gid.Block, -2, false)),
+		NewEffect(i18n.Text("Lying Prone"), i18n.Text("Posture"), 0, false,
+			effectConditionalModifier(i18n.Text("to melee attacks"), -4),
+			effectAttributeBonus(gid.Dodge, -3, false),
+			effectAttributeBonus(gid.Parry, -3, false),
+			effectAttributeBonus(gid.Block, -3, false)),
+	}
+}
+
+func effectAttributeBonus(attrID string, amount int, perLevel bool) feature.Feature {
+	bonus := feature.NewAttributeBonus(attrID)
+	bonus.Amount = fxp.From(amount)
+	bonus.PerLevel = perLevel
+	return bonus
+}
+
+func effectConditionalModifier(situation string, amount int) feature.Feature {
+	bonus := feature.NewConditionalModifierBonus()
+	bonus.Situation = situation
+	bonus.Amount = fxp.From(amount)
+	return bonus
+}
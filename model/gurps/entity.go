@@ -71,6 +71,12 @@ type EntityData struct {
 	CarriedEquipment []*Equipment   `json:"equipment,omitempty"`
 	OtherEquipment   []*Equipment   `json:"other_equipment,omitempty"`
 	Notes            []*Note        `json:"notes,omitempty"`
+	CurrentDate      string         `json:"current_date,omitempty"`
+	TimeOfDay        int            `json:"time_of_day,omitempty"`
+	CostOfLiving     fxp.Int        `json:"cost_of_living,omitempty"`
+	ActiveSpells     []*ActiveSpell `json:"active_spells,omitempty"`
+	Effects          []*Effect      `json:"effects,omitempty"`
+	Advancements     []*Advancement `json:"advancements,omitempty"`
 	CreatedOn        jio.Time       `json:"created_date"`
 	ModifiedOn       jio.Time       `json:"modified_date"`
 	ThirdParty       map[string]any `json:"third_party,omitempty"`
@@ -205,6 +211,24 @@ func (e *Entity) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// SetAttributeDefs replaces the attribute definitions, adding attributes for any new definitions and removing those
+// whose definitions are gone.
+func (e *Entity) SetAttributeDefs(defs *AttributeDefs) {
+	e.SheetSettings.Attributes = defs
+	for attrID, def := range defs.Set {
+		if attr, exists := e.Attributes.Set[attrID]; exists {
+			attr.Order = def.Order
+		} else {
+			e.Attributes.Set[attrID] = NewAttribute(e, attrID, def.Order)
+		}
+	}
+	for attrID := range e.Attributes.Set {
+		if _, exists := defs.Set[attrID]; !exists {
+			delete(e.Attributes.Set, attrID)
+		}
+	}
+}
+
 // DiscardCaches discards the internal caches.
 func (e *Entity) DiscardCaches() {
 	e.cachedBasicLift = -1
@@ -302,6 +326,11 @@ func (e *Entity) processFeatures() {
 		}, true, true, eqp.Modifiers...)
 		return false
 	}, false, false, e.CarriedEquipment...)
+	for _, effect := range e.Effects {
+		for _, f := range effect.Features {
+			processFeature(effect, m, f, effect.Level())
+		}
+	}
 	e.featureMap = m
 	e.LiftingStrengthBonus = e.BonusFor(feature.AttributeIDPrefix+gid.Strength+"."+attribute.LiftingOnly.Key(), nil).Trunc()
 	e.StrikingStrengthBonus = e.BonusFor(feature.AttributeIDPrefix+gid.Strength+"."+attribute.StrikingOnly.Key(), nil).Trunc()
@@ -514,20 +543,22 @@ func (e *Entity) SpellPoints() fxp.Int {
 	return total
 }
 
-// WealthCarried returns the current wealth being carried.
+// WealthCarried returns the current wealth being carried, expressed in the display currency.
 func (e *Entity) WealthCarried() fxp.Int {
 	var value fxp.Int
+	currency := e.DisplayCurrency()
 	for _, one := range e.CarriedEquipment {
-		value += one.ExtendedValue()
+		value += ConvertCurrency(one.ExtendedValue(), one.Currency, currency)
 	}
 	return value
 }
 
-// WealthNotCarried returns the current wealth not being carried.
+// WealthNotCarried returns the current wealth not being carried, expressed in the display currency.
 func (e *Entity) WealthNotCarried() fxp.Int {
 	var value fxp.Int
+	currency := e.DisplayCurrency()
 	for _, one := range e.OtherEquipment {
-		value += one.ExtendedValue()
+		value += ConvertCurrency(one.ExtendedValue(), one.Currency, currency)
 	}
 	return value
 }
@@ -1196,6 +1227,9 @@ func (e *Entity) Reactions() []*ConditionalModifier {
 		e.reactionsFromFeatureList(i18n.Text("from skill ")+sk.String(), sk.Features, m)
 		return false
 	}, false, true, e.Skills...)
+	for _, effect := range e.Effects {
+		e.reactionsFromFeatureList(i18n.Text("from effect ")+effect.String(), effect.Features, m)
+	}
 	list := make([]*ConditionalModifier, 0, len(m))
 	for _, v := range m {
 		list = append(list, v)
@@ -1246,6 +1280,9 @@ func (e *Entity) ConditionalModifiers() []*ConditionalModifier {
 		e.conditionalModifiersFromFeatureList(i18n.Text("from skill ")+sk.String(), sk.Features, m)
 		return false
 	}, false, true, e.Skills...)
+	for _, effect := range e.Effects {
+		e.conditionalModifiersFromFeatureList(i18n.Text("from effect ")+effect.String(), effect.Features, m)
+	}
 	list := make([]*ConditionalModifier, 0, len(m))
 	for _, v := range m {
 		list = append(list, v)
@@ -161,6 +161,7 @@ func (e *Equipment) MarshalJSON() ([]byte, error) {
 		ExtendedValue           fxp.Int         `json:"extended_value"`
 		ExtendedWeight          measure.Weight  `json:"extended_weight"`
 		ExtendedWeightForSkills *measure.Weight `json:"extended_weight_for_skills,omitempty"`
+		ExtendedVolume          *measure.Volume `json:"extended_volume,omitempty"`
 	}
 	e.ClearUnusedFieldsForType()
 	defUnits := SheetSettingsFor(e.Entity).DefaultWeightUnits
@@ -179,6 +180,9 @@ func (e *Equipment) MarshalJSON() ([]byte, error) {
 		w := e.ExtendedWeight(true, defUnits)
 		data.Calc.ExtendedWeightForSkills = &w
 	}
+	if v := e.ExtendedVolume(); v != 0 {
+		data.Calc.ExtendedVolume = &v
+	}
 	return json.Marshal(&data)
 }
 
@@ -253,11 +257,11 @@ func (e *Equipment) CellData(column int, data *CellData) {
 		data.Alignment = unison.EndAlignment
 	case EquipmentCostColumn:
 		data.Type = Text
-		data.Primary = e.AdjustedValue().String()
+		data.Primary = e.formatValue(e.AdjustedValue())
 		data.Alignment = unison.EndAlignment
 	case EquipmentExtendedCostColumn:
 		data.Type = Text
-		data.Primary = e.ExtendedValue().String()
+		data.Primary = e.formatValue(e.ExtendedValue())
 		data.Alignment = unison.EndAlignment
 	case EquipmentWeightColumn:
 		data.Type = Text
@@ -362,7 +366,7 @@ func (e *Equipment) AdjustedValue() fxp.Int {
 	return ValueAdjustedForModifiers(e.Value, e.Modifiers)
 }
 
-// ExtendedValue returns the extended value.
+// ExtendedValue returns the extended value, expressed in this equipment's currency.
 func (e *Equipment) ExtendedValue() fxp.Int {
 	if e.Quantity <= 0 {
 		return 0
@@ -370,12 +374,19 @@ func (e *Equipment) ExtendedValue() fxp.Int {
 	value := e.AdjustedValue()
 	if e.Container() {
 		for _, one := range e.Children {
-			value += one.ExtendedValue()
+			value += ConvertCurrency(one.ExtendedValue(), one.Currency, e.Currency)
 		}
 	}
 	return value.Mul(e.Quantity)
 }
 
+func (e *Equipment) formatValue(value fxp.Int) string {
+	if e.Currency == "" {
+		return value.String()
+	}
+	return FormatCurrency(value, e.Currency)
+}
+
 // AdjustedWeight returns the weight after adjustments for any modifiers. Does not include the weight of children.
 func (e *Equipment) AdjustedWeight(forSkills bool, defUnits measure.WeightUnits) measure.Weight {
 	if forSkills && e.WeightIgnoredForSkills {
@@ -435,6 +446,23 @@ func ExtendedWeightAdjustedForModifiers(defUnits measure.WeightUnits, qty fxp.In
 	return measure.Weight(base.Mul(qty))
 }
 
+// ExtendedVolume returns the volume of the equipment and anything it contains, multiplied by the quantity.
+func (e *Equipment) ExtendedVolume() measure.Volume {
+	return ExtendedVolumeFor(e.Quantity, e.Volume, e.Children)
+}
+
+// ExtendedVolumeFor calculates the extended volume.
+func ExtendedVolumeFor(qty fxp.Int, baseVolume measure.Volume, children []*Equipment) measure.Volume {
+	if qty <= 0 {
+		return 0
+	}
+	volume := fxp.Int(baseVolume)
+	for _, one := range children {
+		volume += fxp.Int(one.ExtendedVolume())
+	}
+	return measure.Volume(volume.Mul(qty))
+}
+
 // FillWithNameableKeys adds any nameable keys found to the provided map.
 func (e *Equipment) FillWithNameableKeys(m map[string]string) {
 	nameables.Extract(e.Name, m)
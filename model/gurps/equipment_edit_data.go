@@ -32,7 +32,10 @@ type EquipmentEditData struct {
 	Modifiers              []*EquipmentModifier `json:"modifiers,omitempty"`
 	Quantity               fxp.Int              `json:"quantity,omitempty"`
 	Value                  fxp.Int              `json:"value,omitempty"`
+	Currency               string               `json:"currency,omitempty"`
+	AmmoDamageType         string               `json:"ammo_damage_type,omitempty"`
 	Weight                 measure.Weight       `json:"weight,omitempty"`
+	Volume                 measure.Volume       `json:"volume,omitempty"`
 	MaxUses                int                  `json:"max_uses,omitempty"`
 	Uses                   int                  `json:"uses,omitempty"`
 	Prereq                 *PrereqList          `json:"prereqs,omitempty"`
@@ -34,6 +34,18 @@ func InstallEvaluatorFunctions(m map[string]eval.Function) {
 	m["ssrt"] = evalSSRT
 	m["ssrt_to_yards"] = evalSSRTYards
 	m["enc"] = evalEncumbrance
+	m["skill_level"] = evalSkillLevel
+	m["spell_level"] = evalSpellLevel
+	m["has_trait"] = evalHasTrait
+	m["has_skill"] = evalHasSkill
+	m["has_spell"] = evalHasSpell
+	m["has_equipment"] = evalHasEquipment
+	m["count_tagged"] = evalCountTagged
+	m["weapon_damage"] = evalWeaponDamage
+	m["equipped_weight"] = evalEquippedWeight
+	m["carried_weight"] = evalCarriedWeight
+	m["wealth"] = evalWealth
+	m["attribute_points"] = evalAttributePoints
 }
 
 func evalToBool(e *eval.Evaluator, arguments string) (bool, error) {
@@ -0,0 +1,389 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package gurps
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/weapon"
+	"github.com/richardwilkes/toolbox/eval"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// EvaluatorFunctionDoc holds the documentation for a function available to the evaluator.
+type EvaluatorFunctionDoc struct {
+	Name        string
+	Signature   string
+	Description string
+}
+
+// EvaluatorFunctionDocs returns the documentation for the functions installed by InstallEvaluatorFunctions, in the
+// order they should be presented.
+func EvaluatorFunctionDocs() []EvaluatorFunctionDoc {
+	return []EvaluatorFunctionDoc{
+		{
+			Name:        "attribute_points",
+			Signature:   "attribute_points(id)",
+			Description: i18n.Text("The number of points spent on the attribute with the given ID"),
+		},
+		{
+			Name:        "carried_weight",
+			Signature:   "carried_weight([for_skills])",
+			Description: i18n.Text("The weight of all carried equipment, in pounds"),
+		},
+		{
+			Name:        "count_tagged",
+			Signature:   "count_tagged(tag)",
+			Description: i18n.Text("The number of enabled traits, skills, spells and equipped items with the given tag, not counting containers"),
+		},
+		{
+			Name:        "dice",
+			Signature:   "dice(count, sides, modifier, multiplier)",
+			Description: i18n.Text("Dice text built from 1 to 4 arguments; the sides are required"),
+		},
+		{
+			Name:        "enc",
+			Signature:   "enc(for_skills)",
+			Description: i18n.Text("The current encumbrance level, from 0 to 4"),
+		},
+		{
+			Name:        "equipped_weight",
+			Signature:   "equipped_weight([for_skills])",
+			Description: i18n.Text("The weight of all equipped equipment, including equipped items within containers, in pounds"),
+		},
+		{
+			Name:        "has_equipment",
+			Signature:   "has_equipment(name)",
+			Description: i18n.Text("True if an equipped item with the given name is being carried"),
+		},
+		{
+			Name:        "has_skill",
+			Signature:   "has_skill(name[, specialization])",
+			Description: i18n.Text("True if a skill with the given name is present"),
+		},
+		{
+			Name:        "has_spell",
+			Signature:   "has_spell(name)",
+			Description: i18n.Text("True if a spell with the given name is present"),
+		},
+		{
+			Name:        "has_trait",
+			Signature:   "has_trait(name)",
+			Description: i18n.Text("True if an enabled trait with the given name is present"),
+		},
+		{
+			Name:        "roll",
+			Signature:   "roll(dice)",
+			Description: i18n.Text("The result of rolling the given dice"),
+		},
+		{
+			Name:        "signed",
+			Signature:   "signed(value)",
+			Description: i18n.Text("The value as text, with a leading sign"),
+		},
+		{
+			Name:        "skill_level",
+			Signature:   "skill_level(name[, specialization])",
+			Description: i18n.Text("The best level of the skill with the given name, or -1 if it isn't present"),
+		},
+		{
+			Name:        "spell_level",
+			Signature:   "spell_level(name)",
+			Description: i18n.Text("The best level of the spell with the given name, or -1 if it isn't present"),
+		},
+		{
+			Name:        "ssrt",
+			Signature:   "ssrt(length, units, for_size)",
+			Description: i18n.Text("The Size and Speed/Range Table value for the given length"),
+		},
+		{
+			Name:        "ssrt_to_yards",
+			Signature:   "ssrt_to_yards(value)",
+			Description: i18n.Text("The length, in yards, for the given Size and Speed/Range Table value"),
+		},
+		{
+			Name:        "trait_level",
+			Signature:   "trait_level(name)",
+			Description: i18n.Text("The number of levels of the trait with the given name, or -1 if it isn't present or isn't leveled"),
+		},
+		{
+			Name:        "wealth",
+			Signature:   "wealth()",
+			Description: i18n.Text("The value of all carried equipment, in the display currency"),
+		},
+		{
+			Name:        "weapon_damage",
+			Signature:   "weapon_damage(name[, usage])",
+			Description: i18n.Text("The resolved damage of the equipped weapon with the given name"),
+		},
+	}
+}
+
+// EvaluatorFunctionsTooltip returns text suitable for a tooltip that lists the available evaluator functions.
+func EvaluatorFunctionsTooltip() string {
+	var buffer strings.Builder
+	buffer.WriteString(i18n.Text("Available functions:"))
+	for _, one := range EvaluatorFunctionDocs() {
+		buffer.WriteString("\n• ")
+		buffer.WriteString(one.Signature)
+		buffer.WriteString(" — ")
+		buffer.WriteString(one.Description)
+	}
+	return buffer.String()
+}
+
+// evalStringArg returns the text of a string argument. Quoted arguments are used as-is, minus the quotes, while
+// unquoted arguments are evaluated.
+func evalStringArg(e *eval.Evaluator, arg string) (string, error) {
+	arg = strings.TrimSpace(arg)
+	if len(arg) > 1 && arg[0] == '"' && arg[len(arg)-1] == '"' {
+		return arg[1 : len(arg)-1], nil
+	}
+	if arg == "" {
+		return "", nil
+	}
+	return evalToString(e, arg)
+}
+
+func evalOptionalBool(e *eval.Evaluator, arg string) (bool, error) {
+	if strings.TrimSpace(arg) == "" {
+		return false, nil
+	}
+	return evalToBool(e, arg)
+}
+
+func evalNameAndQualifier(e *eval.Evaluator, arguments string) (name, qualifier string, err error) {
+	var arg string
+	arg, arguments = eval.NextArg(arguments)
+	if name, err = evalStringArg(e, arg); err != nil {
+		return "", "", err
+	}
+	arg, _ = eval.NextArg(arguments)
+	if qualifier, err = evalStringArg(e, arg); err != nil {
+		return "", "", err
+	}
+	return name, qualifier, nil
+}
+
+func evalSkillLevel(e *eval.Evaluator, arguments string) (any, error) {
+	name, specialization, err := evalNameAndQualifier(e, arguments)
+	if err != nil {
+		return nil, err
+	}
+	entity, ok := e.Resolver.(*Entity)
+	if !ok {
+		return -fxp.One, nil
+	}
+	level := -fxp.One
+	// The cached level is used, rather than recalculating, to avoid recursion when a skill depends upon an attribute
+	// whose expression references the skill.
+	for _, sk := range entity.SkillNamed(name, specialization, false, nil) {
+		if sk.LevelData.Level > level {
+			level = sk.LevelData.Level
+		}
+	}
+	return level, nil
+}
+
+func evalSpellLevel(e *eval.Evaluator, arguments string) (any, error) {
+	name, err := evalStringArg(e, arguments)
+	if err != nil {
+		return nil, err
+	}
+	entity, ok := e.Resolver.(*Entity)
+	if !ok {
+		return -fxp.One, nil
+	}
+	level := -fxp.One
+	Traverse(func(s *Spell) bool {
+		if strings.EqualFold(s.Name, name) && s.LevelData.Level > level {
+			level = s.LevelData.Level
+		}
+		return false
+	}, false, true, entity.Spells...)
+	return level, nil
+}
+
+func evalHasTrait(e *eval.Evaluator, arguments string) (any, error) {
+	name, err := evalStringArg(e, arguments)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	if entity, ok := e.Resolver.(*Entity); ok {
+		Traverse(func(t *Trait) bool {
+			found = strings.EqualFold(t.Name, name)
+			return found
+		}, true, false, entity.Traits...)
+	}
+	return found, nil
+}
+
+func evalHasSkill(e *eval.Evaluator, arguments string) (any, error) {
+	name, specialization, err := evalNameAndQualifier(e, arguments)
+	if err != nil {
+		return nil, err
+	}
+	if entity, ok := e.Resolver.(*Entity); ok {
+		return len(entity.SkillNamed(name, specialization, false, nil)) != 0, nil
+	}
+	return false, nil
+}
+
+func evalHasSpell(e *eval.Evaluator, arguments string) (any, error) {
+	name, err := evalStringArg(e, arguments)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	if entity, ok := e.Resolver.(*Entity); ok {
+		Traverse(func(s *Spell) bool {
+			found = strings.EqualFold(s.Name, name)
+			return found
+		}, false, true, entity.Spells...)
+	}
+	return found, nil
+}
+
+func evalHasEquipment(e *eval.Evaluator, arguments string) (any, error) {
+	name, err := evalStringArg(e, arguments)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	if entity, ok := e.Resolver.(*Entity); ok {
+		Traverse(func(eqp *Equipment) bool {
+			found = strings.EqualFold(eqp.Name, name)
+			return found
+		}, true, false, entity.CarriedEquipment...)
+	}
+	return found, nil
+}
+
+func evalCountTagged(e *eval.Evaluator, arguments string) (any, error) {
+	tag, err := evalStringArg(e, arguments)
+	if err != nil {
+		return nil, err
+	}
+	entity, ok := e.Resolver.(*Entity)
+	if !ok || tag == "" {
+		return fxp.Int(0), nil
+	}
+	count := 0
+	Traverse(func(t *Trait) bool {
+		if HasTag(tag, t.Tags) {
+			count++
+		}
+		return false
+	}, true, true, entity.Traits...)
+	Traverse(func(s *Skill) bool {
+		if HasTag(tag, s.Tags) {
+			count++
+		}
+		return false
+	}, false, true, entity.Skills...)
+	Traverse(func(s *Spell) bool {
+		if HasTag(tag, s.Tags) {
+			count++
+		}
+		return false
+	}, false, true, entity.Spells...)
+	Traverse(func(eqp *Equipment) bool {
+		if eqp.Equipped && HasTag(tag, eqp.Tags) {
+			count++
+		}
+		return false
+	}, true, true, entity.CarriedEquipment...)
+	return fxp.From(count), nil
+}
+
+func evalWeaponDamage(e *eval.Evaluator, arguments string) (any, error) {
+	name, usage, err := evalNameAndQualifier(e, arguments)
+	if err != nil {
+		return nil, err
+	}
+	entity, ok := e.Resolver.(*Entity)
+	if !ok {
+		return "", nil
+	}
+	for _, wt := range weapon.AllType {
+		for _, w := range entity.EquippedWeapons(wt) {
+			if strings.EqualFold(w.String(), name) && (usage == "" || strings.EqualFold(w.Usage, usage)) {
+				return w.Damage.ResolvedDamage(nil), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func evalEquippedWeight(e *eval.Evaluator, arguments string) (any, error) {
+	arg, _ := eval.NextArg(arguments)
+	forSkills, err := evalOptionalBool(e, arg)
+	if err != nil {
+		return nil, err
+	}
+	entity, ok := e.Resolver.(*Entity)
+	if !ok {
+		return fxp.Int(0), nil
+	}
+	var total fxp.Int
+	Traverse(func(eqp *Equipment) bool {
+		// An equipped container's extended weight already includes everything within it.
+		if eqp.Equipped && !hasEquippedAncestor(eqp) {
+			total += fxp.Int(eqp.ExtendedWeight(forSkills, entity.SheetSettings.DefaultWeightUnits))
+		}
+		return false
+	}, true, false, entity.CarriedEquipment...)
+	return total, nil
+}
+
+func hasEquippedAncestor(eqp *Equipment) bool {
+	for parent := eqp.Parent(); parent != nil; parent = parent.Parent() {
+		if parent.Equipped {
+			return true
+		}
+	}
+	return false
+}
+
+func evalCarriedWeight(e *eval.Evaluator, arguments string) (any, error) {
+	arg, _ := eval.NextArg(arguments)
+	forSkills, err := evalOptionalBool(e, arg)
+	if err != nil {
+		return nil, err
+	}
+	if entity, ok := e.Resolver.(*Entity); ok {
+		return fxp.Int(entity.WeightCarried(forSkills)), nil
+	}
+	return fxp.Int(0), nil
+}
+
+func evalWealth(e *eval.Evaluator, _ string) (any, error) {
+	if entity, ok := e.Resolver.(*Entity); ok {
+		return entity.WealthCarried(), nil
+	}
+	return fxp.Int(0), nil
+}
+
+func evalAttributePoints(e *eval.Evaluator, arguments string) (any, error) {
+	id, err := evalStringArg(e, arguments)
+	if err != nil {
+		return nil, err
+	}
+	if entity, ok := e.Resolver.(*Entity); ok {
+		if attr := entity.ResolveAttribute(strings.TrimPrefix(id, "$")); attr != nil {
+			return attr.PointCost(), nil
+		}
+	}
+	return fxp.Int(0), nil
+}
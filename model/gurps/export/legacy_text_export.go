@@ -226,6 +226,8 @@ func (ex *legacyExporter) emitKey(key string) error {
 		ex.writeEncodedText(ex.entity.Profile.Skin)
 	case "BIRTHDAY":
 		ex.writeEncodedText(ex.entity.Profile.Birthday)
+	case "CURRENT_DATE":
+		ex.writeEncodedText(ex.entity.CurrentDate)
 	case techLevelKey:
 		ex.writeEncodedText(ex.entity.Profile.TechLevel)
 	case "HAND":
@@ -1123,6 +1125,8 @@ func (ex *legacyExporter) processNotesLoop(buffer []byte) {
 				ex.writeEncodedText(n.PageRef)
 			case "NOTE":
 				ex.writeEncodedText(n.Text)
+			case "NOTE_DATE":
+				ex.writeEncodedText(n.Date)
 			case "NOTE_FORMATTED":
 				if strings.TrimSpace(n.Text) != "" {
 					for _, one := range strings.Split(n.Text, "\n") {
@@ -1282,7 +1286,7 @@ func (ex *legacyExporter) processMeleeKeys(key string, currentID int, w *gurps.W
 	case "BLOCK":
 		ex.writeEncodedText(w.ResolvedBlock(nil))
 	case "REACH":
-		ex.writeEncodedText(w.Reach)
+		ex.writeEncodedText(w.ResolvedReach())
 	case "ATTACK_MODES_LOOP_COUNT":
 		ex.writeEncodedText(strconv.Itoa(len(attackModes)))
 	case "ATTACK_MODES_LOOP_START":
@@ -1305,17 +1309,17 @@ func (ex *legacyExporter) processMeleeKeys(key string, currentID int, w *gurps.W
 func (ex *legacyExporter) processRangedKeys(key string, currentID int, w *gurps.Weapon, attackModes []*gurps.Weapon, buf []byte, index int) int {
 	switch key {
 	case "BULK":
-		ex.writeEncodedText(w.Bulk)
+		ex.writeEncodedText(w.ResolvedBulk())
 	case "ACCURACY":
-		ex.writeEncodedText(w.Accuracy)
+		ex.writeEncodedText(w.ResolvedAccuracy())
 	case "RANGE":
 		ex.writeEncodedText(w.ResolvedRange())
 	case "ROF":
-		ex.writeEncodedText(w.RateOfFire)
+		ex.writeEncodedText(w.ResolvedRateOfFire())
 	case "SHOTS":
-		ex.writeEncodedText(w.Shots)
+		ex.writeEncodedText(w.ShotsText())
 	case "RECOIL":
-		ex.writeEncodedText(w.Recoil)
+		ex.writeEncodedText(w.ResolvedRecoil())
 	case "ATTACK_MODES_LOOP_COUNT":
 		ex.writeEncodedText(strconv.Itoa(len(attackModes)))
 	case "ATTACK_MODES_LOOP_START":
@@ -0,0 +1,187 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package gurps
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/datafile"
+	"github.com/richardwilkes/json"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/eval"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// ExpressionVariable holds a variable referenced by an expression, along with its resolved value.
+type ExpressionVariable struct {
+	Name  string
+	Value string
+	Index int
+}
+
+// Resolved returns true if the variable resolved to a value.
+func (v *ExpressionVariable) Resolved() bool {
+	return v.Value != ""
+}
+
+// ExpressionDiagnostic holds the results of evaluating an expression, along with the information needed to explain
+// how that result was arrived at.
+type ExpressionDiagnostic struct {
+	Expression string
+	// Expanded is the expression with each resolved variable replaced by its value.
+	Expanded  string
+	Variables []ExpressionVariable
+	Result    fxp.Int
+	Err       error
+	// Problem describes the problem found at ErrorIndex, if any.
+	Problem string
+	// ErrorIndex is the byte offset within Expression where a problem was detected, or -1 if no specific location could
+	// be determined.
+	ErrorIndex int
+}
+
+// NewScratchEntity creates an entity for previewing expressions against, so that changes made while editing don't
+// disturb the original. The entity holds a copy of the source's data, or is a new PC if source is nil.
+func NewScratchEntity(source *Entity) *Entity {
+	if source != nil {
+		if data, err := json.Marshal(source); err == nil {
+			var entity Entity
+			if err = json.Unmarshal(data, &entity); err == nil {
+				return &entity
+			}
+		}
+	}
+	return NewEntity(datafile.PC)
+}
+
+// DiagnoseExpression evaluates the expression, collecting the variables it references and attempting to locate the
+// source of any error.
+func DiagnoseExpression(expression string, resolver eval.VariableResolver) *ExpressionDiagnostic {
+	d := &ExpressionDiagnostic{
+		Expression: expression,
+		ErrorIndex: -1,
+	}
+	if strings.TrimSpace(expression) == "" {
+		return d
+	}
+	d.collectVariables(resolver)
+	d.Result, d.Err = fxp.TryEvaluateToNumber(expression, resolver)
+	d.locateProblem()
+	if d.Err == nil && d.Problem != "" {
+		d.Err = errs.New(d.Problem)
+	}
+	return d
+}
+
+func (d *ExpressionDiagnostic) collectVariables(resolver eval.VariableResolver) {
+	var buffer strings.Builder
+	s := d.Expression
+	inQuote := false
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch == '"' {
+			inQuote = !inQuote
+		}
+		if ch != '$' || inQuote {
+			buffer.WriteByte(ch)
+			continue
+		}
+		j := i + 1
+		for j < len(s) && isExpressionNameChar(s[j]) {
+			j++
+		}
+		v := ExpressionVariable{
+			Name:  s[i+1 : j],
+			Index: i,
+		}
+		if v.Name != "" && resolver != nil {
+			v.Value = resolver.ResolveVariable(v.Name)
+		}
+		d.Variables = append(d.Variables, v)
+		if v.Resolved() {
+			buffer.WriteString(v.Value)
+		} else {
+			buffer.WriteString(s[i:j])
+		}
+		i = j - 1
+	}
+	d.Expanded = buffer.String()
+}
+
+func (d *ExpressionDiagnostic) locateProblem() {
+	for _, v := range d.Variables {
+		if !v.Resolved() {
+			d.setProblem(v.Index, i18n.Text("Unable to resolve variable $")+v.Name)
+			return
+		}
+	}
+	s := d.Expression
+	var open []int
+	inQuote := -1
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '"':
+			if inQuote == -1 {
+				inQuote = i
+			} else {
+				inQuote = -1
+			}
+		case inQuote != -1:
+		case ch == '(':
+			open = append(open, i)
+		case ch == ')':
+			if len(open) == 0 {
+				d.setProblem(i, i18n.Text("Unmatched closing parenthesis"))
+				return
+			}
+			open = open[:len(open)-1]
+		case isExpressionNameStartChar(ch) && (i == 0 || (!isExpressionNameChar(s[i-1]) && s[i-1] != '$')):
+			j := i + 1
+			for j < len(s) && isExpressionNameChar(s[j]) {
+				j++
+			}
+			k := j
+			for k < len(s) && s[k] == ' ' {
+				k++
+			}
+			if k < len(s) && s[k] == '(' {
+				if _, exists := fxp.EvalFuncs[s[i:j]]; !exists {
+					d.setProblem(i, i18n.Text("Unknown function ")+s[i:j])
+					return
+				}
+			}
+			i = j - 1
+		}
+	}
+	if inQuote != -1 {
+		d.setProblem(inQuote, i18n.Text("Unterminated quoted text"))
+		return
+	}
+	if len(open) != 0 {
+		d.setProblem(open[len(open)-1], i18n.Text("Unmatched opening parenthesis"))
+	}
+}
+
+func (d *ExpressionDiagnostic) setProblem(index int, problem string) {
+	d.ErrorIndex = index
+	d.Problem = problem
+}
+
+func isExpressionNameStartChar(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_'
+}
+
+func isExpressionNameChar(ch byte) bool {
+	return isExpressionNameStartChar(ch) || (ch >= '0' && ch <= '9') || ch == '.'
+}
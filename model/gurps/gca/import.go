@@ -0,0 +1,547 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package gca
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/datafile"
+	"github.com/richardwilkes/gcs/v5/model/gurps/gid"
+	"github.com/richardwilkes/gcs/v5/model/gurps/importer"
+	"github.com/richardwilkes/gcs/v5/model/gurps/measure"
+	"github.com/richardwilkes/gcs/v5/model/gurps/skill"
+	"github.com/richardwilkes/gcs/v5/model/gurps/trait"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xio"
+)
+
+var attributeIDs = map[string]string{
+	"st":             gid.Strength,
+	"strength":       gid.Strength,
+	"dx":             gid.Dexterity,
+	"dexterity":      gid.Dexterity,
+	"iq":             gid.Intelligence,
+	"intelligence":   gid.Intelligence,
+	"ht":             gid.Health,
+	"health":         gid.Health,
+	"hp":             gid.HitPoints,
+	"hit points":     gid.HitPoints,
+	"fp":             gid.FatiguePoints,
+	"fatigue points": gid.FatiguePoints,
+	"will":           gid.Will,
+	"per":            gid.Perception,
+	"perception":     gid.Perception,
+	"basic speed":    gid.BasicSpeed,
+	"basic move":     gid.BasicMove,
+}
+
+var primaryAttributes = map[string]bool{
+	gid.Strength:     true,
+	gid.Dexterity:    true,
+	gid.Intelligence: true,
+	gid.Health:       true,
+}
+
+type gcaImporter struct {
+	entity     *gurps.Entity
+	index      *importer.LibraryIndex
+	report     *importer.Report
+	attributes map[string]fxp.Int
+	levels     []levelCheck
+}
+
+// levelCheck records the level GCA calculated for a skill or spell, so that it can be compared against the level GCS
+// calculates once everything has been imported.
+type levelCheck struct {
+	description string
+	expected    fxp.Int
+	actual      func() fxp.Int
+}
+
+// ImportFile imports a GCA character file.
+func ImportFile(filePath string) (*gurps.Entity, *importer.Report, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, errs.NewWithCause(filePath, err)
+	}
+	defer xio.CloseIgnoringErrors(f)
+	var entity *gurps.Entity
+	var report *importer.Report
+	if entity, report, err = Import(f); err != nil {
+		return nil, nil, errs.NewWithCause(filePath, err)
+	}
+	return entity, report, nil
+}
+
+// Import reads a GURPS Character Assistant character file (.gca4 or .gca5) into a new character sheet. Traits, skills,
+// spells and equipment are matched by name against the items found in the configured libraries; anything that can't be
+// matched is added using the values recorded by GCA. The returned report describes what was done.
+func Import(r io.Reader) (*gurps.Entity, *importer.Report, error) {
+	root, err := parse(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	character := root
+	if !strings.EqualFold(root.XMLName.Local, "character") {
+		if character = root.child("character"); character == nil {
+			return nil, nil, errs.New(i18n.Text("no character data found"))
+		}
+	}
+	imp := &gcaImporter{
+		entity:     gurps.NewEntity(datafile.PC),
+		index:      importer.NewLibraryIndex(gurps.SettingsProvider.Libraries()),
+		report:     &importer.Report{},
+		attributes: make(map[string]fxp.Int),
+	}
+	imp.importProfile(character)
+	var skills, spells, equipment []*node
+	for _, section := range character.find("traits").children() {
+		items := section.all("trait")
+		switch strings.ToLower(section.XMLName.Local) {
+		case "attributes":
+			imp.importAttributes(items)
+		case "advantages", "disadvantages", "perks", "quirks", "features", "languages", "cultures":
+			for _, one := range items {
+				imp.importTrait(one)
+			}
+		case "skills":
+			skills = append(skills, items...)
+		case "spells":
+			spells = append(spells, items...)
+		case "equipment":
+			equipment = append(equipment, items...)
+		default:
+			for _, one := range items {
+				imp.report.Ignored = append(imp.report.Ignored, fmt.Sprintf("%s (%s)", fullName(one),
+					section.XMLName.Local))
+			}
+		}
+	}
+	imp.entity.Recalculate()
+	imp.applyAttributes(true)
+	imp.entity.Recalculate()
+	imp.applyAttributes(false)
+	imp.entity.Recalculate()
+	for _, one := range skills {
+		imp.importSkill(one)
+	}
+	for _, one := range spells {
+		imp.importSpell(one)
+	}
+	imp.importEquipment(equipment)
+	imp.importNotes(character)
+	imp.entity.Recalculate()
+	if total, ok := character.number("campaign", "totalpoints"); ok {
+		imp.entity.TotalPoints = total
+	} else {
+		imp.entity.SetUnspentPoints(0)
+	}
+	for _, one := range imp.levels {
+		if actual := one.actual().Trunc(); actual != one.expected.Trunc() {
+			imp.report.Differences = append(imp.report.Differences,
+				fmt.Sprintf(i18n.Text("%s: level %s in GCA, %s in GCS"), one.description, one.expected.Trunc().String(),
+					actual.String()))
+		}
+	}
+	return imp.entity, imp.report, nil
+}
+
+func fullName(n *node) string {
+	name := n.value("name")
+	if ext := n.value("nameext"); ext != "" {
+		name += " (" + ext + ")"
+	}
+	return name
+}
+
+// refValue returns the value of a child element, looking first on the item itself and then within its "ref" element,
+// where GCA places some of the data it carries through from its data files.
+func refValue(n *node, name string) string {
+	if value := n.value(name); value != "" {
+		return value
+	}
+	return n.value("ref", name)
+}
+
+func (imp *gcaImporter) importProfile(character *node) {
+	p := imp.entity.Profile
+	if name := character.value("name"); name != "" {
+		p.Name = name
+	}
+	p.PlayerName = character.value("player")
+	if tl := character.value("currenttl"); tl != "" {
+		p.TechLevel = tl
+	}
+	vitals := character.child("vitals")
+	if age := vitals.value("age"); age != "" {
+		p.Age = age
+	}
+	if height := vitals.value("height"); height != "" {
+		if length, err := measure.LengthFromString(height, measure.FeetAndInches); err == nil {
+			p.Height = length
+		}
+	}
+	if weight := vitals.value("weight"); weight != "" {
+		if w, err := measure.WeightFromString(weight, measure.Pound); err == nil {
+			p.Weight = w
+		}
+	}
+}
+
+func (imp *gcaImporter) importAttributes(items []*node) {
+	for _, one := range items {
+		name := one.value("name")
+		attrID, ok := attributeIDs[strings.ToLower(name)]
+		if !ok {
+			attrID, ok = attributeIDs[strings.ToLower(one.value("symbol"))]
+		}
+		score, hasScore := one.number("calcs", "score")
+		if !ok || !hasScore {
+			if points, _ := one.number("calcs", "points"); points != 0 {
+				imp.report.Ignored = append(imp.report.Ignored, fmt.Sprintf("%s [%s]", name, points.String()))
+			}
+			continue
+		}
+		imp.attributes[attrID] = score
+	}
+}
+
+func (imp *gcaImporter) applyAttributes(primary bool) {
+	for attrID, value := range imp.attributes {
+		if primaryAttributes[attrID] != primary {
+			continue
+		}
+		if attr, ok := imp.entity.Attributes.Set[attrID]; ok {
+			attr.SetMaximum(value)
+		}
+	}
+}
+
+type modifierData struct {
+	name     string
+	cost     fxp.Int
+	costType trait.ModifierCostType
+	parsed   bool
+}
+
+func parseModifier(n *node) modifierData {
+	m := modifierData{name: fullName(n)}
+	value := strings.ReplaceAll(strings.TrimSpace(n.value("value")), " ", "")
+	switch {
+	case strings.HasSuffix(value, "%"):
+		m.costType = trait.Percentage
+		value = strings.TrimSuffix(value, "%")
+	case strings.HasPrefix(strings.ToLower(value), "x"), strings.HasPrefix(value, "*"):
+		m.costType = trait.Multiplier
+		value = value[1:]
+	default:
+		m.costType = trait.Points
+	}
+	if cost, err := fxp.FromString(strings.TrimPrefix(value, "+")); err == nil {
+		m.cost = cost
+		m.parsed = true
+	}
+	return m
+}
+
+func (imp *gcaImporter) importTrait(n *node) {
+	name := fullName(n)
+	points, _ := n.number("calcs", "points")
+	level, _ := n.number("calcs", "level")
+	var modifiers []modifierData
+	for _, one := range n.child("modifiers").all("modifier") {
+		modifiers = append(modifiers, parseModifier(one))
+	}
+	var t *gurps.Trait
+	if found := imp.index.Trait(name); found != nil {
+		t = found.Clone(imp.entity, nil, false)
+		if level > 0 && t.IsLeveled() {
+			t.Levels = level
+		}
+		imp.applyLibraryModifiers(t, modifiers)
+		imp.report.Matched = append(imp.report.Matched, name)
+		if adjusted := t.AdjustedPoints(); adjusted != points {
+			imp.report.Differences = append(imp.report.Differences,
+				fmt.Sprintf(i18n.Text("%s: %s points in GCA, %s in GCS"), name, points.String(), adjusted.String()))
+		}
+	} else {
+		t = gurps.NewTrait(imp.entity, nil, false)
+		t.Name = name
+		base := points
+		allParsed := true
+		for _, one := range modifiers {
+			allParsed = allParsed && one.parsed
+		}
+		if allParsed {
+			if premods, ok := n.number("calcs", "premodspoints"); ok {
+				base = premods
+			}
+		}
+		if level > 1 || (level == 1 && isLeveled(n)) {
+			t.CanLevel = true
+			t.Levels = level
+			t.PointsPerLevel = base.Div(level)
+		} else {
+			t.BasePoints = base
+		}
+		for _, one := range modifiers {
+			t.Modifiers = append(t.Modifiers, newTraitModifier(imp.entity, one, allParsed))
+		}
+		imp.report.Placeholders = append(imp.report.Placeholders, name)
+	}
+	imp.entity.Traits = append(imp.entity.Traits, t)
+}
+
+// isLeveled returns true if the GCA trait has levels. GCA records a level of 1 for traits that don't have levels, so the
+// level alone can't be relied upon.
+func isLeveled(n *node) bool {
+	return refValue(n, "upto") != "" || refValue(n, "levelnames") != "" || strings.Contains(refValue(n, "cost"), "/")
+}
+
+// applyLibraryModifiers enables the library modifiers that GCA had applied and disables the rest. Modifiers that the
+// library trait doesn't offer are added.
+func (imp *gcaImporter) applyLibraryModifiers(t *gurps.Trait, modifiers []modifierData) {
+	used := make(map[int]bool)
+	gurps.Traverse(func(mod *gurps.TraitModifier) bool {
+		mod.Disabled = true
+		for i, one := range modifiers {
+			if !used[i] && strings.EqualFold(mod.Name, one.name) {
+				used[i] = true
+				mod.Disabled = false
+				break
+			}
+		}
+		return false
+	}, false, true, t.Modifiers...)
+	for i, one := range modifiers {
+		if !used[i] {
+			t.Modifiers = append(t.Modifiers, newTraitModifier(imp.entity, one, true))
+		}
+	}
+}
+
+func newTraitModifier(entity *gurps.Entity, data modifierData, useCost bool) *gurps.TraitModifier {
+	mod := gurps.NewTraitModifier(entity, nil, false)
+	mod.Name = data.name
+	if useCost {
+		mod.CostType = data.costType
+		mod.Cost = data.cost
+	} else {
+		mod.CostType = trait.Points
+	}
+	return mod
+}
+
+// parseType extracts the attribute and difficulty from a GCA skill type, such as "DX/A".
+func parseType(text string) (attrID string, difficulty skill.Difficulty, ok bool) {
+	parts := strings.SplitN(text, "/", 2)
+	if len(parts) != 2 {
+		return "", 0, false
+	}
+	if attrID, ok = attributeIDs[strings.ToLower(strings.TrimSpace(parts[0]))]; !ok {
+		return "", 0, false
+	}
+	return attrID, skill.ExtractDifficulty(strings.TrimSpace(parts[1])), true
+}
+
+func (imp *gcaImporter) importSkill(n *node) {
+	name := n.value("name")
+	specialization := n.value("nameext")
+	description := fullName(n)
+	kind := n.value("calcs", "type")
+	if kind == "" {
+		kind = n.value("type")
+	}
+	var s *gurps.Skill
+	found := imp.index.Skill(name, specialization)
+	matched := found != nil
+	if matched {
+		s = found.Clone(imp.entity, nil, false)
+		if specialization != "" && (s.Specialization == "" || strings.Contains(s.Specialization, "@")) {
+			s.Specialization = specialization
+		}
+		imp.report.Matched = append(imp.report.Matched, description)
+	} else {
+		if strings.HasPrefix(strings.ToLower(kind), "tech") {
+			s = gurps.NewTechnique(imp.entity, nil, specialization)
+		} else {
+			s = gurps.NewSkill(imp.entity, nil, false)
+			s.Specialization = specialization
+		}
+		s.Name = name
+		if attrID, difficulty, ok := parseType(kind); ok {
+			if s.TechniqueDefault == nil {
+				s.Difficulty.Attribute = attrID
+			}
+			s.Difficulty.Difficulty = difficulty
+		}
+		imp.report.Placeholders = append(imp.report.Placeholders, description)
+	}
+	if tl := n.value("tl"); tl != "" && (s.TechLevel != nil || !matched) {
+		s.TechLevel = &tl
+	}
+	if points, ok := n.number("calcs", "points"); ok {
+		s.SetRawPoints(points)
+	}
+	imp.entity.Skills = append(imp.entity.Skills, s)
+	if level, ok := n.number("calcs", "level"); ok {
+		imp.levels = append(imp.levels, levelCheck{
+			description: description,
+			expected:    level,
+			actual:      func() fxp.Int { return s.LevelData.Level },
+		})
+	}
+}
+
+func (imp *gcaImporter) importSpell(n *node) {
+	name := fullName(n)
+	var s *gurps.Spell
+	found := imp.index.Spell(name)
+	matched := found != nil
+	if matched {
+		s = found.Clone(imp.entity, nil, false)
+		imp.report.Matched = append(imp.report.Matched, name)
+	} else {
+		s = gurps.NewSpell(imp.entity, nil, false)
+		s.Name = name
+		if attrID, difficulty, ok := parseType(n.value("calcs", "type")); ok {
+			s.Difficulty.Attribute = attrID
+			s.Difficulty.Difficulty = difficulty
+		}
+		if college := refValue(n, "cat"); college != "" {
+			s.College = nil
+			for _, one := range strings.Split(college, ",") {
+				if one = strings.TrimSpace(one); one != "" {
+					s.College = append(s.College, one)
+				}
+			}
+		}
+		s.Class = refValue(n, "class")
+		s.CastingCost = refValue(n, "castingcost")
+		s.CastingTime = refValue(n, "time")
+		s.Duration = refValue(n, "duration")
+		imp.report.Placeholders = append(imp.report.Placeholders, name)
+	}
+	if tl := n.value("tl"); tl != "" && (s.TechLevel != nil || !matched) {
+		s.TechLevel = &tl
+	}
+	if points, ok := n.number("calcs", "points"); ok {
+		s.SetRawPoints(points)
+	}
+	imp.entity.Spells = append(imp.entity.Spells, s)
+	if level, ok := n.number("calcs", "level"); ok {
+		imp.levels = append(imp.levels, levelCheck{
+			description: name,
+			expected:    level,
+			actual:      func() fxp.Int { return s.LevelData.Level },
+		})
+	}
+}
+
+// equipmentKey normalizes the keys GCA uses to link equipment to its container, which are written as "k123" in the
+// parentkey element and as "123" in the idkey attribute.
+func equipmentKey(key string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(key)), "k")
+}
+
+func (imp *gcaImporter) importEquipment(items []*node) {
+	parents := make(map[string]string)
+	for _, one := range items {
+		if key := equipmentKey(one.attr("idkey")); key != "" {
+			parents[key] = equipmentKey(refValue(one, "parentkey"))
+		}
+	}
+	children := make(map[string][]*node)
+	var roots []*node
+	for _, one := range items {
+		key := equipmentKey(one.attr("idkey"))
+		parent := equipmentKey(refValue(one, "parentkey"))
+		_, parentExists := parents[parent]
+		switch {
+		case parent == "" || !parentExists:
+			roots = append(roots, one)
+		case key != "" && inEquipmentCycle(key, parents):
+			imp.report.Warnings = append(imp.report.Warnings,
+				fmt.Sprintf(i18n.Text("%s is contained within itself, so was placed at the top level"), fullName(one)))
+			roots = append(roots, one)
+		default:
+			children[parent] = append(children[parent], one)
+		}
+	}
+	imp.entity.CarriedEquipment = append(imp.entity.CarriedEquipment, imp.createEquipment(nil, roots, children)...)
+}
+
+// inEquipmentCycle returns true if following the chain of containers from the equipment with the given key leads back
+// to it.
+func inEquipmentCycle(key string, parents map[string]string) bool {
+	current := key
+	for i := 0; i < len(parents); i++ {
+		var ok bool
+		if current, ok = parents[current]; !ok || current == "" {
+			return false
+		}
+		if current == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (imp *gcaImporter) createEquipment(parent *gurps.Equipment, items []*node,
+	children map[string][]*node) []*gurps.Equipment {
+	list := make([]*gurps.Equipment, 0, len(items))
+	for _, n := range items {
+		name := fullName(n)
+		contents := children[equipmentKey(n.attr("idkey"))]
+		var eqp *gurps.Equipment
+		if found := imp.index.Equipment(name); found != nil && (len(contents) == 0 || found.Container()) {
+			eqp = found.Clone(imp.entity, parent, false)
+			imp.report.Matched = append(imp.report.Matched, name)
+		} else {
+			eqp = gurps.NewEquipment(imp.entity, parent, len(contents) != 0)
+			eqp.Name = name
+			if value, ok := n.number("calcs", "basecost"); ok {
+				eqp.Value = value
+			}
+			if weight := n.value("calcs", "baseweight"); weight != "" {
+				if w, err := measure.WeightFromString(weight, measure.Pound); err == nil {
+					eqp.Weight = w
+				}
+			}
+			imp.report.Placeholders = append(imp.report.Placeholders, name)
+		}
+		if count, ok := n.number("calcs", "count"); ok && count > 0 {
+			eqp.Quantity = count
+		}
+		if len(contents) != 0 {
+			eqp.Children = append(eqp.Children, imp.createEquipment(eqp, contents, children)...)
+		}
+		list = append(list, eqp)
+	}
+	return list
+}
+
+func (imp *gcaImporter) importNotes(character *node) {
+	for _, one := range []string{"description", "notes"} {
+		if text := character.value(one); text != "" {
+			note := gurps.NewNote(imp.entity, nil, false)
+			note.Text = text
+			imp.entity.Notes = append(imp.entity.Notes, note)
+		}
+	}
+}
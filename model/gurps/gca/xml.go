@@ -0,0 +1,131 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package gca
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/errs"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// node is a generic XML element. GCA has changed its file layout a number of times over the years, so rather than
+// decoding into fixed structures, the importer walks the tree looking for the elements it understands.
+type node struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Text     string     `xml:",chardata"`
+	Children []*node    `xml:",any"`
+}
+
+func parse(r io.Reader) (*node, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.CharsetReader = charsetReader
+	var root node
+	if err := decoder.Decode(&root); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &root, nil
+}
+
+// charsetReader decodes the legacy encodings that GCA4 declares for its files. GCA4 writes them using the Windows code
+// page, even when it declares them to be ISO-8859-1, so anything that isn't UTF-8 is decoded as Windows-1252.
+func charsetReader(label string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(label)) {
+	case "", "utf-8", "utf8":
+		return input, nil
+	default:
+		return charmap.Windows1252.NewDecoder().Reader(input), nil
+	}
+}
+
+// child returns the first direct child with the given name, ignoring case.
+func (n *node) child(name string) *node {
+	if n == nil {
+		return nil
+	}
+	for _, one := range n.Children {
+		if strings.EqualFold(one.XMLName.Local, name) {
+			return one
+		}
+	}
+	return nil
+}
+
+// find follows the given path of child names.
+func (n *node) find(path ...string) *node {
+	for _, name := range path {
+		if n = n.child(name); n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// children returns the direct children.
+func (n *node) children() []*node {
+	if n == nil {
+		return nil
+	}
+	return n.Children
+}
+
+// all returns the direct children with the given name, ignoring case.
+func (n *node) all(name string) []*node {
+	if n == nil {
+		return nil
+	}
+	var list []*node
+	for _, one := range n.Children {
+		if strings.EqualFold(one.XMLName.Local, name) {
+			list = append(list, one)
+		}
+	}
+	return list
+}
+
+// value returns the trimmed text of the element found by following the path.
+func (n *node) value(path ...string) string {
+	if found := n.find(path...); found != nil {
+		return strings.TrimSpace(found.Text)
+	}
+	return ""
+}
+
+// number returns the numeric value of the element found by following the path.
+func (n *node) number(path ...string) (fxp.Int, bool) {
+	text := strings.TrimPrefix(strings.ReplaceAll(n.value(path...), ",", ""), "+")
+	if text == "" {
+		return 0, false
+	}
+	value, err := fxp.FromString(text)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+func (n *node) attr(name string) string {
+	if n == nil {
+		return ""
+	}
+	for _, one := range n.Attrs {
+		if strings.EqualFold(one.Name.Local, name) {
+			return strings.TrimSpace(one.Value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,184 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package importer
+
+import (
+	"path"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/log/jot"
+)
+
+// LibraryIndex provides lookup by name of the traits, skills, spells and equipment found in the libraries. When more
+// than one library provides an item with the same name, the first one found is used.
+type LibraryIndex struct {
+	traits    map[string]*gurps.Trait
+	skills    map[string][]*gurps.Skill
+	spells    map[string]*gurps.Spell
+	equipment map[string]*gurps.Equipment
+}
+
+// NewLibraryIndex creates a new index of the contents of the given libraries.
+func NewLibraryIndex(libraries library.Libraries) *LibraryIndex {
+	index := &LibraryIndex{
+		traits:    make(map[string]*gurps.Trait),
+		skills:    make(map[string][]*gurps.Skill),
+		spells:    make(map[string]*gurps.Spell),
+		equipment: make(map[string]*gurps.Equipment),
+	}
+	for _, set := range library.ScanForLibraryFiles(libraries, library.TraitsExt, library.SkillsExt, library.SpellsExt,
+		library.EquipmentExt) {
+		for _, ref := range set.List {
+			if err := index.load(ref); err != nil {
+				jot.Warn(errs.NewWithCause(ref.FilePath, err))
+			}
+		}
+	}
+	return index
+}
+
+func (x *LibraryIndex) load(ref *library.NamedFileRef) error {
+	switch strings.ToLower(path.Ext(ref.FilePath)) {
+	case library.TraitsExt:
+		list, err := gurps.NewTraitsFromFile(ref.FileSystem, ref.FilePath)
+		if err != nil {
+			return err
+		}
+		x.AddTraits(list...)
+	case library.SkillsExt:
+		list, err := gurps.NewSkillsFromFile(ref.FileSystem, ref.FilePath)
+		if err != nil {
+			return err
+		}
+		x.AddSkills(list...)
+	case library.SpellsExt:
+		list, err := gurps.NewSpellsFromFile(ref.FileSystem, ref.FilePath)
+		if err != nil {
+			return err
+		}
+		x.AddSpells(list...)
+	case library.EquipmentExt:
+		list, err := gurps.NewEquipmentFromFile(ref.FileSystem, ref.FilePath)
+		if err != nil {
+			return err
+		}
+		x.AddEquipment(list...)
+	}
+	return nil
+}
+
+// AddTraits adds the traits, including those within containers, to the index. Traits with the same name as one already
+// present are ignored.
+func (x *LibraryIndex) AddTraits(list ...*gurps.Trait) {
+	gurps.Traverse(func(t *gurps.Trait) bool {
+		key := indexKey(t.Name)
+		if _, exists := x.traits[key]; !exists {
+			x.traits[key] = t
+		}
+		return false
+	}, false, true, list...)
+}
+
+// AddSkills adds the skills and techniques, including those within containers, to the index.
+func (x *LibraryIndex) AddSkills(list ...*gurps.Skill) {
+	gurps.Traverse(func(s *gurps.Skill) bool {
+		key := indexKey(s.Name)
+		x.skills[key] = append(x.skills[key], s)
+		return false
+	}, false, true, list...)
+}
+
+// AddSpells adds the spells, including those within containers, to the index. Spells with the same name as one already
+// present are ignored.
+func (x *LibraryIndex) AddSpells(list ...*gurps.Spell) {
+	gurps.Traverse(func(s *gurps.Spell) bool {
+		key := indexKey(s.Name)
+		if _, exists := x.spells[key]; !exists {
+			x.spells[key] = s
+		}
+		return false
+	}, false, true, list...)
+}
+
+// AddEquipment adds the equipment, including that within containers, to the index. Equipment with the same name as one
+// already present is ignored.
+func (x *LibraryIndex) AddEquipment(list ...*gurps.Equipment) {
+	gurps.Traverse(func(e *gurps.Equipment) bool {
+		key := indexKey(e.Name)
+		if _, exists := x.equipment[key]; !exists {
+			x.equipment[key] = e
+		}
+		return false
+	}, false, false, list...)
+}
+
+func indexKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Trait looks up a trait, first by its full name and then, if the name has a parenthetical qualifier, by the name
+// without it.
+func (x *LibraryIndex) Trait(name string) *gurps.Trait {
+	if t, ok := x.traits[indexKey(name)]; ok {
+		return t
+	}
+	if i := strings.IndexByte(name, '('); i > 0 {
+		if t, ok := x.traits[indexKey(name[:i])]; ok {
+			return t
+		}
+	}
+	return nil
+}
+
+// Skill looks up a skill by name, preferring one whose specialization matches. Skills whose specialization is a
+// placeholder to be filled in, or that have no specialization, are used when there is no exact match.
+func (x *LibraryIndex) Skill(name, specialization string) *gurps.Skill {
+	list := x.skills[indexKey(name)]
+	if len(list) == 0 {
+		return nil
+	}
+	var fallback *gurps.Skill
+	for _, s := range list {
+		if strings.EqualFold(s.Specialization, specialization) {
+			return s
+		}
+		if fallback == nil && (s.Specialization == "" || strings.Contains(s.Specialization, "@")) {
+			fallback = s
+		}
+	}
+	if fallback == nil {
+		fallback = list[0]
+	}
+	return fallback
+}
+
+// Spell looks up a spell by name.
+func (x *LibraryIndex) Spell(name string) *gurps.Spell {
+	return x.spells[indexKey(name)]
+}
+
+// Equipment looks up equipment, first by its full name and then, if the name has a parenthetical qualifier, by the
+// name without it.
+func (x *LibraryIndex) Equipment(name string) *gurps.Equipment {
+	if e, ok := x.equipment[indexKey(name)]; ok {
+		return e
+	}
+	if i := strings.IndexByte(name, '('); i > 0 {
+		if e, ok := x.equipment[indexKey(name[:i])]; ok {
+			return e
+		}
+	}
+	return nil
+}
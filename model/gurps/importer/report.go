@@ -0,0 +1,58 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package importer
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// Report holds the results of an import.
+type Report struct {
+	Matched      []string
+	Placeholders []string
+	Differences  []string
+	Ignored      []string
+	Warnings     []string
+}
+
+// String returns a human-readable description of the report.
+func (r *Report) String() string {
+	var buffer strings.Builder
+	for _, one := range []struct {
+		title string
+		list  []string
+	}{
+		{title: i18n.Text("Matched against the libraries:"), list: r.Matched},
+		{title: i18n.Text("Added as placeholders, which should be reviewed:"), list: r.Placeholders},
+		{title: i18n.Text("Imported with values that differ from the original:"), list: r.Differences},
+		{title: i18n.Text("Ignored:"), list: r.Ignored},
+		{title: i18n.Text("Warnings:"), list: r.Warnings},
+	} {
+		if len(one.list) == 0 {
+			continue
+		}
+		if buffer.Len() != 0 {
+			buffer.WriteString("\n\n")
+		}
+		buffer.WriteString(one.title)
+		for _, item := range one.list {
+			buffer.WriteString("\n- ")
+			buffer.WriteString(item)
+		}
+	}
+	if buffer.Len() == 0 {
+		return i18n.Text("Nothing was found to import.")
+	}
+	return buffer.String()
+}
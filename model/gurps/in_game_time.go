@@ -0,0 +1,280 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package gurps
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/settings"
+	"github.com/richardwilkes/gcs/v5/model/gurps/trait"
+	"github.com/richardwilkes/rpgtools/calendar"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/txt"
+)
+
+// CalendarRef returns the calendar used for in-game dates: the one chosen in the sheet settings, if any, otherwise the
+// one chosen in the general settings.
+func (e *Entity) CalendarRef() *settings.CalendarRef {
+	libraries := SettingsProvider.Libraries()
+	if s := SheetSettingsFor(e); s != nil && s.CalendarName != "" {
+		if ref := settings.LookupCalendarRef(s.CalendarName, libraries); ref != nil {
+			return ref
+		}
+	}
+	return SettingsProvider.GeneralSettings().CalendarRef(libraries)
+}
+
+// Calendar returns the calendar used for in-game dates.
+func (e *Entity) Calendar() *calendar.Calendar {
+	return e.CalendarRef().Calendar
+}
+
+// Date returns the current in-game date. Returns false if no valid date has been set.
+func (e *Entity) Date() (calendar.Date, bool) {
+	if strings.TrimSpace(e.CurrentDate) == "" {
+		return calendar.Date{}, false
+	}
+	date, err := e.Calendar().ParseDate(e.CurrentDate)
+	if err != nil {
+		return calendar.Date{}, false
+	}
+	return date, true
+}
+
+// ValidDate returns true if the text is empty, meaning the date is unset, or is a valid date in the entity's calendar.
+func (e *Entity) ValidDate(text string) bool {
+	if strings.TrimSpace(text) == "" {
+		return true
+	}
+	_, err := e.Calendar().ParseDate(text)
+	return err == nil
+}
+
+// SetDate sets the current in-game date, recomputing the age from the birthday.
+func (e *Entity) SetDate(date calendar.Date) {
+	current, hasCurrent := e.Date()
+	e.updateAge(current, hasCurrent, date)
+	e.CurrentDate = date.String()
+}
+
+// AdvanceTime moves the in-game time forward by the specified number of seconds, advancing any active spells and timed
+// effects by the same amount. If a valid current date has been set, it moves forward by each full day that elapses,
+// with the time of day carrying the remainder over to the next advance. If applyCostOfLiving is true, the cost of
+// living for the elapsed days is then debited from the entity's cash; should that fail, the date and time are left as
+// they were.
+func (e *Entity) AdvanceTime(seconds int, applyCostOfLiving bool) error {
+	if seconds < 0 {
+		return errs.New(i18n.Text("Time can't be moved backward."))
+	}
+	date := e.CurrentDate
+	timeOfDay := e.TimeOfDay
+	age := e.Profile.Age
+	total := timeOfDay + seconds
+	days := total / SecondsPerDay
+	if days > 0 {
+		if current, ok := e.Date(); ok {
+			e.SetDate(e.Calendar().NewDateByDays(current.Days + days))
+		}
+	}
+	e.TimeOfDay = total % SecondsPerDay
+	if applyCostOfLiving {
+		if cost := e.CostOfLivingFor(days); cost > 0 {
+			if err := e.AdjustCash(-cost, ""); err != nil {
+				e.CurrentDate = date
+				e.TimeOfDay = timeOfDay
+				e.Profile.Age = age
+				return err
+			}
+		}
+	}
+	e.AdvanceActiveSpells(seconds)
+	e.AdvanceEffects(seconds)
+	return nil
+}
+
+// FormatTimeOfDay returns the time of day, such as "14:05:00".
+func (e *Entity) FormatTimeOfDay() string {
+	return fmt.Sprintf("%02d:%02d:%02d", e.TimeOfDay/SecondsPerHour, (e.TimeOfDay%SecondsPerHour)/SecondsPerMinute,
+		e.TimeOfDay%SecondsPerMinute)
+}
+
+// CostOfLivingMultiplier returns the multiplier to apply to the monthly cost of living, taking into account any
+// cost-of-living increases from self-control rolls.
+func (e *Entity) CostOfLivingMultiplier() fxp.Int {
+	percent := 100
+	Traverse(func(t *Trait) bool {
+		if t.CR != trait.None && (t.CRAdj == MinorCostOfLivingIncrease || t.CRAdj == MajorCostOfLivingIncrease) {
+			percent += t.CRAdj.Adjustment(t.CR)
+		}
+		return false
+	}, true, false, e.Traits...)
+	return fxp.From(percent).Div(fxp.Hundred)
+}
+
+// CostOfLivingFor returns the cost of living, in the base currency, for the given number of days.
+func (e *Entity) CostOfLivingFor(days int) fxp.Int {
+	if days <= 0 || e.CostOfLiving <= 0 {
+		return 0
+	}
+	cal := e.Calendar()
+	monthsPerYear := len(cal.Months)
+	if monthsPerYear == 0 {
+		return 0
+	}
+	daysPerMonth := fxp.From(cal.MinDaysPerYear()).Div(fxp.From(monthsPerYear))
+	return e.CostOfLiving.Mul(e.CostOfLivingMultiplier()).Mul(fxp.From(days).Div(daysPerMonth))
+}
+
+// Birthday returns the month, day and year of the entity's birthday. The month may be written before or after the day,
+// and the year is optional; it is 0 if not present. Returns false if the birthday can't be determined.
+func (e *Entity) Birthday() (month, day, year int, ok bool) {
+	text := strings.TrimSpace(e.Profile.Birthday)
+	if text == "" {
+		return 0, 0, 0, false
+	}
+	cal := e.Calendar()
+	if date, err := cal.ParseDate(text); err == nil {
+		return date.Month(), date.DayInMonth(), date.Year(), true
+	}
+	var numbers []int
+	previousEra := false
+	for _, part := range strings.FieldsFunc(text, func(ch rune) bool {
+		return unicode.IsSpace(ch) || ch == ',' || ch == '.' || ch == '/'
+	}) {
+		if v, err := strconv.Atoi(strings.TrimRight(strings.ToLower(part), "stndrh")); err == nil {
+			numbers = append(numbers, v)
+			continue
+		}
+		if m := monthNumber(cal, part); m != 0 && month == 0 {
+			month = m
+			continue
+		}
+		if cal.PreviousEra != "" && cal.PreviousEra != cal.Era && strings.EqualFold(part, cal.PreviousEra) {
+			previousEra = true
+			continue
+		}
+		if !strings.EqualFold(part, cal.Era) {
+			return 0, 0, 0, false
+		}
+	}
+	if month == 0 || len(numbers) == 0 || len(numbers) > 2 {
+		return 0, 0, 0, false
+	}
+	day = numbers[0]
+	if len(numbers) == 2 {
+		if year = numbers[1]; year == 0 {
+			return 0, 0, 0, false
+		}
+		if previousEra {
+			year = -year
+		}
+	}
+	maxDays := cal.Months[month-1].Days
+	if cal.IsLeapMonth(month) {
+		maxDays++
+	}
+	if day < 1 || day > maxDays {
+		return 0, 0, 0, false
+	}
+	return month, day, year, true
+}
+
+func monthNumber(cal *calendar.Calendar, text string) int {
+	for i, month := range cal.Months {
+		if strings.EqualFold(text, month.Name) || strings.EqualFold(text, txt.FirstN(month.Name, 3)) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// BirthdayIn returns the date of the entity's birthday within the given year. A birthday on a leap day falls on the
+// last day of the month in other years. Returns false if the birthday can't be determined.
+func (e *Entity) BirthdayIn(year int) (calendar.Date, bool) {
+	month, day, _, ok := e.Birthday()
+	if !ok {
+		return calendar.Date{}, false
+	}
+	return birthdayIn(e.Calendar(), month, day, year)
+}
+
+func birthdayIn(cal *calendar.Calendar, month, day, year int) (calendar.Date, bool) {
+	maxDays := cal.Months[month-1].Days
+	if cal.IsLeapMonth(month) && cal.IsLeapYear(year) {
+		maxDays++
+	}
+	if day > maxDays {
+		day = maxDays
+	}
+	date, err := cal.NewDate(month, day, year)
+	if err != nil {
+		return calendar.Date{}, false
+	}
+	return date, true
+}
+
+// ageOn returns the age on the given date of someone born on the given month, day and year.
+func ageOn(cal *calendar.Calendar, month, day, year int, date calendar.Date) (int, bool) {
+	birthday, ok := birthdayIn(cal, month, day, date.Year())
+	if !ok {
+		return 0, false
+	}
+	age := date.Year() - year
+	if year < 0 && date.Year() > 0 {
+		age-- // There is no year 0
+	}
+	if date.Days < birthday.Days {
+		age--
+	}
+	if age < 0 {
+		age = 0
+	}
+	return age, true
+}
+
+// updateAge recomputes the age for the new date. When the birthday doesn't include a year, the year of birth is
+// inferred from the age on the prior date.
+func (e *Entity) updateAge(from calendar.Date, hasFrom bool, to calendar.Date) {
+	month, day, year, ok := e.Birthday()
+	if !ok {
+		return
+	}
+	cal := e.Calendar()
+	if year == 0 {
+		if !hasFrom {
+			return
+		}
+		age, err := strconv.Atoi(strings.TrimSpace(e.Profile.Age))
+		if err != nil || age < 0 {
+			return
+		}
+		var birthday calendar.Date
+		if birthday, ok = birthdayIn(cal, month, day, from.Year()); !ok {
+			return
+		}
+		year = from.Year() - age
+		if from.Days < birthday.Days {
+			year--
+		}
+		if year <= 0 && from.Year() > 0 {
+			year-- // There is no year 0
+		}
+	}
+	if age, valid := ageOn(cal, month, day, year, to); valid {
+		e.Profile.Age = strconv.Itoa(age)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package measure
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/json"
+	"golang.org/x/exp/constraints"
+)
+
+// Area contains a fixed-point value in square inches.
+type Area fxp.Int
+
+// AreaFromInteger creates a new Area.
+func AreaFromInteger[T constraints.Integer](value T, unit AreaUnits) Area {
+	return Area(unit.ToSquareInches(fxp.From(value)))
+}
+
+// AreaFromStringForced creates a new Area. May have any of the known Area suffixes or no notation at all, in which
+// case defaultUnits is used.
+func AreaFromStringForced(text string, defaultUnits AreaUnits) Area {
+	area, err := AreaFromString(text, defaultUnits)
+	if err != nil {
+		return 0
+	}
+	return area
+}
+
+// AreaFromString creates a new Area. May have any of the known Area suffixes or no notation at all, in which case
+// defaultUnits is used.
+func AreaFromString(text string, defaultUnits AreaUnits) (Area, error) {
+	text = strings.TrimLeft(strings.TrimSpace(text), "+")
+	for _, unit := range AllAreaUnits {
+		if strings.HasSuffix(text, unit.Key()) {
+			value, err := fxp.FromString(strings.TrimSpace(strings.TrimSuffix(text, unit.Key())))
+			if err != nil {
+				return 0, err
+			}
+			return Area(unit.ToSquareInches(value)), nil
+		}
+	}
+	// No matches, so let's use our passed-in default units
+	value, err := fxp.FromString(strings.TrimSpace(text))
+	if err != nil {
+		return 0, err
+	}
+	return Area(defaultUnits.ToSquareInches(value)), nil
+}
+
+func (a Area) String() string {
+	return SquareInch.Format(a)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a Area) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *Area) UnmarshalJSON(in []byte) error {
+	var s string
+	if err := json.Unmarshal(in, &s); err != nil {
+		return err
+	}
+	var err error
+	*a, err = AreaFromString(s, SquareInch)
+	return err
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+package measure_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps/measure"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAreaConversion(t *testing.T) {
+	assert.Equal(t, "1 sq in", measure.SquareInch.Format(measure.AreaFromInteger(1, measure.SquareInch)))
+	assert.Equal(t, "144 sq in", measure.SquareInch.Format(measure.AreaFromInteger(1, measure.SquareFoot)))
+	assert.Equal(t, "1296 sq in", measure.SquareInch.Format(measure.AreaFromInteger(1, measure.SquareYard)))
+	assert.Equal(t, "9 sq ft", measure.SquareFoot.Format(measure.AreaFromInteger(1, measure.SquareYard)))
+	assert.Equal(t, "1 sq m", measure.SquareMeter.Format(measure.AreaFromInteger(1, measure.SquareYard)))
+	assert.Equal(t, "4840 sq yd", measure.SquareYard.Format(measure.AreaFromInteger(1, measure.Acre)))
+	assert.Equal(t, "640 ac", measure.Acre.Format(measure.AreaFromInteger(1, measure.SquareMile)))
+	assert.Equal(t, "10000 sq cm", measure.SquareCentimeter.Format(measure.AreaFromInteger(1, measure.SquareMeter)))
+	assert.Equal(t, "100 ha", measure.Hectare.Format(measure.AreaFromInteger(1, measure.SquareKilometer)))
+
+	a, err := measure.AreaFromString("1", measure.SquareInch)
+	assert.NoError(t, err)
+	assert.Equal(t, "1 sq in", a.String())
+	a, err = measure.AreaFromString("2", measure.SquareFoot)
+	assert.NoError(t, err)
+	assert.Equal(t, "288 sq in", a.String())
+	a, err = measure.AreaFromString(" +2.5   sq ft  ", measure.SquareInch)
+	assert.NoError(t, err)
+	assert.Equal(t, "2.5 sq ft", measure.SquareFoot.Format(a))
+	a, err = measure.AreaFromString("250 sq cm", measure.SquareInch)
+	assert.NoError(t, err)
+	assert.Equal(t, "32.4 sq in", a.String())
+	a, err = measure.AreaFromString("1000 sq mi", measure.SquareInch)
+	assert.NoError(t, err)
+	assert.Equal(t, "1000 sq mi", measure.SquareMile.Format(a))
+}
+
+func TestAreaRoundTrip(t *testing.T) {
+	for _, one := range []string{
+		"12 sq in",
+		"3.5 sq ft",
+		"7 sq yd",
+		"2.25 ac",
+		"40 sq mi",
+		"250 sq cm",
+		"12 sq m",
+		"3.5 ha",
+		"15 sq km",
+	} {
+		units := measure.TrailingAreaUnitsFromString(one, measure.SquareInch)
+		a, err := measure.AreaFromString(one, measure.SquareInch)
+		assert.NoError(t, err, one)
+		assert.Equal(t, one, units.Format(a))
+		data, err := a.MarshalJSON()
+		assert.NoError(t, err, one)
+		var b measure.Area
+		assert.NoError(t, b.UnmarshalJSON(data), one)
+		assert.Equal(t, a, b, one)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package measure
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+)
+
+// Conversion factors, in square inches. Whole-number factors are applied directly to the underlying fixed-point value,
+// since the larger ones would overflow a fixed-point multiplication of any sizable area.
+const (
+	squareInchesPerSquareFoot      = 144
+	squareInchesPerSquareYard      = 1296
+	squareInchesPerAcre            = 4840 * squareInchesPerSquareYard
+	squareInchesPerSquareMile      = 3097600 * squareInchesPerSquareYard
+	squareInchesPerHectare         = 10000 * squareInchesPerSquareYard
+	squareInchesPerSquareKilometer = 1000000 * squareInchesPerSquareYard
+)
+
+// squareInchesPerSquareCentimeter is 1/10000th of a square meter, which is treated as a square yard.
+var squareInchesPerSquareCentimeter = fxp.FromStringForced("0.1296")
+
+// TrailingAreaUnitsFromString extracts a trailing AreaUnits from a string.
+func TrailingAreaUnitsFromString(s string, defUnits AreaUnits) AreaUnits {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, one := range AllAreaUnits {
+		if strings.HasSuffix(s, one.Key()) {
+			return one
+		}
+	}
+	return defUnits
+}
+
+// Format the area for this AreaUnits.
+func (enum AreaUnits) Format(area Area) string {
+	value := fxp.Int(area)
+	switch enum {
+	case SquareInch:
+		return value.String() + " " + enum.Key()
+	case SquareFoot:
+		return (value / squareInchesPerSquareFoot).String() + " " + enum.Key()
+	case SquareYard, SquareMeter:
+		return (value / squareInchesPerSquareYard).String() + " " + enum.Key()
+	case Acre:
+		return (value / squareInchesPerAcre).String() + " " + enum.Key()
+	case SquareMile:
+		return (value / squareInchesPerSquareMile).String() + " " + enum.Key()
+	case SquareCentimeter:
+		return value.Div(squareInchesPerSquareCentimeter).String() + " " + enum.Key()
+	case Hectare:
+		return (value / squareInchesPerHectare).String() + " " + enum.Key()
+	case SquareKilometer:
+		return (value / squareInchesPerSquareKilometer).String() + " " + enum.Key()
+	default:
+		return SquareYard.Format(area)
+	}
+}
+
+// ToSquareInches converts the area in this AreaUnits to square inches.
+func (enum AreaUnits) ToSquareInches(area fxp.Int) fxp.Int {
+	switch enum {
+	case SquareInch:
+		return area
+	case SquareFoot:
+		return area * squareInchesPerSquareFoot
+	case SquareYard, SquareMeter:
+		return area * squareInchesPerSquareYard
+	case Acre:
+		return area * squareInchesPerAcre
+	case SquareMile:
+		return area * squareInchesPerSquareMile
+	case SquareCentimeter:
+		return area.Mul(squareInchesPerSquareCentimeter)
+	case Hectare:
+		return area * squareInchesPerHectare
+	case SquareKilometer:
+		return area * squareInchesPerSquareKilometer
+	default:
+		return SquareYard.ToSquareInches(area)
+	}
+}
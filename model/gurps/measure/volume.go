@@ -0,0 +1,79 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package measure
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/json"
+	"golang.org/x/exp/constraints"
+)
+
+// Volume contains a fixed-point value in cubic inches.
+type Volume fxp.Int
+
+// VolumeFromInteger creates a new Volume.
+func VolumeFromInteger[T constraints.Integer](value T, unit VolumeUnits) Volume {
+	return Volume(unit.ToCubicInches(fxp.From(value)))
+}
+
+// VolumeFromStringForced creates a new Volume. May have any of the known Volume suffixes or no notation at all, in which
+// case defaultUnits is used.
+func VolumeFromStringForced(text string, defaultUnits VolumeUnits) Volume {
+	volume, err := VolumeFromString(text, defaultUnits)
+	if err != nil {
+		return 0
+	}
+	return volume
+}
+
+// VolumeFromString creates a new Volume. May have any of the known Volume suffixes or no notation at all, in which case
+// defaultUnits is used.
+func VolumeFromString(text string, defaultUnits VolumeUnits) (Volume, error) {
+	text = strings.TrimLeft(strings.TrimSpace(text), "+")
+	for _, unit := range AllVolumeUnits {
+		if strings.HasSuffix(text, unit.Key()) {
+			value, err := fxp.FromString(strings.TrimSpace(strings.TrimSuffix(text, unit.Key())))
+			if err != nil {
+				return 0, err
+			}
+			return Volume(unit.ToCubicInches(value)), nil
+		}
+	}
+	// No matches, so let's use our passed-in default units
+	value, err := fxp.FromString(strings.TrimSpace(text))
+	if err != nil {
+		return 0, err
+	}
+	return Volume(defaultUnits.ToCubicInches(value)), nil
+}
+
+func (v Volume) String() string {
+	return CubicInch.Format(v)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v Volume) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *Volume) UnmarshalJSON(in []byte) error {
+	var s string
+	if err := json.Unmarshal(in, &s); err != nil {
+		return err
+	}
+	var err error
+	*v, err = VolumeFromString(s, CubicInch)
+	return err
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+package measure_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps/measure"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeConversion(t *testing.T) {
+	assert.Equal(t, "1 cu in", measure.CubicInch.Format(measure.VolumeFromInteger(1, measure.CubicInch)))
+	assert.Equal(t, "1728 cu in", measure.CubicInch.Format(measure.VolumeFromInteger(1, measure.CubicFoot)))
+	assert.Equal(t, "27 cu ft", measure.CubicFoot.Format(measure.VolumeFromInteger(1, measure.CubicYard)))
+	assert.Equal(t, "1 cu m", measure.CubicMeter.Format(measure.VolumeFromInteger(1, measure.CubicYard)))
+	assert.Equal(t, "231 cu in", measure.CubicInch.Format(measure.VolumeFromInteger(1, measure.Gallon)))
+	assert.Equal(t, "4 qt", measure.Quart.Format(measure.VolumeFromInteger(1, measure.Gallon)))
+	assert.Equal(t, "46.656 cu in", measure.CubicInch.Format(measure.VolumeFromInteger(1, measure.Liter)))
+	assert.Equal(t, "1000 l", measure.Liter.Format(measure.VolumeFromInteger(1, measure.CubicMeter)))
+	assert.Equal(t, "1000 ml", measure.Milliliter.Format(measure.VolumeFromInteger(1, measure.Liter)))
+	assert.Equal(t, "1 l", measure.Liter.Format(measure.VolumeFromInteger(1000, measure.Milliliter)))
+
+	v, err := measure.VolumeFromString("1", measure.CubicInch)
+	assert.NoError(t, err)
+	assert.Equal(t, "1 cu in", v.String())
+	v, err = measure.VolumeFromString("2", measure.Liter)
+	assert.NoError(t, err)
+	assert.Equal(t, "93.312 cu in", v.String())
+	v, err = measure.VolumeFromString(" +1.5   gal  ", measure.CubicInch)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.5 gal", measure.Gallon.Format(v))
+	v, err = measure.VolumeFromString("500 ml", measure.CubicInch)
+	assert.NoError(t, err)
+	assert.Equal(t, "23.328 cu in", v.String())
+	assert.Equal(t, "0.5 l", measure.Liter.Format(v))
+}
+
+func TestVolumeRoundTrip(t *testing.T) {
+	for _, one := range []string{
+		"12 cu in",
+		"3.5 cu ft",
+		"2 cu yd",
+		"1.5 gal",
+		"3 qt",
+		"250 ml",
+		"2.5 l",
+		"4 cu m",
+	} {
+		units := measure.TrailingVolumeUnitsFromString(one, measure.CubicInch)
+		v, err := measure.VolumeFromString(one, measure.CubicInch)
+		assert.NoError(t, err, one)
+		assert.Equal(t, one, units.Format(v))
+		data, err := v.MarshalJSON()
+		assert.NoError(t, err, one)
+		var w measure.Volume
+		assert.NoError(t, w.UnmarshalJSON(data), one)
+		assert.Equal(t, v, w, one)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package measure
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+)
+
+// Conversion factors, in cubic inches. Metric volumes use the simplified GURPS metric conversion of 1 yd = 1 meter, so a
+// liter, being a cubic decimeter, is 1/1000th of a cubic yard. Milliliters are derived from liters rather than having a
+// factor of their own, since 0.046656 can't be represented exactly with the available fixed-point precision.
+var (
+	cubicInchesPerCubicFoot = fxp.From(1728)
+	cubicInchesPerCubicYard = fxp.From(46656)
+	cubicInchesPerGallon    = fxp.From(231)
+	cubicInchesPerQuart     = fxp.FromStringForced("57.75")
+	cubicInchesPerLiter     = fxp.FromStringForced("46.656")
+	millilitersPerLiter     = fxp.Thousand
+)
+
+// TrailingVolumeUnitsFromString extracts a trailing VolumeUnits from a string.
+func TrailingVolumeUnitsFromString(s string, defUnits VolumeUnits) VolumeUnits {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, one := range AllVolumeUnits {
+		if strings.HasSuffix(s, one.Key()) {
+			return one
+		}
+	}
+	return defUnits
+}
+
+// Format the volume for this VolumeUnits.
+func (enum VolumeUnits) Format(volume Volume) string {
+	switch enum {
+	case CubicFoot:
+		return fxp.Int(volume).Div(cubicInchesPerCubicFoot).String() + " " + enum.Key()
+	case CubicInch:
+		return fxp.Int(volume).String() + " " + enum.Key()
+	case CubicYard, CubicMeter:
+		return fxp.Int(volume).Div(cubicInchesPerCubicYard).String() + " " + enum.Key()
+	case Gallon:
+		return fxp.Int(volume).Div(cubicInchesPerGallon).String() + " " + enum.Key()
+	case Quart:
+		return fxp.Int(volume).Div(cubicInchesPerQuart).String() + " " + enum.Key()
+	case Milliliter:
+		return fxp.Int(volume).Mul(millilitersPerLiter).Div(cubicInchesPerLiter).String() + " " + enum.Key()
+	case Liter:
+		return fxp.Int(volume).Div(cubicInchesPerLiter).String() + " " + enum.Key()
+	default:
+		return CubicFoot.Format(volume)
+	}
+}
+
+// ToCubicInches converts the volume in this VolumeUnits to cubic inches.
+func (enum VolumeUnits) ToCubicInches(volume fxp.Int) fxp.Int {
+	switch enum {
+	case CubicFoot:
+		return volume.Mul(cubicInchesPerCubicFoot)
+	case CubicInch:
+		return volume
+	case CubicYard, CubicMeter:
+		return volume.Mul(cubicInchesPerCubicYard)
+	case Gallon:
+		return volume.Mul(cubicInchesPerGallon)
+	case Quart:
+		return volume.Mul(cubicInchesPerQuart)
+	case Milliliter:
+		return volume.Mul(cubicInchesPerLiter).Div(millilitersPerLiter)
+	case Liter:
+		return volume.Mul(cubicInchesPerLiter)
+	default:
+		return CubicFoot.ToCubicInches(volume)
+	}
+}
@@ -80,6 +80,9 @@ func NewNote(entity *Entity, parent *Note, container bool) *Note {
 		Entity: entity,
 	}
 	n.Text = n.Kind()
+	if entity != nil && entity.CurrentDate != "" {
+		n.Date = entity.CurrentDate
+	}
 	n.parent = parent
 	return n
 }
@@ -128,6 +131,7 @@ func (n *Note) CellData(column int, data *CellData) {
 	case NoteTextColumn:
 		data.Type = Text
 		data.Primary = n.Text
+		data.Secondary = n.Date
 	case NoteReferenceColumn, PageRefCellAlias:
 		data.Type = PageRef
 		data.Primary = n.PageRef
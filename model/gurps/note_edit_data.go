@@ -17,6 +17,7 @@ var _ EditorData[*Note] = &NoteEditData{}
 type NoteEditData struct {
 	Text    string `json:"text,omitempty"`
 	PageRef string `json:"reference,omitempty"`
+	Date    string `json:"date,omitempty"`
 }
 
 // CopyFrom implements node.EditorData.
@@ -107,7 +107,7 @@ func (p *PrereqList) Satisfied(entity *Entity, exclude any, buffer *xio.ByteBuff
 		if tl < 0 {
 			tl = 0
 		}
-		if !p.WhenTL.Compare.Matches(p.WhenTL.Qualifier, tl) {
+		if !p.WhenTL.Matches(tl) {
 			return true
 		}
 	}
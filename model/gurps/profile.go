@@ -99,5 +99,5 @@ func (p *Profile) AutoFill(entity *Entity) {
 	p.Height = a.RandomHeight(entity, p.Gender, 0)
 	p.Weight = a.RandomWeight(entity, p.Gender, 0)
 	p.Name = a.RandomName(ancestry.AvailableNameGenerators(SettingsProvider.Libraries()), p.Gender)
-	p.Birthday = generalSettings.CalendarRef(SettingsProvider.Libraries()).RandomBirthday(p.Birthday)
+	p.Birthday = entity.CalendarRef().RandomBirthday(p.Birthday)
 }
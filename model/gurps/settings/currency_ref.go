@@ -0,0 +1,178 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package settings
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/gid"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/log/jot"
+	xfs "github.com/richardwilkes/toolbox/xio/fs"
+)
+
+// DefaultCurrencyRefName holds the name of the default set of currencies.
+const DefaultCurrencyRefName = "Standard"
+
+const currenciesTypeKey = "currencies"
+
+// Currency holds a single currency definition. The Rate is the value of one unit of the currency, expressed in the
+// standard $ used throughout the GURPS rules.
+type Currency struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name,omitempty"`
+	Symbol string  `json:"symbol,omitempty"`
+	Rate   fxp.Int `json:"rate"`
+	Suffix bool    `json:"suffix,omitempty"`
+}
+
+// CurrencyRef holds a named reference to a set of currencies.
+type CurrencyRef struct {
+	Name       string
+	Currencies []*Currency
+}
+
+type currenciesData struct {
+	Type       string      `json:"type"`
+	Version    int         `json:"version"`
+	Currencies []*Currency `json:"currencies"`
+}
+
+// AvailableCurrencyRefs scans the libraries and returns the available currency sets.
+func AvailableCurrencyRefs(libraries library.Libraries) []*library.NamedFileSet {
+	return library.ScanForNamedFileSets(embeddedFS, "embedded_data", true, libraries, library.CurrencyExt)
+}
+
+// LookupCurrencyRef a CurrencyRef by name.
+func LookupCurrencyRef(name string, libraries library.Libraries) *CurrencyRef {
+	for _, lib := range AvailableCurrencyRefs(libraries) {
+		for _, one := range lib.List {
+			if one.Name == name {
+				if c, err := NewCurrencyRefFromFS(one.FileSystem, one.FilePath); err != nil {
+					jot.Warn(err)
+				} else {
+					return c
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// NewCurrencyRefFromFS creates a new CurrencyRef from a file.
+func NewCurrencyRefFromFS(fileSystem fs.FS, filePath string) (*CurrencyRef, error) {
+	var data currenciesData
+	if err := jio.LoadFromFS(context.Background(), fileSystem, filePath, &data); err != nil {
+		return nil, err
+	}
+	if data.Type != currenciesTypeKey {
+		return nil, errs.New(gid.UnexpectedFileDataMsg)
+	}
+	if err := gid.CheckVersion(data.Version); err != nil {
+		return nil, err
+	}
+	for _, one := range data.Currencies {
+		if one.Rate <= 0 {
+			one.Rate = fxp.One
+		}
+	}
+	return &CurrencyRef{
+		Name:       xfs.BaseName(filePath),
+		Currencies: data.Currencies,
+	}, nil
+}
+
+// Save writes the currencies to the file as JSON.
+func (c *CurrencyRef) Save(filePath string) error {
+	return jio.SaveToFile(context.Background(), filePath, &currenciesData{
+		Type:       currenciesTypeKey,
+		Version:    gid.CurrentDataVersion,
+		Currencies: c.Currencies,
+	})
+}
+
+// Lookup the currency with the given ID. An empty ID refers to the first currency in the set. Returns nil if no
+// matching currency can be found.
+func (c *CurrencyRef) Lookup(id string) *Currency {
+	if c == nil || len(c.Currencies) == 0 {
+		return nil
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return c.Currencies[0]
+	}
+	for _, one := range c.Currencies {
+		if strings.EqualFold(one.ID, id) {
+			return one
+		}
+	}
+	return nil
+}
+
+// IDs returns the IDs of the currencies in the set.
+func (c *CurrencyRef) IDs() []string {
+	if c == nil {
+		return nil
+	}
+	list := make([]string, 0, len(c.Currencies))
+	for _, one := range c.Currencies {
+		list = append(list, one.ID)
+	}
+	return list
+}
+
+// Convert an amount from one currency to another. Currencies that can't be found are treated as the standard $.
+func (c *CurrencyRef) Convert(amount fxp.Int, from, to string) fxp.Int {
+	if amount == 0 || strings.EqualFold(strings.TrimSpace(from), strings.TrimSpace(to)) {
+		return amount
+	}
+	if cur := c.Lookup(from); cur != nil {
+		amount = amount.Mul(cur.Rate)
+	}
+	if cur := c.Lookup(to); cur != nil {
+		amount = amount.Div(cur.Rate)
+	}
+	return amount
+}
+
+// Format the amount using the given currency's symbol.
+func (c *CurrencyRef) Format(amount fxp.Int, id string) string {
+	cur := c.Lookup(id)
+	if cur == nil {
+		return "$" + amount.Comma()
+	}
+	return cur.Format(amount)
+}
+
+// Format the amount using this currency's symbol.
+func (c *Currency) Format(amount fxp.Int) string {
+	symbol := c.Symbol
+	if symbol == "" {
+		symbol = c.ID
+	}
+	if c.Suffix {
+		return amount.Comma() + " " + symbol
+	}
+	return symbol + amount.Comma()
+}
+
+func (c *Currency) String() string {
+	if c.Name == "" {
+		return c.ID
+	}
+	return c.Name + " (" + c.ID + ")"
+}
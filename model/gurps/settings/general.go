@@ -53,6 +53,7 @@ type General struct {
 	DefaultPlayerName     string  `json:"default_player_name,omitempty"`
 	DefaultTechLevel      string  `json:"default_tech_level,omitempty"`
 	CalendarName          string  `json:"calendar_ref,omitempty"`
+	CurrencyName          string  `json:"currency_ref,omitempty"`
 	ExternalPDFCmdLine    string  `json:"external_pdf_cmd_line,omitempty"`
 	InitialPoints         fxp.Int `json:"initial_points"`
 	TooltipDelay          fxp.Int `json:"tooltip_delay"`
@@ -126,6 +127,17 @@ func (s *General) CalendarRef(libraries library.Libraries) *CalendarRef {
 	return ref
 }
 
+// CurrencyRef returns the CurrencyRef these settings refer to.
+func (s *General) CurrencyRef(libraries library.Libraries) *CurrencyRef {
+	ref := LookupCurrencyRef(s.CurrencyName, libraries)
+	if ref == nil {
+		if ref = LookupCurrencyRef(DefaultCurrencyRefName, libraries); ref == nil {
+			jot.Fatal(1, "unable to load default currencies (Standard)")
+		}
+	}
+	return ref
+}
+
 // EnsureValidity checks the current settings for validity and if they aren't valid, makes them so.
 func (s *General) EnsureValidity() {
 	s.InitialPoints = fxp.ResetIfOutOfRange(s.InitialPoints, InitialPointsMin, InitialPointsMax, InitialPointsDef)
@@ -51,6 +51,10 @@ type SheetSettingsData struct {
 	DamageProgression             attribute.DamageProgression `json:"damage_progression"`
 	DefaultLengthUnits            measure.LengthUnits         `json:"default_length_units"`
 	DefaultWeightUnits            measure.WeightUnits         `json:"default_weight_units"`
+	DefaultVolumeUnits            measure.VolumeUnits         `json:"default_volume_units"`
+	DefaultAreaUnits              measure.AreaUnits           `json:"default_area_units"`
+	DisplayCurrency               string                      `json:"display_currency,omitempty"`
+	CalendarName                  string                      `json:"calendar_ref,omitempty"`
 	UserDescriptionDisplay        display.Option              `json:"user_description_display"`
 	ModifiersDisplay              display.Option              `json:"modifiers_display"`
 	NotesDisplay                  display.Option              `json:"notes_display"`
@@ -92,6 +96,8 @@ func FactorySheetSettings() *SheetSettings {
 			DamageProgression:      attribute.BasicSet,
 			DefaultLengthUnits:     measure.FeetAndInches,
 			DefaultWeightUnits:     measure.Pound,
+			DefaultVolumeUnits:     measure.CubicFoot,
+			DefaultAreaUnits:       measure.SquareYard,
 			UserDescriptionDisplay: display.Tooltip,
 			ModifiersDisplay:       display.Inline,
 			NotesDisplay:           display.Inline,
@@ -142,6 +148,8 @@ func (s *SheetSettings) EnsureValidity() {
 	s.DamageProgression = s.DamageProgression.EnsureValid()
 	s.DefaultLengthUnits = s.DefaultLengthUnits.EnsureValid()
 	s.DefaultWeightUnits = s.DefaultWeightUnits.EnsureValid()
+	s.DefaultVolumeUnits = s.DefaultVolumeUnits.EnsureValid()
+	s.DefaultAreaUnits = s.DefaultAreaUnits.EnsureValid()
 	s.UserDescriptionDisplay = s.UserDescriptionDisplay.EnsureValid()
 	s.ModifiersDisplay = s.ModifiersDisplay.EnsureValid()
 	s.NotesDisplay = s.NotesDisplay.EnsureValid()
@@ -0,0 +1,298 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package gurps
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/attribute"
+	"github.com/richardwilkes/gcs/v5/model/gurps/gid"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// Time periods, in seconds, used for tracking spell durations.
+const (
+	SecondsPerTurn   = 1
+	SecondsPerMinute = 60
+	SecondsPerHour   = 60 * SecondsPerMinute
+	SecondsPerDay    = 24 * SecondsPerHour
+	SecondsPerWeek   = 7 * SecondsPerDay
+	SecondsPerMonth  = 30 * SecondsPerDay
+	SecondsPerYear   = 365 * SecondsPerDay
+)
+
+// spellDurationUnits maps the unit words that may follow the number in a spell's duration to their length in seconds.
+var spellDurationUnits = map[string]int{
+	"s":       1,
+	"sec":     1,
+	"secs":    1,
+	"second":  1,
+	"seconds": 1,
+	"turn":    SecondsPerTurn,
+	"turns":   SecondsPerTurn,
+	"m":       SecondsPerMinute,
+	"min":     SecondsPerMinute,
+	"mins":    SecondsPerMinute,
+	"minute":  SecondsPerMinute,
+	"minutes": SecondsPerMinute,
+	"h":       SecondsPerHour,
+	"hr":      SecondsPerHour,
+	"hrs":     SecondsPerHour,
+	"hour":    SecondsPerHour,
+	"hours":   SecondsPerHour,
+	"d":       SecondsPerDay,
+	"day":     SecondsPerDay,
+	"days":    SecondsPerDay,
+	"wk":      SecondsPerWeek,
+	"wks":     SecondsPerWeek,
+	"week":    SecondsPerWeek,
+	"weeks":   SecondsPerWeek,
+	"mo":      SecondsPerMonth,
+	"mos":     SecondsPerMonth,
+	"month":   SecondsPerMonth,
+	"months":  SecondsPerMonth,
+	"yr":      SecondsPerYear,
+	"yrs":     SecondsPerYear,
+	"year":    SecondsPerYear,
+	"years":   SecondsPerYear,
+}
+
+// SpellCost holds the energy costs of casting a spell, after any reduction for high skill has been applied.
+type SpellCost struct {
+	BaseCost        fxp.Int
+	BaseMaintenance fxp.Int
+	Reduction       fxp.Int
+	Cost            fxp.Int
+	Maintenance     fxp.Int
+	Maintainable    bool
+	Duration        int
+	Permanent       bool
+}
+
+// ActiveSpell holds a spell that has been cast and is still in effect.
+type ActiveSpell struct {
+	SpellID      uuid.UUID `json:"spell_id"`
+	Name         string    `json:"name"`
+	PoolID       string    `json:"pool"`
+	Maintenance  fxp.Int   `json:"maintenance,omitempty"`
+	Duration     int       `json:"duration,omitempty"`
+	Remaining    int       `json:"remaining,omitempty"`
+	Maintainable bool      `json:"maintainable,omitempty"`
+	Maintain     bool      `json:"maintain,omitempty"`
+}
+
+// SpellCostReduction returns the reduction in casting and maintenance cost for a spell known at the given level: 1 at
+// 15-19, 2 at 20-24, and so on.
+func SpellCostReduction(level fxp.Int) fxp.Int {
+	if level < fxp.Fifteen {
+		return 0
+	}
+	return (level - fxp.Fifteen).Div(fxp.Five).Trunc() + fxp.One
+}
+
+// ParseSpellCost extracts the energy cost from casting cost text, such as "3", "1/yd" or "2 to 6". Where a range or
+// per-unit cost is given, the leading value is used. Returns false if no cost could be determined.
+func ParseSpellCost(text string) (fxp.Int, bool) {
+	text = strings.TrimSpace(text)
+	value, remainder := fxp.Extract(text)
+	if remainder == text || value < 0 {
+		return 0, false
+	}
+	return value, true
+}
+
+// ParseSpellMaintenance extracts the maintenance cost from maintenance cost text, given the base casting cost. In
+// addition to numeric values, "Same" and "Half" are recognized. Returns false if the spell can't be maintained.
+func ParseSpellMaintenance(text string, castingCost fxp.Int) (fxp.Int, bool) {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "same", "s":
+		return castingCost, true
+	case "half":
+		return fxp.ApplyRounding(castingCost.Div(fxp.Two), false), true
+	default:
+		return ParseSpellCost(text)
+	}
+}
+
+// ParseSpellDuration extracts the duration, in seconds, from duration text, such as "1 min.", "10 sec." or "Permanent".
+// Other than for permanent spells, the text must start with a number followed by a unit, such as "min", "minutes" or
+// "hr". Returns 0 for instantaneous spells and for text that can't be understood, such as "Special".
+func ParseSpellDuration(text string) (seconds int, permanent bool) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if strings.HasPrefix(text, "perm") || strings.HasPrefix(text, "indef") {
+		return 0, true
+	}
+	value, remainder := fxp.Extract(text)
+	if remainder == text || value <= 0 {
+		return 0, false
+	}
+	words := strings.FieldsFunc(remainder, func(ch rune) bool { return !unicode.IsLetter(ch) })
+	if len(words) == 0 {
+		return 0, false
+	}
+	if unit, ok := spellDurationUnits[words[0]]; ok {
+		return fxp.As[int](value.Mul(fxp.From(unit)).Trunc()), false
+	}
+	return 0, false
+}
+
+// FormatSpellDuration returns a short description of the duration, such as "1 min 30 sec".
+func FormatSpellDuration(seconds int) string {
+	if seconds <= 0 {
+		return i18n.Text("Instant")
+	}
+	var parts []string
+	for _, unit := range []struct {
+		name    string
+		seconds int
+	}{
+		{name: i18n.Text("day"), seconds: SecondsPerDay},
+		{name: i18n.Text("hr"), seconds: SecondsPerHour},
+		{name: i18n.Text("min"), seconds: SecondsPerMinute},
+		{name: i18n.Text("sec"), seconds: 1},
+	} {
+		if seconds >= unit.seconds {
+			parts = append(parts, fmt.Sprintf("%d %s", seconds/unit.seconds, unit.name))
+			seconds %= unit.seconds
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// EnergyCost returns the costs of casting this spell at its current level. Returns an error if the casting cost can't
+// be determined from the spell's data.
+func (s *Spell) EnergyCost() (SpellCost, error) {
+	var cost SpellCost
+	if s.Container() {
+		return cost, errs.New(i18n.Text("Spell containers can't be cast."))
+	}
+	var ok bool
+	if cost.BaseCost, ok = ParseSpellCost(s.CastingCost); !ok {
+		return cost, errs.Newf(i18n.Text("Unable to determine the casting cost from \"%s\"."), s.CastingCost)
+	}
+	cost.BaseMaintenance, cost.Maintainable = ParseSpellMaintenance(s.MaintenanceCost, cost.BaseCost)
+	cost.Reduction = SpellCostReduction(s.LevelData.Level)
+	cost.Cost = (cost.BaseCost - cost.Reduction).Max(0)
+	if cost.Maintainable {
+		cost.Maintenance = (cost.BaseMaintenance - cost.Reduction).Max(0)
+	}
+	cost.Duration, cost.Permanent = ParseSpellDuration(s.Duration)
+	return cost, nil
+}
+
+// EnergyPools returns the pool attributes that may be used to pay for spells, with fatigue points first.
+func (e *Entity) EnergyPools() []*Attribute {
+	var list []*Attribute
+	for _, attr := range e.Attributes.List() {
+		if def := attr.AttributeDef(); def != nil && def.Type == attribute.Pool {
+			if attr.AttrID == gid.FatiguePoints {
+				list = append([]*Attribute{attr}, list...)
+			} else {
+				list = append(list, attr)
+			}
+		}
+	}
+	return list
+}
+
+// CastSpell deducts the cost of casting the spell from the pool with the given ID and, if the spell has a duration,
+// adds it to the list of active spells.
+func (e *Entity) CastSpell(s *Spell, poolID string) error {
+	cost, err := s.EnergyCost()
+	if err != nil {
+		return err
+	}
+	pool, ok := e.Attributes.Set[poolID]
+	if !ok {
+		return errs.New(i18n.Text("The selected energy pool doesn't exist."))
+	}
+	if pool.Current() < cost.Cost {
+		return errs.Newf(i18n.Text("Not enough energy in %s to cast %s."), poolName(pool), s.String())
+	}
+	pool.Damage += cost.Cost
+	if cost.Duration > 0 {
+		e.ActiveSpells = append(e.ActiveSpells, &ActiveSpell{
+			SpellID:      s.ID,
+			Name:         s.String(),
+			PoolID:       poolID,
+			Maintenance:  cost.Maintenance,
+			Duration:     cost.Duration,
+			Remaining:    cost.Duration,
+			Maintainable: cost.Maintainable,
+			Maintain:     cost.Maintainable,
+		})
+	}
+	return nil
+}
+
+// AdvanceActiveSpells moves time forward by the given number of seconds for all active spells. Spells whose duration
+// runs out are maintained if they are marked for maintenance and their pool can cover the cost; otherwise, they end.
+// The spells that ended are returned.
+func (e *Entity) AdvanceActiveSpells(seconds int) []*ActiveSpell {
+	var ended []*ActiveSpell
+	list := make([]*ActiveSpell, 0, len(e.ActiveSpells))
+	for _, one := range e.ActiveSpells {
+		one.Remaining -= seconds
+		for one.Remaining <= 0 && one.maintain(e) {
+			one.Remaining += one.Duration
+		}
+		if one.Remaining > 0 {
+			list = append(list, one)
+		} else {
+			ended = append(ended, one)
+		}
+	}
+	e.ActiveSpells = list
+	return ended
+}
+
+// EndSpell removes the active spell.
+func (e *Entity) EndSpell(spell *ActiveSpell) {
+	for i, one := range e.ActiveSpells {
+		if one == spell {
+			e.ActiveSpells = append(e.ActiveSpells[:i], e.ActiveSpells[i+1:]...)
+			return
+		}
+	}
+}
+
+func (a *ActiveSpell) maintain(e *Entity) bool {
+	if !a.Maintain || !a.Maintainable || a.Duration <= 0 {
+		return false
+	}
+	pool, ok := e.Attributes.Set[a.PoolID]
+	if !ok || pool.Current() < a.Maintenance {
+		return false
+	}
+	pool.Damage += a.Maintenance
+	return true
+}
+
+// PoolName returns the name of the pool the spell draws its energy from.
+func (a *ActiveSpell) PoolName(e *Entity) string {
+	if pool, ok := e.Attributes.Set[a.PoolID]; ok {
+		return poolName(pool)
+	}
+	return a.PoolID
+}
+
+func poolName(pool *Attribute) string {
+	if def := pool.AttributeDef(); def != nil {
+		return def.Name
+	}
+	return pool.AttrID
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package statblock
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	xfs "github.com/richardwilkes/toolbox/xio/fs"
+)
+
+// ImportFiles imports the stat blocks contained in the given text files, writing a character sheet alongside each one
+// and printing a report of what was done.
+func ImportFiles(paths ...string) error {
+	for _, p := range paths {
+		fmt.Printf(i18n.Text("Processing %s\n"), p)
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return errs.NewWithCause(p, err)
+		}
+		entity, report := Import(string(data))
+		target := xfs.TrimExtension(p) + library.SheetExt
+		if err = entity.Save(target); err != nil {
+			return err
+		}
+		fmt.Printf(i18n.Text("Wrote %s\n%s\n\n"), target, report.String())
+	}
+	return nil
+}
@@ -0,0 +1,489 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package statblock
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/datafile"
+	"github.com/richardwilkes/gcs/v5/model/gurps/gid"
+	"github.com/richardwilkes/gcs/v5/model/gurps/importer"
+	"github.com/richardwilkes/gcs/v5/model/gurps/skill"
+	"github.com/richardwilkes/gcs/v5/model/gurps/trait"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+type section int
+
+const (
+	headerSection section = iota
+	traitsSection
+	perksSection
+	quirksSection
+	skillsSection
+	techniquesSection
+	spellsSection
+	equipmentSection
+	notesSection
+	ignoredSection
+)
+
+var (
+	sectionRegex = regexp.MustCompile(`(?i)\b(advantages|disadvantages|perks|quirks|traits|features|languages|` +
+		`skills|techniques|spells|equipment|gear|weapons|attacks|notes)\s*:`)
+	attributeRegex = regexp.MustCompile(`(?i)\b(ST|DX|IQ|HT|HP|Will|Per|FP|Basic Speed|Speed|Basic Move|Move)\b\s*:?\s*` +
+		`(\d+(?:\.\d+)?)`)
+	costRegex           = regexp.MustCompile(`\[\s*([+-]?\d+(?:\.\d+)?)\s*]`)
+	selfControlRegex    = regexp.MustCompile(`\(\s*(6|9|12|15)\s*\)`)
+	trailingLevelRegex  = regexp.MustCompile(`\s+(\d+)$`)
+	skillLevelRegex     = regexp.MustCompile(`(?:\s*[-–]\s*|\s+)(\d+)$`)
+	difficultyRegex     = regexp.MustCompile(`\(\s*(ST|DX|IQ|HT|Will|Per)\s*/\s*(E|A|H|VH|W)\s*\)`)
+	techLevelRegex      = regexp.MustCompile(`/TL\s*(\d+[^\s(]*)`)
+	specializationRegex = regexp.MustCompile(`\(([^)]*)\)\s*$`)
+	quantityRegex       = regexp.MustCompile(`^(\d+)\s*[x×]?\s+`)
+	attributeIDs        = map[string]string{
+		"st":          gid.Strength,
+		"dx":          gid.Dexterity,
+		"iq":          gid.Intelligence,
+		"ht":          gid.Health,
+		"hp":          gid.HitPoints,
+		"will":        gid.Will,
+		"per":         gid.Perception,
+		"fp":          gid.FatiguePoints,
+		"basic speed": gid.BasicSpeed,
+		"speed":       gid.BasicSpeed,
+		"basic move":  gid.BasicMove,
+		"move":        gid.BasicMove,
+	}
+	primaryAttributes = map[string]bool{
+		gid.Strength:     true,
+		gid.Dexterity:    true,
+		gid.Intelligence: true,
+		gid.Health:       true,
+	}
+)
+
+// maxRaiseIterations limits the number of times raiseToLevel will try to increment a level.
+const maxRaiseIterations = 100
+
+type blockImporter struct {
+	entity     *gurps.Entity
+	index      *importer.LibraryIndex
+	report     *importer.Report
+	attributes map[string]fxp.Int
+	traitCosts map[*gurps.Trait]statedCost
+}
+
+type statedCost struct {
+	text string
+	cost fxp.Int
+}
+
+type levelRaiser interface {
+	RawPoints() fxp.Int
+	SetRawPoints(points fxp.Int) bool
+	IncrementSkillLevel()
+	CalculateLevel() skill.Level
+}
+
+// Import parses the text of a stat block, such as those found in published adventures, into a new character sheet.
+// Trait, skill, spell and equipment names are matched against the items found in the configured libraries; anything
+// that can't be matched is added as a placeholder. The returned report describes what was done.
+func Import(text string) (*gurps.Entity, *importer.Report) {
+	return ImportUsing(text, importer.NewLibraryIndex(gurps.SettingsProvider.Libraries()))
+}
+
+// ImportUsing parses the text of a stat block into a new character sheet, just as Import does, but matches names
+// against the items in the given index rather than those in the configured libraries.
+func ImportUsing(text string, index *importer.LibraryIndex) (*gurps.Entity, *importer.Report) {
+	imp := &blockImporter{
+		entity:     gurps.NewEntity(datafile.PC),
+		index:      index,
+		report:     &importer.Report{},
+		attributes: make(map[string]fxp.Int),
+		traitCosts: make(map[*gurps.Trait]statedCost),
+	}
+	text = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\u00a0", " ", "\u2014", "-", "\u2212", "-").Replace(text)
+	imp.importName(text)
+	sections := splitSections(text)
+	imp.importAttributes(sections[headerSection])
+	for _, one := range sections[traitsSection] {
+		imp.importTrait(one, 0)
+	}
+	for _, one := range sections[perksSection] {
+		imp.importTrait(one, fxp.One)
+	}
+	for _, one := range sections[quirksSection] {
+		imp.importTrait(one, -fxp.One)
+	}
+	imp.entity.Recalculate()
+	imp.applyAttributes(true)
+	imp.entity.Recalculate()
+	imp.applyAttributes(false)
+	imp.entity.Recalculate()
+	for _, one := range sections[skillsSection] {
+		imp.importSkill(one, false)
+	}
+	for _, one := range sections[techniquesSection] {
+		imp.importSkill(one, true)
+	}
+	for _, one := range sections[spellsSection] {
+		imp.importSpell(one)
+	}
+	for _, one := range sections[equipmentSection] {
+		imp.importEquipment(one)
+	}
+	for _, one := range sections[notesSection] {
+		note := gurps.NewNote(imp.entity, nil, false)
+		note.Text = one
+		imp.entity.Notes = append(imp.entity.Notes, note)
+	}
+	imp.report.Ignored = append(imp.report.Ignored, sections[ignoredSection]...)
+	imp.entity.Recalculate()
+	imp.reportDifferences()
+	return imp.entity, imp.report
+}
+
+// reportDifferences notes the attributes and traits whose final values don't match those given in the stat block.
+func (imp *blockImporter) reportDifferences() {
+	for _, attrID := range []string{gid.Strength, gid.Dexterity, gid.Intelligence, gid.Health, gid.HitPoints, gid.Will,
+		gid.Perception, gid.FatiguePoints, gid.BasicSpeed, gid.BasicMove} {
+		value, ok := imp.attributes[attrID]
+		if !ok {
+			continue
+		}
+		if attr, exists := imp.entity.Attributes.Set[attrID]; exists && attr.Maximum() != value {
+			imp.report.Differences = append(imp.report.Differences, fmt.Sprintf(i18n.Text("%s %s (is %s)"),
+				attr.AttributeDef().Name, value.String(), attr.Maximum().String()))
+		}
+	}
+	for _, t := range imp.entity.Traits {
+		if stated, ok := imp.traitCosts[t]; ok && t.AdjustedPoints() != stated.cost {
+			imp.report.Differences = append(imp.report.Differences, fmt.Sprintf(i18n.Text("%s (costs %s)"),
+				stated.text, t.AdjustedPoints().String()))
+		}
+	}
+}
+
+func (imp *blockImporter) importName(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !attributeRegex.MatchString(line) && !sectionRegex.MatchString(line) {
+			imp.entity.Profile.Name = strings.TrimRight(line, ".:;,")
+		}
+		return
+	}
+}
+
+// splitSections divides the text into its sections, with each section broken into its individual items. The header
+// section holds the raw text that precedes the first section heading.
+func splitSections(text string) map[section][]string {
+	sections := make(map[section][]string)
+	locs := sectionRegex.FindAllStringSubmatchIndex(text, -1)
+	end := len(text)
+	if len(locs) != 0 {
+		end = locs[0][0]
+	}
+	sections[headerSection] = []string{text[:end]}
+	for i, loc := range locs {
+		end = len(text)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		var which section
+		switch strings.ToLower(text[loc[2]:loc[3]]) {
+		case "advantages", "disadvantages", "traits", "features", "languages":
+			which = traitsSection
+		case "perks":
+			which = perksSection
+		case "quirks":
+			which = quirksSection
+		case "skills":
+			which = skillsSection
+		case "techniques":
+			which = techniquesSection
+		case "spells":
+			which = spellsSection
+		case "equipment", "gear":
+			which = equipmentSection
+		case "notes":
+			which = notesSection
+		default:
+			which = ignoredSection
+		}
+		content := text[loc[1]:end]
+		if which == notesSection || which == ignoredSection {
+			if content = strings.TrimSpace(content); content != "" {
+				sections[which] = append(sections[which], content)
+			}
+		} else {
+			sections[which] = append(sections[which], splitItems(content)...)
+		}
+	}
+	return sections
+}
+
+// splitItems breaks a section's content into its items, which are separated by commas or semicolons that aren't
+// enclosed in parentheses or brackets.
+func splitItems(text string) []string {
+	var list []string
+	depth := 0
+	start := 0
+	add := func(item string) {
+		item = strings.TrimSpace(strings.Join(strings.Fields(item), " "))
+		item = strings.TrimSpace(strings.TrimRight(item, "."))
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	for i, ch := range text {
+		switch ch {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			if depth > 0 {
+				depth--
+			}
+		case ',', ';':
+			if depth == 0 {
+				add(text[start:i])
+				start = i + 1
+			}
+		}
+	}
+	add(text[start:])
+	return list
+}
+
+func (imp *blockImporter) importAttributes(header []string) {
+	for _, text := range header {
+		for _, match := range attributeRegex.FindAllStringSubmatch(text, -1) {
+			if value, err := fxp.FromString(match[2]); err == nil {
+				imp.attributes[attributeIDs[strings.ToLower(strings.Join(strings.Fields(match[1]), " "))]] = value
+			}
+		}
+	}
+}
+
+func (imp *blockImporter) applyAttributes(primary bool) {
+	for attrID, value := range imp.attributes {
+		if primaryAttributes[attrID] != primary {
+			continue
+		}
+		if attr, ok := imp.entity.Attributes.Set[attrID]; ok {
+			attr.SetMaximum(value)
+		} else {
+			imp.report.Ignored = append(imp.report.Ignored, attrID+" "+value.String())
+		}
+	}
+}
+
+func (imp *blockImporter) importTrait(text string, defaultCost fxp.Int) {
+	name := text
+	cost := defaultCost
+	hasCost := false
+	if match := costRegex.FindStringSubmatch(name); match != nil {
+		if value, err := fxp.FromString(match[1]); err == nil {
+			cost = value
+			hasCost = true
+		}
+		name = strings.TrimSpace(costRegex.ReplaceAllString(name, ""))
+	}
+	cr := trait.None
+	if match := selfControlRegex.FindStringSubmatch(name); match != nil {
+		cr = trait.SelfControlRoll(fxp.As[int](fxp.FromStringForced(match[1]))).EnsureValid()
+		name = strings.TrimSpace(selfControlRegex.ReplaceAllString(name, ""))
+	}
+	var levels fxp.Int
+	if match := trailingLevelRegex.FindStringSubmatch(name); match != nil {
+		levels = fxp.FromStringForced(match[1])
+		name = strings.TrimSpace(name[:len(name)-len(match[0])])
+	}
+	var t *gurps.Trait
+	if found := imp.index.Trait(name); found != nil {
+		t = found.Clone(imp.entity, nil, false)
+		if levels > 0 && t.IsLeveled() {
+			t.Levels = levels
+		}
+		imp.report.Matched = append(imp.report.Matched, text)
+	} else {
+		t = gurps.NewTrait(imp.entity, nil, false)
+		t.Name = name
+		if levels > 0 {
+			t.CanLevel = true
+			t.Levels = levels
+			t.PointsPerLevel = cost.Div(levels)
+		} else {
+			t.BasePoints = cost
+		}
+		imp.report.Placeholders = append(imp.report.Placeholders, text)
+	}
+	if cr != trait.None {
+		t.CR = cr
+	}
+	if hasCost {
+		imp.traitCosts[t] = statedCost{text: text, cost: cost}
+	}
+	imp.entity.Traits = append(imp.entity.Traits, t)
+}
+
+type skillDescription struct {
+	name           string
+	specialization string
+	techLevel      string
+	attribute      string
+	difficulty     string
+	level          fxp.Int
+	hasLevel       bool
+}
+
+func parseSkill(text string) skillDescription {
+	var d skillDescription
+	text = strings.TrimSpace(costRegex.ReplaceAllString(text, ""))
+	if match := difficultyRegex.FindStringSubmatch(text); match != nil {
+		d.attribute = attributeIDs[strings.ToLower(match[1])]
+		d.difficulty = strings.ToLower(match[2])
+		text = strings.TrimSpace(difficultyRegex.ReplaceAllString(text, ""))
+	}
+	if match := skillLevelRegex.FindStringSubmatch(text); match != nil {
+		d.level = fxp.FromStringForced(match[1])
+		d.hasLevel = true
+		text = strings.TrimSpace(text[:len(text)-len(match[0])])
+	}
+	if match := techLevelRegex.FindStringSubmatch(text); match != nil {
+		d.techLevel = match[1]
+		text = strings.TrimSpace(techLevelRegex.ReplaceAllString(text, ""))
+	}
+	if match := specializationRegex.FindStringSubmatch(text); match != nil {
+		d.specialization = strings.TrimSpace(match[1])
+		text = strings.TrimSpace(text[:len(text)-len(match[0])])
+	}
+	d.name = text
+	return d
+}
+
+func (imp *blockImporter) importSkill(text string, technique bool) {
+	d := parseSkill(text)
+	var s *gurps.Skill
+	if found := imp.index.Skill(d.name, d.specialization); found != nil {
+		s = found.Clone(imp.entity, nil, false)
+		if d.specialization != "" && (s.Specialization == "" || strings.Contains(s.Specialization, "@")) {
+			s.Specialization = d.specialization
+		}
+		if s.TechLevel != nil && d.techLevel != "" {
+			tl := d.techLevel
+			s.TechLevel = &tl
+		}
+		imp.report.Matched = append(imp.report.Matched, text)
+	} else {
+		if technique {
+			s = gurps.NewTechnique(imp.entity, nil, d.specialization)
+		} else {
+			s = gurps.NewSkill(imp.entity, nil, false)
+			s.Specialization = d.specialization
+		}
+		s.Name = d.name
+		if d.techLevel != "" {
+			tl := d.techLevel
+			s.TechLevel = &tl
+		}
+		if d.attribute != "" && !technique {
+			s.Difficulty.Attribute = d.attribute
+		}
+		if d.difficulty != "" {
+			s.Difficulty.Difficulty = skill.ExtractDifficulty(d.difficulty)
+		}
+		imp.report.Placeholders = append(imp.report.Placeholders, text)
+	}
+	imp.entity.Skills = append(imp.entity.Skills, s)
+	if d.hasLevel {
+		imp.raiseToLevel(s, d.level, text)
+	}
+}
+
+func (imp *blockImporter) importSpell(text string) {
+	d := parseSkill(text)
+	var s *gurps.Spell
+	if found := imp.index.Spell(d.name); found != nil {
+		s = found.Clone(imp.entity, nil, false)
+		imp.report.Matched = append(imp.report.Matched, text)
+	} else {
+		s = gurps.NewSpell(imp.entity, nil, false)
+		s.Name = d.name
+		if d.attribute != "" {
+			s.Difficulty.Attribute = d.attribute
+		}
+		if d.difficulty != "" {
+			s.Difficulty.Difficulty = skill.ExtractDifficulty(d.difficulty)
+		}
+		imp.report.Placeholders = append(imp.report.Placeholders, text)
+	}
+	if s.TechLevel != nil && d.techLevel != "" {
+		tl := d.techLevel
+		s.TechLevel = &tl
+	}
+	imp.entity.Spells = append(imp.entity.Spells, s)
+	if d.hasLevel {
+		imp.raiseToLevel(s, d.level, text)
+	}
+}
+
+func (imp *blockImporter) importEquipment(text string) {
+	name := text
+	quantity := fxp.One
+	if match := quantityRegex.FindStringSubmatch(name); match != nil {
+		quantity = fxp.FromStringForced(match[1])
+		name = strings.TrimSpace(name[len(match[0]):])
+	}
+	var eqp *gurps.Equipment
+	if found := imp.index.Equipment(name); found != nil {
+		eqp = found.Clone(imp.entity, nil, false)
+		imp.report.Matched = append(imp.report.Matched, text)
+	} else {
+		eqp = gurps.NewEquipment(imp.entity, nil, false)
+		eqp.Name = name
+		imp.report.Placeholders = append(imp.report.Placeholders, text)
+	}
+	eqp.Quantity = quantity
+	imp.entity.CarriedEquipment = append(imp.entity.CarriedEquipment, eqp)
+}
+
+// raiseToLevel adds points until the target level is reached or no further progress can be made, such as when a
+// technique has reached its maximum. If the target level can't be reached, the difference is reported.
+func (imp *blockImporter) raiseToLevel(target levelRaiser, level fxp.Int, text string) {
+	target.SetRawPoints(fxp.One)
+	current := target.CalculateLevel().Level
+	for i := 0; i < maxRaiseIterations && current < level; i++ {
+		points := target.RawPoints()
+		target.IncrementSkillLevel()
+		next := target.CalculateLevel().Level
+		if next <= current {
+			target.SetRawPoints(points)
+			break
+		}
+		current = next
+	}
+	switch {
+	case current == fxp.Min:
+		imp.report.Differences = append(imp.report.Differences, fmt.Sprintf(i18n.Text("%s (can't be learned)"), text))
+	case current != level:
+		imp.report.Differences = append(imp.report.Differences, fmt.Sprintf(i18n.Text("%s (is %s)"), text,
+			current.String()))
+	}
+}
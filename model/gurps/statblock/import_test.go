@@ -0,0 +1,126 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package statblock_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/gid"
+	"github.com/richardwilkes/gcs/v5/model/gurps/importer"
+	"github.com/richardwilkes/gcs/v5/model/gurps/settings"
+	"github.com/richardwilkes/gcs/v5/model/gurps/statblock"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSettings struct {
+	general *settings.General
+	sheet   *gurps.SheetSettings
+}
+
+func (s *testSettings) GeneralSettings() *settings.General {
+	return s.general
+}
+
+func (s *testSettings) SheetSettings() *gurps.SheetSettings {
+	return s.sheet
+}
+
+func (s *testSettings) Libraries() library.Libraries {
+	return nil
+}
+
+func TestMain(m *testing.M) {
+	gurps.SettingsProvider = &testSettings{
+		general: settings.NewGeneral(),
+		sheet:   gurps.FactorySheetSettings(),
+	}
+	gurps.InstallEvaluatorFunctions(fxp.EvalFuncs)
+	os.Exit(m.Run())
+}
+
+func TestImport(t *testing.T) {
+	limit := fxp.Two
+	feint := gurps.NewTechnique(nil, nil, "Karate")
+	feint.Name = "Feint"
+	feint.TechniqueLimitModifier = &limit
+	index := importer.NewLibraryIndex(nil)
+	index.AddSkills(feint)
+
+	for _, one := range []struct {
+		name        string
+		text        string
+		placeholder string
+		difference  string
+		check       func(t *testing.T, entity *gurps.Entity)
+	}{
+		{
+			name: "lowercase attributes",
+			text: "Guard\nst 13; dx 12; iq 9; ht 11.",
+			check: func(t *testing.T, entity *gurps.Entity) {
+				assert.Equal(t, "Guard", entity.Profile.Name)
+				assert.Equal(t, fxp.From(13), entity.Attributes.Set[gid.Strength].Maximum())
+				assert.Equal(t, fxp.From(12), entity.Attributes.Set[gid.Dexterity].Maximum())
+				assert.Equal(t, fxp.From(9), entity.Attributes.Set[gid.Intelligence].Maximum())
+				assert.Equal(t, fxp.From(11), entity.Attributes.Set[gid.Health].Maximum())
+			},
+		},
+		{
+			name:        "unknown trait",
+			text:        "Guard\nAdvantages: Uncanny Knack [15].",
+			placeholder: "Uncanny Knack [15]",
+			check: func(t *testing.T, entity *gurps.Entity) {
+				if assert.Len(t, entity.Traits, 1) {
+					assert.Equal(t, "Uncanny Knack", entity.Traits[0].Name)
+					assert.Equal(t, fxp.From(15), entity.Traits[0].AdjustedPoints())
+				}
+			},
+		},
+		{
+			name:        "unreachable technique",
+			text:        "Guard\nTechniques: Disarming (Fencing)-14.",
+			placeholder: "Disarming (Fencing)-14",
+			difference:  "Disarming (Fencing)-14",
+		},
+		{
+			name:       "capped technique",
+			text:       "Guard\nDX 10. Skills: Karate-12. Techniques: Feint (Karate)-20.",
+			difference: "Feint (Karate)-20",
+			check: func(t *testing.T, entity *gurps.Entity) {
+				if assert.Len(t, entity.Skills, 2) {
+					assert.Equal(t, fxp.From(12), entity.Skills[0].CalculateLevel().Level)
+					assert.Equal(t, fxp.From(14), entity.Skills[1].CalculateLevel().Level)
+				}
+			},
+		},
+	} {
+		t.Run(one.name, func(t *testing.T) {
+			entity, report := statblock.ImportUsing(one.text, index)
+			if one.placeholder != "" {
+				assert.Contains(t, report.Placeholders, one.placeholder)
+			}
+			if one.difference != "" {
+				if assert.Len(t, report.Differences, 1) {
+					assert.Contains(t, report.Differences[0], one.difference)
+				}
+			} else {
+				assert.Empty(t, report.Differences)
+			}
+			if one.check != nil {
+				one.check(t, entity)
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package gurps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/richardwilkes/gcs/v5/model/crc"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/feature"
+	"github.com/richardwilkes/gcs/v5/model/gurps/gid"
+	"github.com/richardwilkes/gcs/v5/model/gurps/weapon"
+	"github.com/richardwilkes/gcs/v5/model/id"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/log/jot"
+	xfs "github.com/richardwilkes/toolbox/xio/fs"
+)
+
+const vehicleTypeKey = "vehicle"
+
+var (
+	_ WeaponOwner        = &Vehicle{}
+	_ WeaponListProvider = &Vehicle{}
+)
+
+// Vehicle holds the GURPS Vehicle data that is written to disk.
+type Vehicle struct {
+	Type         string         `json:"type"`
+	Version      int            `json:"version"`
+	ID           uuid.UUID      `json:"id"`
+	Name         string         `json:"name,omitempty"`
+	TechLevel    string         `json:"tech_level,omitempty"`
+	PageRef      string         `json:"reference,omitempty"`
+	ST           int            `json:"st,omitempty"`
+	HP           int            `json:"hp,omitempty"`
+	Handling     int            `json:"handling,omitempty"`
+	Stability    int            `json:"stability,omitempty"`
+	HT           int            `json:"ht,omitempty"`
+	HTCodes      string         `json:"ht_codes,omitempty"`
+	Acceleration int            `json:"acceleration,omitempty"`
+	TopSpeed     int            `json:"top_speed,omitempty"`
+	LoadedWeight fxp.Int        `json:"loaded_weight,omitempty"`
+	Load         fxp.Int        `json:"load,omitempty"`
+	SizeModifier int            `json:"sm,omitempty"`
+	Occupancy    string         `json:"occupancy,omitempty"`
+	DR           string         `json:"dr,omitempty"`
+	Range        int            `json:"range,omitempty"`
+	Cost         fxp.Int        `json:"cost,omitempty"`
+	Locations    string         `json:"locations,omitempty"`
+	UserNotes    string         `json:"notes,omitempty"`
+	Crew         []*VehicleCrew `json:"crew,omitempty"`
+	WeaponList   []*Weapon      `json:"weapons,omitempty"`
+	dir          string
+}
+
+// VehicleCrew holds a crew position for a vehicle. The crew member may reference a character sheet, in which case that
+// character's skills are used to determine the skill levels of the vehicle's weapons when they are the gunner. The path
+// to the sheet is stored relative to the vehicle file whenever possible, so that the two may be moved together.
+type VehicleCrew struct {
+	Role       string `json:"role,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Gunner     bool   `json:"gunner,omitempty"`
+	dir        string
+	entity     *Entity
+	loadedPath string
+}
+
+// NewVehicleFromFile loads a Vehicle from a file.
+func NewVehicleFromFile(fileSystem fs.FS, filePath string) (*Vehicle, error) {
+	var vehicle Vehicle
+	if err := jio.LoadFromFS(context.Background(), fileSystem, filePath, &vehicle); err != nil {
+		return nil, errs.NewWithCause(gid.InvalidFileDataMsg, err)
+	}
+	if vehicle.Type != vehicleTypeKey {
+		return nil, errs.New(gid.UnexpectedFileDataMsg)
+	}
+	if err := gid.CheckVersion(vehicle.Version); err != nil {
+		return nil, err
+	}
+	vehicle.adoptWeapons()
+	vehicle.adoptCrew()
+	return &vehicle, nil
+}
+
+// NewVehicle creates a new Vehicle.
+func NewVehicle() *Vehicle {
+	return &Vehicle{
+		Type: vehicleTypeKey,
+		ID:   id.NewUUID(),
+	}
+}
+
+// Save the Vehicle to a file as JSON. The paths to the crew's character sheets are rewritten to be relative to the new
+// location of the vehicle file.
+func (v *Vehicle) Save(filePath string) error {
+	v.SetFilePath(filePath)
+	v.Version = gid.CurrentDataVersion
+	return jio.SaveToFile(context.Background(), filePath, v)
+}
+
+// SetFilePath records the location of the vehicle file, which the paths to the crew's character sheets are relative to.
+// This should be called after loading a vehicle, since the file system it was loaded from may not reveal its location.
+// Crew paths that were relative to a previously recorded location are rewritten so that they continue to refer to the
+// same sheets.
+func (v *Vehicle) SetFilePath(filePath string) {
+	dir := filepath.Dir(filePath)
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	for _, one := range v.Crew {
+		resolved := one.ResolvedPath()
+		one.dir = dir
+		if resolved != "" {
+			one.SetResolvedPath(resolved)
+		}
+	}
+	v.dir = dir
+}
+
+// SetCrew replaces the crew.
+func (v *Vehicle) SetCrew(crew []*VehicleCrew) {
+	v.Crew = crew
+	v.adoptCrew()
+}
+
+// CloneCrew returns a copy of the crew.
+func (v *Vehicle) CloneCrew() []*VehicleCrew {
+	if v.Crew == nil {
+		return nil
+	}
+	list := make([]*VehicleCrew, len(v.Crew))
+	for i, one := range v.Crew {
+		list[i] = one.Clone()
+	}
+	return list
+}
+
+func (v *Vehicle) adoptCrew() {
+	for _, one := range v.Crew {
+		one.dir = v.dir
+	}
+}
+
+// CRC64 computes a CRC-64 value for the canonical disk format of the data.
+func (v *Vehicle) CRC64() uint64 {
+	var buffer bytes.Buffer
+	if err := jio.Save(context.Background(), &buffer, v); err != nil {
+		return 0
+	}
+	return crc.Bytes(0, buffer.Bytes())
+}
+
+func (v *Vehicle) adoptWeapons() {
+	for _, w := range v.WeaponList {
+		w.SetOwner(v)
+	}
+}
+
+// Gunner returns the crew member assigned as the gunner, or nil if there isn't one.
+func (v *Vehicle) Gunner() *VehicleCrew {
+	for _, one := range v.Crew {
+		if one.Gunner {
+			return one
+		}
+	}
+	return nil
+}
+
+// Entity implements EntityProvider. Returns the character sheet of the gunner, if any.
+func (v *Vehicle) Entity() *Entity {
+	if gunner := v.Gunner(); gunner != nil {
+		return gunner.Entity()
+	}
+	return nil
+}
+
+// OwningEntity implements WeaponOwner.
+func (v *Vehicle) OwningEntity() *Entity {
+	return v.Entity()
+}
+
+// String implements WeaponOwner.
+func (v *Vehicle) String() string {
+	return v.Description()
+}
+
+// Description implements WeaponOwner.
+func (v *Vehicle) Description() string {
+	if v.Name == "" {
+		return i18n.Text("Vehicle")
+	}
+	return v.Name
+}
+
+// FeatureList implements WeaponOwner.
+func (v *Vehicle) FeatureList() feature.Features {
+	return nil
+}
+
+// TagList implements WeaponOwner.
+func (v *Vehicle) TagList() []string {
+	return nil
+}
+
+// WeaponOwner implements WeaponListProvider.
+func (v *Vehicle) WeaponOwner() WeaponOwner {
+	return v
+}
+
+// Weapons implements WeaponListProvider.
+func (v *Vehicle) Weapons(weaponType weapon.Type) []*Weapon {
+	return ExtractWeaponsOfType(weaponType, v.WeaponList)
+}
+
+// SetWeapons implements WeaponListProvider.
+func (v *Vehicle) SetWeapons(weaponType weapon.Type, list []*Weapon) {
+	melee, ranged := SeparateWeapons(v.WeaponList)
+	switch weaponType {
+	case weapon.Melee:
+		melee = list
+	case weapon.Ranged:
+		ranged = list
+	}
+	v.WeaponList = append(append(make([]*Weapon, 0, len(melee)+len(ranged)), melee...), ranged...)
+	v.adoptWeapons()
+}
+
+// SetGunner makes the crew member the gunner, clearing the gunner flag from all others. Passing nil clears the gunner.
+func (v *Vehicle) SetGunner(gunner *VehicleCrew) {
+	for _, one := range v.Crew {
+		one.Gunner = one == gunner
+	}
+}
+
+// HandlingAndStability returns the Hnd/SR value, e.g. "-2/4".
+func (v *Vehicle) HandlingAndStability() string {
+	return fmt.Sprintf("%d/%d", v.Handling, v.Stability)
+}
+
+// STAndHP returns the ST/HP value. If ST and HP are the same, only a single value is returned, as is typical in
+// vehicle tables.
+func (v *Vehicle) STAndHP() string {
+	if v.ST == v.HP {
+		return strconv.Itoa(v.ST)
+	}
+	return fmt.Sprintf("%d/%d", v.ST, v.HP)
+}
+
+// HTWithCodes returns the HT value, along with any codes, such as "11f".
+func (v *Vehicle) HTWithCodes() string {
+	return fmt.Sprintf("%d%s", v.HT, v.HTCodes)
+}
+
+// Move returns the Move value, e.g. "4/40".
+func (v *Vehicle) Move() string {
+	return fmt.Sprintf("%d/%d", v.Acceleration, v.TopSpeed)
+}
+
+// Notes implements WeaponOwner. The vehicle's own notes aren't returned, as they would otherwise be repeated beneath
+// each of its weapons.
+func (v *Vehicle) Notes() string {
+	return ""
+}
+
+// Clone returns a copy of this crew member.
+func (c *VehicleCrew) Clone() *VehicleCrew {
+	other := *c
+	return &other
+}
+
+// ResolvedPath returns the path to the character sheet referenced by this crew member, or an empty string if there
+// isn't one. Relative paths are resolved against the location of the vehicle file, if known.
+func (c *VehicleCrew) ResolvedPath() string {
+	if c.Path == "" {
+		return ""
+	}
+	p := filepath.FromSlash(c.Path)
+	if filepath.IsAbs(p) || c.dir == "" {
+		return p
+	}
+	return filepath.Join(c.dir, p)
+}
+
+// SetResolvedPath sets the path to the character sheet referenced by this crew member, storing it relative to the
+// location of the vehicle file, if known and possible.
+func (c *VehicleCrew) SetResolvedPath(filePath string) {
+	c.Path = filePath
+	if filePath != "" && c.dir != "" && filepath.IsAbs(filePath) {
+		if rel, err := filepath.Rel(c.dir, filePath); err == nil {
+			c.Path = filepath.ToSlash(rel)
+		}
+	}
+}
+
+// Entity returns the character sheet referenced by this crew member, loading it if necessary. Returns nil if there is
+// no referenced sheet or it can't be loaded.
+func (c *VehicleCrew) Entity() *Entity {
+	p := c.ResolvedPath()
+	if p == "" {
+		return nil
+	}
+	if c.loadedPath == p {
+		return c.entity
+	}
+	c.entity = nil
+	c.loadedPath = p
+	if !xfs.FileIsReadable(p) {
+		return nil
+	}
+	entity, err := NewEntityFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p))
+	if err != nil {
+		jot.Warn(errs.NewWithCause(fmt.Sprintf(i18n.Text("unable to load crew member sheet: %s"), p), err))
+		return nil
+	}
+	c.entity = entity
+	return entity
+}
+
+// Reload discards any cached character sheet, so that the next request for it will load it from disk again.
+func (c *VehicleCrew) Reload() {
+	c.entity = nil
+	c.loadedPath = ""
+}
+
+// DisplayName returns the name to display for this crew member, falling back to the name of the referenced sheet.
+func (c *VehicleCrew) DisplayName() string {
+	if name := strings.TrimSpace(c.Name); name != "" {
+		return name
+	}
+	if entity := c.Entity(); entity != nil && entity.Profile != nil && entity.Profile.Name != "" {
+		return entity.Profile.Name
+	}
+	if c.Path != "" {
+		return xfs.BaseName(filepath.FromSlash(c.Path))
+	}
+	return ""
+}
@@ -80,6 +80,8 @@ type WeaponData struct {
 	Shots           string          `json:"shots,omitempty"`
 	Bulk            string          `json:"bulk,omitempty"`
 	Recoil          string          `json:"recoil,omitempty"`
+	AmmoID          string          `json:"ammo_id,omitempty"`
+	ShotsUsed       int             `json:"shots_used,omitempty"`
 	Defaults        []*SkillDefault `json:"defaults,omitempty"`
 }
 
@@ -141,11 +143,16 @@ func NewWeapon(owner WeaponOwner, weaponType weapon.Type) *Weapon {
 }
 
 // Clone implements Node.
-func (w *Weapon) Clone(_ *Entity, _ *Weapon, preserveID bool) *Weapon {
+func (w *Weapon) Clone(entity *Entity, _ *Weapon, preserveID bool) *Weapon {
 	other := *w
 	if !preserveID {
 		other.ID = uuid.New()
 	}
+	if entity == nil {
+		// The ammunition state only has meaning for a weapon on a sheet, so don't carry it into libraries and templates
+		other.AmmoID = ""
+		other.ShotsUsed = 0
+	}
 	other.Damage = *other.Damage.Clone(&other)
 	if other.Defaults != nil {
 		other.Defaults = make([]*SkillDefault, 0, len(w.Defaults))
@@ -224,6 +231,10 @@ func (w *Weapon) MarshalJSON() ([]byte, error) {
 			Damage: w.Damage.ResolvedDamage(nil),
 		},
 	}
+	if w.Entity() == nil {
+		data.AmmoID = ""
+		data.ShotsUsed = 0
+	}
 	if w.Type == weapon.Melee {
 		data.Calc.Parry = w.ResolvedParry(nil)
 		data.Calc.Block = w.ResolvedBlock(nil)
@@ -409,18 +420,42 @@ func (w *Weapon) extractSkillBonusForThisWeapon(f feature.Feature, tooltip *xio.
 	return 0
 }
 
-// ResolvedParry returns the resolved parry level.
+// ResolvedParry returns the resolved parry level. If the parry is an expression, its result is used as-is, rather than
+// being adjusted by the parry derived from the weapon's skill.
 func (w *Weapon) ResolvedParry(tooltip *xio.ByteBuffer) string {
-	return w.resolvedValue(w.Parry, gid.Parry, tooltip)
+	return w.resolvedDefense(w.Parry, gid.Parry, tooltip)
 }
 
-// ResolvedBlock returns the resolved block level.
+// ResolvedBlock returns the resolved block level. If the block is an expression, its result is used as-is, rather than
+// being adjusted by the block derived from the weapon's skill.
 func (w *Weapon) ResolvedBlock(tooltip *xio.ByteBuffer) string {
-	return w.resolvedValue(w.Block, gid.Block, tooltip)
+	return w.resolvedDefense(w.Block, gid.Block, tooltip)
+}
+
+func (w *Weapon) resolvedDefense(text, baseDefaultType string, tooltip *xio.ByteBuffer) string {
+	result, list, ok := w.DiagnoseExpressions(text)
+	if !ok {
+		return w.resolvedValue(text, baseDefaultType, tooltip)
+	}
+	if tooltip != nil {
+		for _, d := range list {
+			if d.Err != nil {
+				problem := d.Problem
+				if problem == "" {
+					problem = d.Err.Error()
+				}
+				fmt.Fprintf(tooltip, i18n.Text("\nUnable to evaluate %s: %s"), d.Expression, problem)
+			}
+		}
+	}
+	return result
 }
 
 // ResolvedRange returns the range, fully resolved for the user's ST, if possible.
 func (w *Weapon) ResolvedRange() string {
+	if result, ok := w.resolveExpressions(w.Range); ok {
+		return result
+	}
 	//nolint:ifshort // No, pc isn't just used on the next line...
 	pc := w.PC()
 	if pc == nil {
@@ -668,6 +703,7 @@ func (w *Weapon) SetChildren(_ []*Weapon) {
 // CellData returns the cell data information for the given column.
 func (w *Weapon) CellData(column int, data *CellData) {
 	var buffer xio.ByteBuffer
+	var expressionText string
 	data.Type = Text
 	switch column {
 	case WeaponDescriptionColumn:
@@ -679,32 +715,50 @@ func (w *Weapon) CellData(column int, data *CellData) {
 		data.Primary = w.SkillLevel(&buffer).String()
 	case WeaponParryColumn:
 		data.Primary = w.ResolvedParry(&buffer)
+		expressionText = w.Parry
 	case WeaponBlockColumn:
 		data.Primary = w.ResolvedBlock(&buffer)
+		expressionText = w.Block
 	case WeaponDamageColumn:
 		data.Primary = w.Damage.ResolvedDamage(&buffer)
 	case WeaponReachColumn:
-		data.Primary = w.Reach
+		data.Primary = w.ResolvedReach()
+		expressionText = w.Reach
 	case WeaponSTColumn:
 		data.Primary = w.MinimumStrength
 	case WeaponAccColumn:
-		data.Primary = w.Accuracy
+		data.Primary = w.ResolvedAccuracy()
+		expressionText = w.Accuracy
 	case WeaponRangeColumn:
 		data.Primary = w.ResolvedRange()
+		expressionText = w.Range
 	case WeaponRoFColumn:
-		data.Primary = w.RateOfFire
+		data.Primary = w.ResolvedRateOfFire()
+		expressionText = w.RateOfFire
 	case WeaponShotsColumn:
-		data.Primary = w.Shots
+		data.Primary = w.ResolvedShots()
+		data.Tooltip = w.ShotsTooltip()
+		expressionText = w.Shots
 	case WeaponBulkColumn:
-		data.Primary = w.Bulk
+		data.Primary = w.ResolvedBulk()
+		expressionText = w.Bulk
 	case WeaponRecoilColumn:
-		data.Primary = w.Recoil
+		data.Primary = w.ResolvedRecoil()
+		expressionText = w.Recoil
 	case PageRefCellAlias:
 		data.Type = PageRef
 	}
 	if buffer.Len() > 0 {
 		data.Tooltip = i18n.Text("Includes modifiers from:") + buffer.String()
 	}
+	if expressionText != "" {
+		if breakdown := w.ExpressionTooltip(expressionText); breakdown != "" {
+			if data.Tooltip != "" {
+				data.Tooltip += "\n\n"
+			}
+			data.Tooltip += breakdown
+		}
+	}
 }
 
 // OwningEntity returns the owning Entity.
@@ -0,0 +1,176 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package gurps
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// EquipmentByID returns the equipment with the given ID, looking in both the carried and other equipment lists.
+// Returns nil if no match is found.
+func (e *Entity) EquipmentByID(id string) *Equipment {
+	if id == "" {
+		return nil
+	}
+	var found *Equipment
+	f := func(eqp *Equipment) bool {
+		if eqp.ID.String() == id {
+			found = eqp
+			return true
+		}
+		return false
+	}
+	Traverse(f, false, false, e.CarriedEquipment...)
+	if found == nil {
+		Traverse(f, false, false, e.OtherEquipment...)
+	}
+	return found
+}
+
+// Ammo returns the equipment being used as ammunition for this weapon, if any.
+func (w *Weapon) Ammo() *Equipment {
+	if entity := w.Entity(); entity != nil {
+		return entity.EquipmentByID(w.AmmoID)
+	}
+	return nil
+}
+
+// ShotsCapacity returns the number of shots the weapon holds when fully loaded, as parsed from the Shots field. For
+// example, "30+1(3)" yields 31. Returns 0 if the number of shots can't be determined, such as for thrown weapons.
+func (w *Weapon) ShotsCapacity() int {
+	s := strings.TrimSpace(w.ShotsText())
+	if i := strings.IndexByte(s, '('); i != -1 {
+		s = s[:i]
+	}
+	total := 0
+	for _, part := range strings.Split(s, "+") {
+		value := 0
+		for _, ch := range strings.TrimSpace(part) {
+			if ch < '0' || ch > '9' {
+				break
+			}
+			value = value*10 + int(ch-'0')
+		}
+		total += value
+	}
+	return total
+}
+
+// ReloadTime returns the reload time text, as parsed from the parenthetical portion of the Shots field.
+func (w *Weapon) ReloadTime() string {
+	shots := w.ShotsText()
+	start := strings.IndexByte(shots, '(')
+	if start == -1 {
+		return ""
+	}
+	end := strings.IndexByte(shots[start:], ')')
+	if end == -1 {
+		return strings.TrimSpace(shots[start+1:])
+	}
+	return strings.TrimSpace(shots[start+1 : start+end])
+}
+
+// ShotsRemaining returns the number of shots remaining in the weapon.
+func (w *Weapon) ShotsRemaining() int {
+	if remaining := w.ShotsCapacity() - w.ShotsUsed; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// SpareAmmo returns the quantity of spare ammunition (magazines, clips, or individual rounds) available.
+func (w *Weapon) SpareAmmo() fxp.Int {
+	if ammo := w.Ammo(); ammo != nil {
+		return ammo.Quantity
+	}
+	return 0
+}
+
+// CanFire returns true if the weapon has at least the specified number of shots available.
+func (w *Weapon) CanFire(shots int) bool {
+	if shots < 1 {
+		return false
+	}
+	if w.ShotsCapacity() > 0 {
+		return w.ShotsRemaining() >= shots
+	}
+	return w.Ammo() != nil && w.SpareAmmo() >= fxp.From(shots)
+}
+
+// Fire expends the specified number of shots. Weapons that don't hold a number of shots, such as bows, draw directly
+// from their linked ammunition instead.
+func (w *Weapon) Fire(shots int) error {
+	if !w.CanFire(shots) {
+		return errs.New(i18n.Text("Insufficient ammunition."))
+	}
+	if w.ShotsCapacity() > 0 {
+		w.ShotsUsed += shots
+	} else {
+		w.Ammo().Quantity -= fxp.From(shots)
+	}
+	return nil
+}
+
+// CanReload returns true if the weapon can be reloaded.
+func (w *Weapon) CanReload() bool {
+	if w.ShotsCapacity() == 0 || w.ShotsUsed == 0 {
+		return false
+	}
+	ammo := w.Ammo()
+	return ammo == nil || ammo.Quantity >= fxp.One
+}
+
+// Reload the weapon, consuming one unit of the linked ammunition, if any.
+func (w *Weapon) Reload() error {
+	if !w.CanReload() {
+		return errs.New(i18n.Text("Unable to reload."))
+	}
+	if ammo := w.Ammo(); ammo != nil {
+		ammo.Quantity -= fxp.One
+	}
+	w.ShotsUsed = 0
+	return nil
+}
+
+// ResolvedShots returns the shots, prefixed with the number of shots remaining when they are being tracked.
+func (w *Weapon) ResolvedShots() string {
+	if w.ShotsCapacity() == 0 || (w.ShotsUsed == 0 && w.AmmoID == "") {
+		return w.ShotsText()
+	}
+	return fmt.Sprintf("%d/%s", w.ShotsRemaining(), w.ShotsText())
+}
+
+// ShotsTooltip returns a tooltip describing the current ammunition state.
+func (w *Weapon) ShotsTooltip() string {
+	var buffer strings.Builder
+	if capacity := w.ShotsCapacity(); capacity > 0 {
+		fmt.Fprintf(&buffer, i18n.Text("Loaded: %d of %d"), w.ShotsRemaining(), capacity)
+	}
+	if reload := w.ReloadTime(); reload != "" {
+		if buffer.Len() != 0 {
+			buffer.WriteByte('\n')
+		}
+		fmt.Fprintf(&buffer, i18n.Text("Reload: %s"), reload)
+	}
+	if ammo := w.Ammo(); ammo != nil {
+		if buffer.Len() != 0 {
+			buffer.WriteByte('\n')
+		}
+		fmt.Fprintf(&buffer, i18n.Text("Ammunition: %s (%s spare)"), ammo.Description(), ammo.Quantity.Comma())
+	}
+	return buffer.String()
+}
@@ -245,6 +245,21 @@ func (w *WeaponDamage) ResolvedDamage(tooltip *xio.ByteBuffer) string {
 			return false
 		}, true, true, eqp.Modifiers...)
 	}
+	damageType := w.Type
+	if ammo := w.Owner.Ammo(); ammo != nil {
+		for _, f := range ammo.FeatureList() {
+			w.extractWeaponBonus(f, bonusSet, fxp.From(base.Count), levels, tooltip)
+		}
+		Traverse(func(mod *EquipmentModifier) bool {
+			for _, f := range mod.Features {
+				w.extractWeaponBonus(f, bonusSet, fxp.From(base.Count), levels, tooltip)
+			}
+			return false
+		}, true, true, ammo.Modifiers...)
+		if t := strings.TrimSpace(ammo.AmmoDamageType); t != "" {
+			damageType = t
+		}
+	}
 	adjustForPhoenixFlame := pc.SheetSettings.DamageProgression == attribute.PhoenixFlameD3 && base.Sides == 3
 	var percentDamageBonus, percentDRDivisorBonus fxp.Int
 	armorDivisor := w.ArmorDivisor
@@ -296,11 +311,11 @@ func (w *WeaponDamage) ResolvedDamage(tooltip *xio.ByteBuffer) string {
 		buffer.WriteString(armorDivisor.String())
 		buffer.WriteByte(')')
 	}
-	if strings.TrimSpace(w.Type) != "" {
+	if strings.TrimSpace(damageType) != "" {
 		if buffer.Len() != 0 {
 			buffer.WriteByte(' ')
 		}
-		buffer.WriteString(w.Type)
+		buffer.WriteString(damageType)
 	}
 	if w.Fragmentation != nil {
 		if frag := w.Fragmentation.StringExtra(pc.SheetSettings.UseModifyingDicePlusAdds); frag != "0" {
@@ -0,0 +1,223 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package gurps
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// Variables that are available to expressions within weapon stat fields, in addition to those provided by the entity.
+// Unlike entity variables, these may be referenced with or without a leading '$' within braces.
+const (
+	WeaponSkillLevelVariable    = "skill_level"
+	WeaponParryBonusVariable    = "parry_bonus"
+	WeaponBlockBonusVariable    = "block_bonus"
+	WeaponThrowingSTVariable    = "throwing_st"
+	WeaponMinimumSTVariable     = "min_st"
+	weaponExpressionStartMarker = '{'
+	weaponExpressionEndMarker   = '}'
+)
+
+var (
+	// Go's regexp package doesn't support look-behind, so the character preceding the variable name is captured and
+	// written back out when the '$' is inserted.
+	weaponVariableRegex = regexp.MustCompile(`(^|[^$\w.])(` + WeaponSkillLevelVariable + `|` +
+		WeaponParryBonusVariable + `|` + WeaponBlockBonusVariable + `|` + WeaponThrowingSTVariable + `|` +
+		WeaponMinimumSTVariable + `)\b`)
+	// weaponExpressionRegex matches a variable reference or an expression enclosed in braces.
+	weaponExpressionRegex = regexp.MustCompile(`\$[A-Za-z_]|\{[^{}]*\}`)
+)
+
+type weaponVariableResolver struct {
+	weapon *Weapon
+	entity *Entity
+}
+
+func (r *weaponVariableResolver) ResolveVariable(variableName string) string {
+	switch variableName {
+	case WeaponSkillLevelVariable:
+		return r.weapon.SkillLevel(nil).String()
+	case WeaponParryBonusVariable:
+		return r.entity.ParryBonus.String()
+	case WeaponBlockBonusVariable:
+		return r.entity.BlockBonus.String()
+	case WeaponThrowingSTVariable:
+		return (r.entity.StrengthOrZero() + r.entity.ThrowingStrengthBonus).Trunc().String()
+	case WeaponMinimumSTVariable:
+		return r.weapon.ResolvedMinimumStrength().String()
+	default:
+		return r.entity.ResolveVariable(variableName)
+	}
+}
+
+// IsWeaponExpression returns true if the text of a weapon stat field should be evaluated as an expression rather than
+// parsed in the traditional manner. This is the case when the text references a variable, such as "$st*15", or contains
+// one or more expressions enclosed in braces, such as "{$dx/4}F".
+func IsWeaponExpression(text string) bool {
+	return weaponExpressionRegex.MatchString(text)
+}
+
+// resolveExpressions evaluates the expressions within the text, returning the result and true if the text was treated
+// as an expression.
+func (w *Weapon) resolveExpressions(text string) (string, bool) {
+	if !IsWeaponExpression(text) {
+		return text, false
+	}
+	resolver := w.expressionResolver()
+	if resolver == nil {
+		return text, true
+	}
+	return expandWeaponExpressions(text, func(expression string) (string, bool) {
+		return fxp.EvaluateToNumber(prepareWeaponExpression(expression), resolver).Trunc().String(), true
+	}), true
+}
+
+// DiagnoseExpressions evaluates the expressions within the text of a weapon stat field, returning the resolved text
+// along with a diagnostic for each expression. When the text contains braces, only the portions within them are
+// evaluated; otherwise, the whole text is. Expressions that fail to evaluate are left as-is. Returns false if the text
+// isn't an expression.
+func (w *Weapon) DiagnoseExpressions(text string) (string, []*ExpressionDiagnostic, bool) {
+	if !IsWeaponExpression(text) {
+		return text, nil, false
+	}
+	resolver := w.expressionResolver()
+	if resolver == nil {
+		return text, nil, true
+	}
+	var list []*ExpressionDiagnostic
+	result := expandWeaponExpressions(text, func(expression string) (string, bool) {
+		d := DiagnoseExpression(prepareWeaponExpression(expression), resolver)
+		list = append(list, d)
+		if d.Err != nil {
+			return "", false
+		}
+		return d.Result.Trunc().String(), true
+	})
+	return result, list, true
+}
+
+func (w *Weapon) expressionResolver() *weaponVariableResolver {
+	pc := w.PC()
+	if pc == nil {
+		return nil
+	}
+	return &weaponVariableResolver{
+		weapon: w,
+		entity: pc,
+	}
+}
+
+// prepareWeaponExpression adds the leading '$' to any references to the weapon variables that lack one.
+func prepareWeaponExpression(expression string) string {
+	return weaponVariableRegex.ReplaceAllString(strings.TrimSpace(expression), "${1}$$${2}")
+}
+
+// expandWeaponExpressions replaces each expression within the text with the result of passing it to resolve.
+func expandWeaponExpressions(text string, resolve func(expression string) (string, bool)) string {
+	if strings.IndexByte(text, weaponExpressionStartMarker) == -1 {
+		if result, ok := resolve(text); ok {
+			return result
+		}
+		return text
+	}
+	var buffer strings.Builder
+	for {
+		start := strings.IndexByte(text, weaponExpressionStartMarker)
+		if start == -1 {
+			break
+		}
+		end := strings.IndexByte(text[start:], weaponExpressionEndMarker)
+		if end == -1 {
+			break
+		}
+		end += start
+		buffer.WriteString(text[:start])
+		if result, ok := resolve(text[start+1 : end]); ok {
+			buffer.WriteString(result)
+		} else {
+			buffer.WriteString(text[start : end+1])
+		}
+		text = text[end+1:]
+	}
+	buffer.WriteString(text)
+	return buffer.String()
+}
+
+// ExpressionTooltip returns a description of the calculations performed to resolve the text of a weapon stat field, or
+// an empty string if the text isn't an expression.
+func (w *Weapon) ExpressionTooltip(text string) string {
+	_, list, ok := w.DiagnoseExpressions(text)
+	if !ok {
+		return ""
+	}
+	var breakdown strings.Builder
+	for _, d := range list {
+		if breakdown.Len() != 0 {
+			breakdown.WriteByte('\n')
+		}
+		breakdown.WriteString(d.Expression)
+		if d.Err != nil {
+			problem := d.Problem
+			if problem == "" {
+				problem = d.Err.Error()
+			}
+			fmt.Fprintf(&breakdown, i18n.Text("\nUnable to evaluate: %s"), problem)
+			continue
+		}
+		if d.Expanded != "" && d.Expanded != d.Expression {
+			fmt.Fprintf(&breakdown, "\n= %s", d.Expanded)
+		}
+		fmt.Fprintf(&breakdown, "\n= %s", d.Result.Trunc().String())
+	}
+	return breakdown.String()
+}
+
+// ResolvedReach returns the reach, with any expressions resolved.
+func (w *Weapon) ResolvedReach() string {
+	result, _ := w.resolveExpressions(w.Reach)
+	return result
+}
+
+// ResolvedAccuracy returns the accuracy, with any expressions resolved.
+func (w *Weapon) ResolvedAccuracy() string {
+	result, _ := w.resolveExpressions(w.Accuracy)
+	return result
+}
+
+// ResolvedRateOfFire returns the rate of fire, with any expressions resolved.
+func (w *Weapon) ResolvedRateOfFire() string {
+	result, _ := w.resolveExpressions(w.RateOfFire)
+	return result
+}
+
+// ResolvedBulk returns the bulk, with any expressions resolved.
+func (w *Weapon) ResolvedBulk() string {
+	result, _ := w.resolveExpressions(w.Bulk)
+	return result
+}
+
+// ResolvedRecoil returns the recoil, with any expressions resolved.
+func (w *Weapon) ResolvedRecoil() string {
+	result, _ := w.resolveExpressions(w.Recoil)
+	return result
+}
+
+// ShotsText returns the shots, with any expressions resolved, but without the number of shots remaining.
+func (w *Weapon) ShotsText() string {
+	result, _ := w.resolveExpressions(w.Shots)
+	return result
+}
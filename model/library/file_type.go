@@ -28,6 +28,7 @@ const (
 
 // Primary GCS file extensions.
 const (
+	BundleExt             = ".gcsz"
 	EquipmentExt          = ".eqp"
 	EquipmentModifiersExt = ".eqm"
 	NotesExt              = ".not"
@@ -37,6 +38,13 @@ const (
 	TemplatesExt          = ".gct"
 	TraitModifiersExt     = ".adm"
 	TraitsExt             = ".adq"
+	VehiclesExt           = ".gcv"
+)
+
+// Foreign file extensions that can be imported.
+const (
+	GCA4Ext = ".gca4"
+	GCA5Ext = ".gca5"
 )
 
 // Secondary GCS file extensions (no visible display for these, since you don't open them into a view).
@@ -49,6 +57,7 @@ const (
 	BodyExtAlt         = ".ghl"
 	CalendarExt        = ".calendar"
 	ColorSettingsExt   = ".colors"
+	CurrencyExt        = ".currency"
 	FontSettingsExt    = ".fonts"
 	GeneralSettingsExt = ".general"
 	KeySettingsExt     = ".keys"
@@ -131,6 +140,7 @@ func GCSSecondaryExtensions() []string {
 		BodyExtAlt,
 		CalendarExt,
 		ColorSettingsExt,
+		CurrencyExt,
 		FontSettingsExt,
 		GeneralSettingsExt,
 		KeySettingsExt,
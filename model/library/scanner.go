@@ -63,6 +63,21 @@ func ScanForNamedFileSets(builtIn fs.FS, builtInDir string, omitDuplicateNames b
 	return list
 }
 
+// ScanForLibraryFiles scans the libraries for data files of a particular type.
+func ScanForLibraryFiles(libraries Libraries, extensions ...string) []*NamedFileSet {
+	set := make(map[string]bool)
+	list := make([]*NamedFileSet, 0)
+	for _, lib := range libraries.List() {
+		if refs := scanForNamedFileSets(os.DirFS(lib.Path()), ".", extensions, false, set); len(refs) != 0 {
+			list = append(list, &NamedFileSet{
+				Name: lib.Title,
+				List: refs,
+			})
+		}
+	}
+	return list
+}
+
 func scanForNamedFileSets(fileSystem fs.FS, dirPath string, extensions []string, omitDuplicateNames bool, set map[string]bool) []*NamedFileRef {
 	extMap := make(map[string]bool, len(extensions))
 	for _, ext := range extensions {
@@ -0,0 +1,28 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package paper
+
+// Dimensions returns the card dimensions.
+func (enum CardSize) Dimensions() (width, height Length) {
+	switch enum {
+	case PokerCardSize:
+		return Length{Length: 2.5, Units: Inch}, Length{Length: 3.5, Units: Inch}
+	case BridgeCardSize:
+		return Length{Length: 2.25, Units: Inch}, Length{Length: 3.5, Units: Inch}
+	case TarotCardSize:
+		return Length{Length: 2.75, Units: Inch}, Length{Length: 4.75, Units: Inch}
+	case IndexCardSize:
+		return Length{Length: 3, Units: Inch}, Length{Length: 5, Units: Inch}
+	default:
+		return PokerCardSize.Dimensions()
+	}
+}
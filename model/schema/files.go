@@ -0,0 +1,244 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package schema
+
+import (
+	"fmt"
+	"io/fs"
+	"reflect"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/ancestry"
+	"github.com/richardwilkes/gcs/v5/model/gurps/gid"
+	gsettings "github.com/richardwilkes/gcs/v5/model/gurps/settings"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/gcs/v5/model/settings"
+	"github.com/richardwilkes/gcs/v5/model/theme"
+	"github.com/richardwilkes/rpgtools/calendar"
+)
+
+// fileType describes the JSON content of the files with the given extensions.
+type fileType struct {
+	extensions []string
+	title      string
+	// typeKey is the value of the "type" field that precedes the data, along with its "version". Empty if the file
+	// has no such header.
+	typeKey string
+	data    reflect.Type
+	load    func(fileSystem fs.FS, filePath string) error
+}
+
+// rows mirrors the layout of the list files, whose items are held in a "rows" array following the header.
+type rows[T any] struct {
+	Rows []T `json:"rows"`
+}
+
+type attributeRows struct {
+	Rows *gurps.AttributeDefs `json:"rows,alt=attributes"`
+}
+
+type currencies struct {
+	Currencies []*gsettings.Currency `json:"currencies"`
+}
+
+var fileTypes = []*fileType{
+	{
+		extensions: []string{library.SheetExt},
+		title:      "GCS Character Sheet",
+		typeKey:    "character",
+		data:       typeOf[gurps.Entity](),
+		load:       loader(gurps.NewEntityFromFile),
+	},
+	{
+		extensions: []string{library.TemplatesExt},
+		title:      "GCS Character Template",
+		typeKey:    "template",
+		data:       typeOf[gurps.Template](),
+		load:       loader(gurps.NewTemplateFromFile),
+	},
+	{
+		extensions: []string{library.VehiclesExt},
+		title:      "GCS Vehicle",
+		typeKey:    "vehicle",
+		data:       typeOf[gurps.Vehicle](),
+		load:       loader(gurps.NewVehicleFromFile),
+	},
+	{
+		extensions: []string{library.TraitsExt},
+		title:      "GCS Traits",
+		typeKey:    "trait_list",
+		data:       typeOf[rows[*gurps.Trait]](),
+		load:       loader(gurps.NewTraitsFromFile),
+	},
+	{
+		extensions: []string{library.TraitModifiersExt},
+		title:      "GCS Trait Modifiers",
+		typeKey:    "modifier_list",
+		data:       typeOf[rows[*gurps.TraitModifier]](),
+		load:       loader(gurps.NewTraitModifiersFromFile),
+	},
+	{
+		extensions: []string{library.EquipmentExt},
+		title:      "GCS Equipment",
+		typeKey:    "equipment_list",
+		data:       typeOf[rows[*gurps.Equipment]](),
+		load:       loader(gurps.NewEquipmentFromFile),
+	},
+	{
+		extensions: []string{library.EquipmentModifiersExt},
+		title:      "GCS Equipment Modifiers",
+		typeKey:    "eqp_modifier_list",
+		data:       typeOf[rows[*gurps.EquipmentModifier]](),
+		load:       loader(gurps.NewEquipmentModifiersFromFile),
+	},
+	{
+		extensions: []string{library.SkillsExt},
+		title:      "GCS Skills",
+		typeKey:    "skill_list",
+		data:       typeOf[rows[*gurps.Skill]](),
+		load:       loader(gurps.NewSkillsFromFile),
+	},
+	{
+		extensions: []string{library.SpellsExt},
+		title:      "GCS Spells",
+		typeKey:    "spell_list",
+		data:       typeOf[rows[*gurps.Spell]](),
+		load:       loader(gurps.NewSpellsFromFile),
+	},
+	{
+		extensions: []string{library.NotesExt},
+		title:      "GCS Notes",
+		typeKey:    "note_list",
+		data:       typeOf[rows[*gurps.Note]](),
+		load:       loader(gurps.NewNotesFromFile),
+	},
+	{
+		extensions: []string{library.AncestryExt},
+		title:      "GCS Ancestry",
+		typeKey:    "ancestry",
+		data:       typeOf[ancestry.Ancestry](),
+		load:       loader(ancestry.NewAncestryFromFile),
+	},
+	{
+		extensions: []string{library.AttributesExt, library.AttributesExtAlt1, library.AttributesExtAlt2},
+		title:      "GCS Attributes",
+		typeKey:    "attribute_settings",
+		data:       typeOf[attributeRows](),
+		load:       loader(gurps.NewAttributeDefsFromFile),
+	},
+	{
+		extensions: []string{library.BodyExt, library.BodyExtAlt},
+		title:      "GCS Body Type",
+		typeKey:    "body_type",
+		data:       typeOf[gurps.Body](),
+		load:       loader(gurps.NewBodyFromFile),
+	},
+	{
+		extensions: []string{library.CalendarExt},
+		title:      "GCS Calendar",
+		data:       typeOf[calendar.Calendar](),
+		load:       loader(gsettings.NewCalendarRefFromFS),
+	},
+	{
+		extensions: []string{library.ColorSettingsExt},
+		title:      "GCS Theme Colors",
+		data:       typeOf[theme.Colors](),
+		load:       loader(theme.NewColorsFromFS),
+	},
+	{
+		extensions: []string{library.CurrencyExt},
+		title:      "GCS Currencies",
+		typeKey:    "currencies",
+		data:       typeOf[currencies](),
+		load:       loader(gsettings.NewCurrencyRefFromFS),
+	},
+	{
+		extensions: []string{library.FontSettingsExt},
+		title:      "GCS Theme Fonts",
+		data:       typeOf[theme.Fonts](),
+		load:       loader(theme.NewFontsFromFS),
+	},
+	{
+		extensions: []string{library.GeneralSettingsExt},
+		title:      "GCS General Settings",
+		data:       typeOf[gsettings.General](),
+		load:       loader(gsettings.NewGeneralFromFile),
+	},
+	{
+		extensions: []string{library.KeySettingsExt},
+		title:      "GCS Key Bindings",
+		data:       typeOf[settings.KeyBindings](),
+		load:       loader(settings.NewKeyBindingsFromFS),
+	},
+	{
+		extensions: []string{library.NamesExt},
+		title:      "GCS Name Generator",
+		data:       typeOf[ancestry.NameGenerator](),
+		load:       loader(ancestry.NewNameGeneratorFromFS),
+	},
+	{
+		extensions: []string{library.PageRefSettingsExt},
+		title:      "GCS Page References",
+		data:       typeOf[settings.PageRefs](),
+		load:       loader(settings.NewPageRefsFromFS),
+	},
+	{
+		extensions: []string{library.SheetSettingsExt},
+		title:      "GCS Sheet Settings",
+		data:       typeOf[gurps.SheetSettings](),
+		load:       loader(gurps.NewSheetSettingsFromFile),
+	},
+}
+
+func loader[T any](f func(fileSystem fs.FS, filePath string) (T, error)) func(fileSystem fs.FS, filePath string) error {
+	return func(fileSystem fs.FS, filePath string) error {
+		_, err := f(fileSystem, filePath)
+		return err
+	}
+}
+
+func fileTypeFor(ext string) *fileType {
+	for _, ft := range fileTypes {
+		for _, one := range ft.extensions {
+			if strings.EqualFold(one, ext) {
+				return ft
+			}
+		}
+	}
+	return nil
+}
+
+// schema generates the schema for the file type.
+func (ft *fileType) schema() *Schema {
+	g := newGenerator()
+	s := g.build(ft.data)
+	if ft.typeKey != "" {
+		if s.Properties == nil {
+			s.Properties = make(map[string]*Schema)
+		}
+		s.Properties["type"] = &Schema{Type: Types{"string"}, Const: ft.typeKey}
+		s.Properties["version"] = &Schema{
+			Type: Types{"integer"},
+			Description: fmt.Sprintf("Data versions %d through %d can be loaded.", gid.MinimumDataVersion,
+				gid.CurrentDataVersion),
+		}
+		s.Required = append([]string{"type", "version"}, s.Required...)
+	}
+	s.Dialect = Draft
+	s.Title = ft.title
+	s.Description = fmt.Sprintf("Files with the extension %s.", strings.Join(ft.extensions, ", "))
+	if len(g.defs) != 0 {
+		s.Defs = g.defs
+	}
+	return s
+}
@@ -0,0 +1,258 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package schema
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/richardwilkes/json"
+)
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	unmarshalerType   = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// generator builds schemas from the Go types of the data, following the same rules the json package uses to encode
+// and decode them. Named struct types are placed in $defs so that recursive types, such as containers that hold
+// children of their own type, can be described.
+type generator struct {
+	defs    map[string]*Schema
+	names   map[reflect.Type]string
+	claimed map[string]reflect.Type
+}
+
+func newGenerator() *generator {
+	return &generator{
+		defs:    make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+		claimed: make(map[string]reflect.Type),
+	}
+}
+
+// schemaFor returns the schema for the type. nullable should be true when the json package may write null for the
+// value, which happens for nil pointers, slices and maps that aren't marked omitempty.
+func (g *generator) schemaFor(t reflect.Type, nullable bool) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var s *Schema
+	if t.Kind() == reflect.Struct && t.Name() != "" && overrides[t] == nil && !implements(t, textMarshalerType) {
+		s = g.ref(t)
+	} else {
+		s = g.build(t)
+	}
+	if !nullable {
+		return s
+	}
+	switch {
+	case s.Ref != "":
+		return &Schema{AnyOf: []*Schema{s, {Type: Types{"null"}}}}
+	case len(s.Type) != 0:
+		s.Type = append(s.Type, "null")
+	}
+	return s
+}
+
+// ref returns a reference to the definition of the named struct type, creating the definition if needed.
+func (g *generator) ref(t reflect.Type) *Schema {
+	name, exists := g.names[t]
+	if !exists {
+		name = g.defName(t)
+		def := &Schema{}
+		g.defs[name] = def
+		*def = *g.build(t)
+	}
+	return &Schema{Ref: "#/$defs/" + name}
+}
+
+func (g *generator) defName(t reflect.Type) string {
+	base := strings.Map(func(r rune) rune {
+		if r == '.' || r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			return r
+		}
+		return '_'
+	}, t.String())
+	name := base
+	for i := 2; g.claimed[name] != nil; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.claimed[name] = t
+	g.names[t] = name
+	return name
+}
+
+// build returns the schema for the type itself, without using a reference.
+func (g *generator) build(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if f, exists := overrides[t]; exists {
+		return f(g)
+	}
+	if t.Kind() == reflect.Struct && (implements(t, marshalerType) || implements(t, unmarshalerType)) {
+		// Types with their own JSON handling keep their persistent fields in an embedded XxxData struct, adding
+		// calculated values for the benefit of other tools when they are written.
+		if data, ok := embeddedData(t); ok {
+			s := g.build(data)
+			if implements(t, marshalerType) {
+				s.Properties["calc"] = &Schema{
+					Description: "Values calculated by GCS when the file was written. These are ignored when loading.",
+					Type:        Types{"object"},
+					ReadOnly:    true,
+				}
+			}
+			return s
+		}
+	}
+	if implements(t, marshalerType) {
+		return &Schema{Description: fmt.Sprintf("Data in the format written by %s.", t)}
+	}
+	if implements(t, textMarshalerType) {
+		s := &Schema{Type: Types{"string"}}
+		if keys := enumKeys(t); len(keys) != 0 {
+			s.Enum = keys
+		}
+		return s
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: Types{"array"}, Items: g.schemaFor(t.Elem(), false)}
+	case reflect.Map:
+		return &Schema{Type: Types{"object"}, AdditionalProperties: g.schemaFor(t.Elem(), false)}
+	case reflect.Struct:
+		s := &Schema{
+			Type:                 Types{"object"},
+			Properties:           make(map[string]*Schema),
+			AdditionalProperties: false,
+		}
+		g.addFields(s, t, 0, make(map[string]int))
+		return s
+	default:
+		return &Schema{}
+	}
+}
+
+// addFields adds the fields of the struct to the schema's properties. Fields of embedded structs without a name in
+// their tag are promoted, with shallower fields hiding deeper ones of the same name, just as the json package does.
+func (g *generator) addFields(s *Schema, t reflect.Type, depth int, depths map[string]int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(s, ft, depth+1, depths)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if prior, exists := depths[name]; exists && prior <= depth {
+			continue
+		}
+		var omitEmpty bool
+		var alt string
+		for _, option := range strings.Split(options, ",") {
+			if option == "omitempty" {
+				omitEmpty = true
+			} else if strings.HasPrefix(option, "alt=") {
+				alt = strings.TrimPrefix(option, "alt=")
+			}
+		}
+		fs := g.schemaFor(f.Type, !omitEmpty && canBeNull(f.Type))
+		s.Properties[name] = fs
+		depths[name] = depth
+		if alt != "" {
+			altSchema := *fs
+			altSchema.Description = fmt.Sprintf("Older name for %s, still accepted when loading.", name)
+			altSchema.Deprecated = true
+			s.Properties[alt] = &altSchema
+			depths[alt] = depth
+		}
+	}
+}
+
+// embeddedData returns the type of the XxxData struct embedded within the type, if there is one.
+func embeddedData(t reflect.Type) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Anonymous && f.Type.Kind() == reflect.Struct && strings.HasSuffix(f.Name, "Data") {
+			return f.Type, true
+		}
+	}
+	return nil, false
+}
+
+// enumKeys returns the keys of a generated enumeration type, which can be recognized by its EnsureValid() method.
+// Values are walked upward from zero until EnsureValid() no longer returns the value unchanged.
+func enumKeys(t reflect.Type) []any {
+	method, exists := t.MethodByName("EnsureValid")
+	if !exists || method.Type.NumIn() != 1 || method.Type.NumOut() != 1 || method.Type.Out(0) != t ||
+		t.Kind() != reflect.Uint8 {
+		return nil
+	}
+	var keys []any
+	for i := 0; i < 256; i++ {
+		v := reflect.New(t).Elem()
+		v.SetUint(uint64(i))
+		if method.Func.Call([]reflect.Value{v})[0].Uint() != uint64(i) {
+			break
+		}
+		m, ok := v.Interface().(encoding.TextMarshaler)
+		if !ok {
+			return nil
+		}
+		text, err := m.MarshalText()
+		if err != nil {
+			return nil
+		}
+		keys = append(keys, string(text))
+	}
+	return keys
+}
+
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+func canBeNull(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package schema
+
+import (
+	"reflect"
+
+	"github.com/google/uuid"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/feature"
+	"github.com/richardwilkes/gcs/v5/model/gurps/measure"
+	"github.com/richardwilkes/gcs/v5/model/gurps/prereq"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/gcs/v5/model/paper"
+	"github.com/richardwilkes/gcs/v5/model/settings"
+	"github.com/richardwilkes/gcs/v5/model/theme"
+	"github.com/richardwilkes/unison"
+)
+
+// overrides describe the types whose custom JSON handling can't be discovered through reflection.
+var overrides map[reflect.Type]func(g *generator) *Schema
+
+func init() {
+	overrides = map[reflect.Type]func(g *generator) *Schema{
+		typeOf[fxp.Int]():           fixed(&Schema{Type: Types{"number"}}),
+		typeOf[measure.Length]():    fixed(&Schema{Type: Types{"string"}, Description: `A length, such as "6 ft"`}),
+		typeOf[measure.Weight]():    fixed(&Schema{Type: Types{"string"}, Description: `A weight, such as "2.5 lb"`}),
+		typeOf[measure.Volume]():    fixed(&Schema{Type: Types{"string"}, Description: `A volume, such as "2 cu ft"`}),
+		typeOf[measure.Area]():      fixed(&Schema{Type: Types{"string"}, Description: `An area, such as "4 sq ft"`}),
+		typeOf[paper.Length]():      fixed(&Schema{Type: Types{"string"}, Description: `A length, such as "0.25 in"`}),
+		typeOf[jio.Time]():          fixed(&Schema{Type: Types{"string"}, Format: "date-time"}),
+		typeOf[uuid.UUID]():         fixed(&Schema{Type: Types{"string"}, Format: "uuid"}),
+		typeOf[gurps.CollegeList](): fixed(&Schema{Type: Types{"array"}, Items: &Schema{Type: Types{"string"}}}),
+		typeOf[gurps.BlockLayout](): fixed(&Schema{Type: Types{"array"}, Items: &Schema{Type: Types{"string"}}}),
+		typeOf[gurps.AttributeDifficulty](): fixed(&Schema{
+			Type:        Types{"string"},
+			Description: `A difficulty, optionally preceded by the ID of the attribute it is based on, such as "dx/a"`,
+		}),
+		typeOf[gurps.Attributes]():     arrayOf[*gurps.Attribute](),
+		typeOf[gurps.AttributeDefs]():  arrayOf[*gurps.AttributeDef](),
+		typeOf[theme.Colors]():         objectOf[*unison.ThemeColor](),
+		typeOf[theme.Fonts]():          objectOf[unison.FontDescriptor](),
+		typeOf[settings.KeyBindings](): objectOf[unison.KeyBinding](),
+		typeOf[settings.PageRefs]():    objectOf[*settings.PageRef](),
+		typeOf[feature.Features]():     featureList,
+		typeOf[gurps.Prereqs]():        prereqList,
+	}
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func fixed(s *Schema) func(g *generator) *Schema {
+	return func(_ *generator) *Schema {
+		clone := *s
+		return &clone
+	}
+}
+
+func arrayOf[T any]() func(g *generator) *Schema {
+	return func(g *generator) *Schema {
+		return &Schema{Type: Types{"array"}, Items: g.schemaFor(typeOf[T](), false)}
+	}
+}
+
+func objectOf[T any]() func(g *generator) *Schema {
+	return func(g *generator) *Schema {
+		return &Schema{Type: Types{"object"}, AdditionalProperties: g.schemaFor(typeOf[T](), false)}
+	}
+}
+
+// variant returns a branch of a union whose members are chosen by the value of their "type" property.
+func variant(g *generator, t reflect.Type, keys ...string) *Schema {
+	s := g.schemaFor(t, false)
+	list := make([]any, len(keys))
+	for i, key := range keys {
+		list[i] = key
+	}
+	s.Properties = map[string]*Schema{"type": {Type: Types{"string"}, Enum: list}}
+	return s
+}
+
+// variantList returns an array of a union with a branch for each of the types, in the order given. Types held by the
+// same data type share a branch.
+func variantList[T interface {
+	comparable
+	Key() string
+}](g *generator, all []T, dataTypes map[T]reflect.Type) *Schema {
+	var order []reflect.Type
+	keys := make(map[reflect.Type][]string)
+	for _, one := range all {
+		if t, exists := dataTypes[one]; exists {
+			if _, seen := keys[t]; !seen {
+				order = append(order, t)
+			}
+			keys[t] = append(keys[t], one.Key())
+		}
+	}
+	choices := make([]*Schema, 0, len(order))
+	for _, t := range order {
+		choices = append(choices, variant(g, t, keys[t]...))
+	}
+	return &Schema{Type: Types{"array"}, Items: &Schema{OneOf: choices}}
+}
+
+// featureDataTypes maps each of feature.AllType onto the type that holds its data.
+var featureDataTypes = map[feature.Type]reflect.Type{
+	feature.AttributeBonusType:           typeOf[feature.AttributeBonus](),
+	feature.ConditionalModifierType:      typeOf[feature.ConditionalModifier](),
+	feature.ContainedWeightReductionType: typeOf[feature.ContainedWeightReduction](),
+	feature.CostReductionType:            typeOf[feature.CostReduction](),
+	feature.DRBonusType:                  typeOf[feature.DRBonus](),
+	feature.ReactionBonusType:            typeOf[feature.ReactionBonus](),
+	feature.SkillBonusType:               typeOf[feature.SkillBonus](),
+	feature.SkillPointBonusType:          typeOf[feature.SkillPointBonus](),
+	feature.SpellBonusType:               typeOf[feature.SpellBonus](),
+	feature.SpellPointBonusType:          typeOf[feature.SpellPointBonus](),
+	feature.WeaponBonusType:              typeOf[feature.WeaponBonus](),
+	feature.WeaponDRDivisorBonusType:     typeOf[feature.WeaponBonus](),
+}
+
+// prereqDataTypes maps each of prereq.AllType onto the type that holds its data.
+var prereqDataTypes = map[prereq.Type]reflect.Type{
+	prereq.List:              typeOf[gurps.PrereqList](),
+	prereq.Trait:             typeOf[gurps.TraitPrereq](),
+	prereq.Attribute:         typeOf[gurps.AttributePrereq](),
+	prereq.ContainedQuantity: typeOf[gurps.ContainedQuantityPrereq](),
+	prereq.ContainedWeight:   typeOf[gurps.ContainedWeightPrereq](),
+	prereq.Skill:             typeOf[gurps.SkillPrereq](),
+	prereq.Spell:             typeOf[gurps.SpellPrereq](),
+}
+
+func featureList(g *generator) *Schema {
+	return variantList(g, feature.AllType, featureDataTypes)
+}
+
+func prereqList(g *generator) *Schema {
+	return variantList(g, prereq.AllType, prereqDataTypes)
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package schema
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/json"
+)
+
+// Draft is the JSON Schema dialect the schemas are written in.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Ext is appended to a file type's name to form the name of its schema file, e.g. eqp.schema.json.
+const Ext = ".schema.json"
+
+// Types holds one or more JSON type names. A single type is written as a plain string.
+type Types []string
+
+// MarshalJSON implements json.Marshaler.
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// Schema holds the subset of JSON Schema needed to describe GCS data files.
+type Schema struct {
+	Dialect     string             `json:"$schema,omitempty"`
+	ID          string             `json:"$id,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        Types              `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Const       any                `json:"const,omitempty"`
+	Enum        []any              `json:"enum,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	// AdditionalProperties is either false or a *Schema.
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	ReadOnly             bool               `json:"readOnly,omitempty"`
+	Deprecated           bool               `json:"deprecated,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Allows returns true if the schema permits values of the given JSON type. A schema without any types permits all of
+// them.
+func (s *Schema) Allows(jsonType string) bool {
+	if len(s.Type) == 0 {
+		return true
+	}
+	for _, one := range s.Type {
+		if one == jsonType || (one == "number" && jsonType == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteAll writes the schema for each file type into the directory, returning the paths of the files written.
+// Alternate extensions share the schema of their primary extension.
+func WriteAll(dir string) ([]string, error) {
+	list := make([]string, 0, len(fileTypes))
+	for _, ft := range fileTypes {
+		p := filepath.Join(dir, strings.TrimPrefix(ft.extensions[0], ".")+Ext)
+		if err := jio.SaveToFile(context.Background(), p, ft.schema()); err != nil {
+			return list, err
+		}
+		list = append(list, p)
+	}
+	return list, nil
+}
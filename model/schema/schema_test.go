@@ -0,0 +1,187 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package schema_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/feature"
+	"github.com/richardwilkes/gcs/v5/model/gurps/prereq"
+	"github.com/richardwilkes/gcs/v5/model/gurps/settings"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/gcs/v5/model/schema"
+	"github.com/richardwilkes/json"
+	"github.com/stretchr/testify/assert"
+)
+
+// committedDir holds the generated schemas that are committed alongside the source. Run
+// "go test ./model/schema -update" to regenerate them after changing the data files.
+const committedDir = "../../schemas"
+
+var update = flag.Bool("update", false, "regenerate the committed schemas")
+
+type testSettings struct {
+	general *settings.General
+	sheet   *gurps.SheetSettings
+}
+
+func (s *testSettings) GeneralSettings() *settings.General {
+	return s.general
+}
+
+func (s *testSettings) SheetSettings() *gurps.SheetSettings {
+	return s.sheet
+}
+
+func (s *testSettings) Libraries() library.Libraries {
+	return nil
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	gurps.SettingsProvider = &testSettings{
+		general: settings.NewGeneral(),
+		sheet:   gurps.FactorySheetSettings(),
+	}
+	os.Exit(m.Run())
+}
+
+func TestCommittedSchemasAreCurrent(t *testing.T) {
+	if *update {
+		assert.NoError(t, os.RemoveAll(committedDir))
+		assert.NoError(t, os.MkdirAll(committedDir, 0o750))
+		_, err := schema.WriteAll(committedDir)
+		assert.NoError(t, err)
+		return
+	}
+	dir := t.TempDir()
+	if _, err := schema.WriteAll(dir); !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, fileNames(t, dir), fileNames(t, committedDir), "run go test ./model/schema -update")
+	for _, name := range fileNames(t, dir) {
+		generated, err := os.ReadFile(filepath.Join(dir, name))
+		assert.NoError(t, err)
+		var committed []byte
+		committed, err = os.ReadFile(filepath.Join(committedDir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, string(generated), string(committed), "%s is stale; run go test ./model/schema -update", name)
+	}
+}
+
+func TestEveryDataFileHasSchema(t *testing.T) {
+	dir := t.TempDir()
+	// Bundles are zip archives rather than JSON, so they are the only primary file type without a schema
+	exts := append([]string{
+		library.EquipmentExt,
+		library.EquipmentModifiersExt,
+		library.NotesExt,
+		library.SheetExt,
+		library.SkillsExt,
+		library.SpellsExt,
+		library.TemplatesExt,
+		library.TraitModifiersExt,
+		library.TraitsExt,
+		library.VehiclesExt,
+	}, library.GCSSecondaryExtensions()...)
+	for _, ext := range exts {
+		p := filepath.Join(dir, "empty"+ext)
+		assert.NoError(t, os.WriteFile(p, []byte("[]"), 0o640))
+		_, err := schema.Validate(p)
+		assert.NoError(t, err, ext)
+	}
+}
+
+func TestEveryFeatureAndPrereqTypeHasSchema(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := schema.WriteAll(dir); !assert.NoError(t, err) {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(dir, strings.TrimPrefix(library.TraitsExt, ".")+schema.Ext))
+	if !assert.NoError(t, err) {
+		return
+	}
+	var root any
+	assert.NoError(t, json.Unmarshal(data, &root))
+	keys := make(map[string]bool)
+	collectVariantKeys(root, keys)
+	for _, one := range feature.AllType {
+		assert.True(t, keys[one.Key()], one.Key())
+	}
+	for _, one := range prereq.AllType {
+		assert.True(t, keys[one.Key()], one.Key())
+	}
+}
+
+func TestValidateFiles(t *testing.T) {
+	dir := t.TempDir()
+	textPath := filepath.Join(dir, "notes.txt")
+	assert.NoError(t, os.WriteFile(textPath, []byte("not a data file"), 0o640))
+	trait := gurps.NewTrait(nil, nil, false)
+	trait.Name = "Fit"
+	traitsPath := filepath.Join(dir, "Traits"+library.TraitsExt)
+	assert.NoError(t, gurps.SaveTraits([]*gurps.Trait{trait}, traitsPath))
+
+	// Files without a schema are skipped when found within a directory...
+	failed, err := schema.ValidateFiles(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, failed)
+
+	// ...but are a problem when named directly, without stopping the remaining files from being validated
+	failed, err = schema.ValidateFiles(textPath, traitsPath)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, failed)
+}
+
+func fileNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+// collectVariantKeys gathers the values permitted for the "type" property of the branches of each oneOf within the
+// schema.
+func collectVariantKeys(value any, keys map[string]bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		if choices, ok := v["oneOf"].([]any); ok {
+			for _, choice := range choices {
+				c, _ := choice.(map[string]any)
+				properties, _ := c["properties"].(map[string]any)
+				typeProperty, _ := properties["type"].(map[string]any)
+				enum, _ := typeProperty["enum"].([]any)
+				for _, one := range enum {
+					if key, isString := one.(string); isString {
+						keys[key] = true
+					}
+				}
+			}
+		}
+		for _, one := range v {
+			collectVariantKeys(one, keys)
+		}
+	case []any:
+		for _, one := range v {
+			collectVariantKeys(one, keys)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/richardwilkes/json"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// node holds a JSON value along with the byte offset at which it starts, so that problems can be reported by line.
+type node struct {
+	kind    string
+	offset  int
+	members []*member
+	items   []*node
+	value   any
+}
+
+type member struct {
+	name   string
+	offset int
+	value  *node
+}
+
+// find returns the value of the object member with the given name, or nil.
+func (n *node) find(name string) *node {
+	for _, m := range n.members {
+		if m.name == name {
+			return m.value
+		}
+	}
+	return nil
+}
+
+type parser struct {
+	data []byte
+	dec  *json.Decoder
+}
+
+// syntaxError is returned when the data isn't well-formed JSON.
+type syntaxError struct {
+	offset int
+	err    error
+}
+
+func (e *syntaxError) Error() string {
+	return e.err.Error()
+}
+
+func parse(data []byte) (*node, error) {
+	p := &parser{
+		data: data,
+		dec:  json.NewDecoder(bytes.NewReader(data)),
+	}
+	p.dec.UseNumber()
+	root, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	offset := p.next()
+	if _, err = p.dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, &syntaxError{offset: offset, err: errs.New(i18n.Text("unexpected data after the end of the JSON"))}
+	}
+	return root, nil
+}
+
+// next returns the offset of the start of the next token.
+func (p *parser) next() int {
+	offset := int(p.dec.InputOffset())
+	for offset < len(p.data) {
+		switch p.data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+func (p *parser) token() (json.Token, int, error) {
+	offset := p.next()
+	tok, err := p.dec.Token()
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			offset = int(syntaxErr.Offset)
+		}
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, offset, &syntaxError{offset: offset, err: err}
+	}
+	return tok, offset, nil
+}
+
+func (p *parser) value() (*node, error) {
+	tok, offset, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	n := &node{offset: offset, value: tok}
+	switch t := tok.(type) {
+	case json.Delim:
+		n.value = nil
+		switch t {
+		case '{':
+			n.kind = "object"
+			for p.dec.More() {
+				var key json.Token
+				if key, offset, err = p.token(); err != nil {
+					return nil, err
+				}
+				name, _ := key.(string)
+				var v *node
+				if v, err = p.value(); err != nil {
+					return nil, err
+				}
+				n.members = append(n.members, &member{name: name, offset: offset, value: v})
+			}
+		case '[':
+			n.kind = "array"
+			for p.dec.More() {
+				var v *node
+				if v, err = p.value(); err != nil {
+					return nil, err
+				}
+				n.items = append(n.items, v)
+			}
+		default:
+			return nil, &syntaxError{offset: offset, err: errs.Newf(i18n.Text("unexpected %v"), t)}
+		}
+		if _, _, err = p.token(); err != nil { // The closing delimiter
+			return nil, err
+		}
+	case string:
+		n.kind = "string"
+	case json.Number:
+		n.kind = "number"
+		if !strings.ContainsAny(t.String(), ".eE") {
+			n.kind = "integer"
+		}
+	case bool:
+		n.kind = "boolean"
+	default:
+		n.kind = "null"
+	}
+	return n, nil
+}
+
+// position returns the 1-based line and column of the offset within the data.
+func position(data []byte, offset int) (line, column int) {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	return 1 + bytes.Count(data[:offset], []byte{'\n'}), offset - bytes.LastIndexByte(data[:offset], '\n')
+}
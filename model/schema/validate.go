@@ -0,0 +1,331 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/richardwilkes/gcs/v5/model/gurps/gid"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/txt"
+	"github.com/richardwilkes/toolbox/xio/fs"
+)
+
+// Issue describes a problem found within a file.
+type Issue struct {
+	// Line and Column are 1-based. Both are zero when the problem isn't tied to a particular location.
+	Line   int
+	Column int
+	// Path locates the value within the data, such as rows[3].name. Empty for the top level.
+	Path    string
+	Message string
+}
+
+// Location returns the file path, followed by the line and column, if known.
+func (i *Issue) Location(filePath string) string {
+	if i.Line == 0 {
+		return filePath
+	}
+	return fmt.Sprintf("%s:%d:%d", filePath, i.Line, i.Column)
+}
+
+func (i *Issue) String() string {
+	if i.Path == "" {
+		return i.Message
+	}
+	return i.Path + ": " + i.Message
+}
+
+type validator struct {
+	root   *Schema
+	data   []byte
+	issues []*Issue
+}
+
+// Validate checks the file against the schema for its type and for problems that a schema can't express, such as
+// duplicate IDs and unsupported versions, then loads it the same way GCS does to catch anything else.
+func Validate(filePath string) ([]*Issue, error) {
+	ft := fileTypeFor(filepath.Ext(filePath))
+	if ft == nil {
+		return nil, errs.Newf(i18n.Text("no schema is available for %s"), filePath)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	v := &validator{
+		root: ft.schema(),
+		data: data,
+	}
+	var root *node
+	if root, err = parse(data); err != nil {
+		var syntaxErr *syntaxError
+		if !errors.As(err, &syntaxErr) {
+			return nil, err
+		}
+		v.add(syntaxErr.offset, "", syntaxErr.Error())
+		return v.issues, nil
+	}
+	v.check(root, v.root, "")
+	v.checkVersion(root)
+	v.checkDuplicates(root, "", make(map[string]int))
+	if err = ft.load(os.DirFS(filepath.Dir(filePath)), filepath.Base(filePath)); err != nil {
+		v.issues = append(v.issues, &Issue{Message: fmt.Sprintf(i18n.Text("unable to load: %s"), err.Error())})
+	}
+	return v.issues, nil
+}
+
+// ValidateFiles validates the files, printing the problems found in each one. Directories are traversed recursively,
+// validating the files within them that have a schema. A file that was named directly but can't be validated, such as
+// one without a schema, is reported as a problem. Returns the number of files with problems.
+func ValidateFiles(paths ...string) (int, error) {
+	list, err := collectFiles(paths)
+	if err != nil {
+		return 0, err
+	}
+	failed := 0
+	for _, p := range list {
+		var issues []*Issue
+		if issues, err = Validate(p); err != nil {
+			failed++
+			fmt.Printf("%s: %s\n", p, err.Error())
+			continue
+		}
+		if len(issues) != 0 {
+			failed++
+		}
+		for _, issue := range issues {
+			fmt.Printf("%s: %s\n", issue.Location(p), issue)
+		}
+	}
+	fmt.Printf(i18n.Text("%d of %d files had problems\n"), failed, len(list))
+	return failed, nil
+}
+
+func collectFiles(paths []string) ([]string, error) {
+	paths, err := fs.UniquePaths(paths...)
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	for _, p := range paths {
+		if err = filepath.WalkDir(p, func(path string, d iofs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if d.IsDir() {
+				if path != p && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if path == p || fileTypeFor(filepath.Ext(path)) != nil {
+				list = append(list, path)
+			}
+			return nil
+		}); err != nil {
+			return nil, errs.Wrap(err)
+		}
+	}
+	txt.SortStringsNaturalAscending(list)
+	return list, nil
+}
+
+func (v *validator) add(offset int, path, format string, args ...any) {
+	issue := &Issue{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	}
+	issue.Line, issue.Column = position(v.data, offset)
+	v.issues = append(v.issues, issue)
+}
+
+func (v *validator) resolve(s *Schema) *Schema {
+	for s.Ref != "" {
+		def, exists := v.root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !exists {
+			return &Schema{}
+		}
+		s = def
+	}
+	return s
+}
+
+func (v *validator) check(n *node, s *Schema, path string) {
+	if s.Ref != "" {
+		v.check(n, v.resolve(s), path)
+	}
+	if len(s.AnyOf) != 0 {
+		v.checkAnyOf(n, s.AnyOf, path)
+	}
+	if len(s.OneOf) != 0 {
+		v.checkOneOf(n, s.OneOf, path)
+	}
+	if !s.Allows(n.kind) {
+		v.add(n.offset, path, i18n.Text("expected %s, but found %s"), strings.Join(s.Type, " or "), n.kind)
+		return
+	}
+	if n.kind != "null" {
+		if s.Const != nil && n.value != s.Const {
+			v.add(n.offset, path, i18n.Text("expected %q, but found %q"), s.Const, n.value)
+		}
+		if len(s.Enum) != 0 && !contains(s.Enum, n.value) {
+			v.add(n.offset, path, i18n.Text("unknown value %q"), n.value)
+		}
+	}
+	switch n.kind {
+	case "object":
+		seen := make(map[string]bool, len(n.members))
+		for _, m := range n.members {
+			seen[m.name] = true
+			ps, exists := s.Properties[m.name]
+			if !exists {
+				switch additional := s.AdditionalProperties.(type) {
+				case *Schema:
+					ps = additional
+				case bool:
+					if !additional {
+						v.add(m.offset, path, i18n.Text("unknown property %q"), m.name)
+					}
+				}
+			}
+			if ps != nil {
+				v.check(m.value, ps, joinPath(path, m.name))
+			}
+		}
+		for _, name := range s.Required {
+			if !seen[name] {
+				v.add(n.offset, path, i18n.Text("missing required property %q"), name)
+			}
+		}
+	case "array":
+		if s.Items != nil {
+			for i, item := range n.items {
+				v.check(item, s.Items, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	}
+}
+
+// checkAnyOf accepts the value if any of the choices accepts it. Otherwise, the problems found with the first choice
+// that allows the value's JSON type are reported.
+func (v *validator) checkAnyOf(n *node, choices []*Schema, path string) {
+	var first, best []*Issue
+	for i, choice := range choices {
+		trial := &validator{root: v.root, data: v.data}
+		trial.check(n, choice, path)
+		if len(trial.issues) == 0 {
+			return
+		}
+		if i == 0 {
+			first = trial.issues
+		}
+		if best == nil && v.resolve(choice).Allows(n.kind) {
+			best = trial.issues
+		}
+	}
+	if best == nil {
+		best = first
+	}
+	v.issues = append(v.issues, best...)
+}
+
+// checkOneOf checks the value against the choice selected by its "type" property.
+func (v *validator) checkOneOf(n *node, choices []*Schema, path string) {
+	if n.kind != "object" {
+		v.add(n.offset, path, i18n.Text("expected object, but found %s"), n.kind)
+		return
+	}
+	typeNode := n.find("type")
+	if typeNode == nil {
+		v.add(n.offset, path, i18n.Text("missing required property %q"), "type")
+		return
+	}
+	for _, choice := range choices {
+		if ts, exists := choice.Properties["type"]; exists && contains(ts.Enum, typeNode.value) {
+			v.check(n, choice, path)
+			return
+		}
+	}
+	v.add(typeNode.offset, joinPath(path, "type"), i18n.Text("unknown value %q"), typeNode.value)
+}
+
+// checkVersion verifies that the version in the file's header is one that GCS can load.
+func (v *validator) checkVersion(root *node) {
+	if root.kind != "object" {
+		return
+	}
+	if n := root.find("version"); n != nil && n.kind == "integer" {
+		if version, err := strconv.Atoi(fmt.Sprint(n.value)); err == nil &&
+			(version < gid.MinimumDataVersion || version > gid.CurrentDataVersion) {
+			v.add(n.offset, "version", i18n.Text("version %d is not supported; only versions %d through %d can be loaded"),
+				version, gid.MinimumDataVersion, gid.CurrentDataVersion)
+		}
+	}
+}
+
+// checkDuplicates verifies that no object repeats a property and that no two objects within the file share the same
+// UUID.
+func (v *validator) checkDuplicates(n *node, path string, seen map[string]int) {
+	switch n.kind {
+	case "object":
+		names := make(map[string]bool, len(n.members))
+		for _, m := range n.members {
+			if names[m.name] {
+				v.add(m.offset, path, i18n.Text("duplicate property %q"), m.name)
+			}
+			names[m.name] = true
+		}
+		if idNode := n.find("id"); idNode != nil && idNode.kind == "string" {
+			if id, err := uuid.Parse(fmt.Sprint(idNode.value)); err == nil {
+				key := id.String()
+				if prior, exists := seen[key]; exists {
+					line, _ := position(v.data, prior)
+					v.add(idNode.offset, joinPath(path, "id"), i18n.Text("duplicate ID %s, first used on line %d"), key, line)
+				} else {
+					seen[key] = idNode.offset
+				}
+			}
+		}
+		for _, m := range n.members {
+			v.checkDuplicates(m.value, joinPath(path, m.name), seen)
+		}
+	case "array":
+		for i, item := range n.items {
+			v.checkDuplicates(item, fmt.Sprintf("%s[%d]", path, i), seen)
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func contains(list []any, value any) bool {
+	for _, one := range list {
+		if one == value {
+			return true
+		}
+	}
+	return false
+}
@@ -15,6 +15,7 @@ import (
 	"context"
 	"io/fs"
 	"sort"
+	"sync/atomic"
 
 	"github.com/richardwilkes/gcs/v5/model/jio"
 	"github.com/richardwilkes/json"
@@ -23,20 +24,30 @@ import (
 	xfs "github.com/richardwilkes/toolbox/xio/fs"
 )
 
-var _ json.Omitter = &PageRefs{}
+var (
+	_                  json.Omitter = &PageRefs{}
+	pageRefsGeneration atomic.Uint64
+)
 
 const oldPageRefsKey = "page_refs"
 
 // PageRefs holds a set of page references.
 type PageRefs struct {
-	data map[string]*PageRef
+	data       map[string]*PageRef
+	generation uint64
 }
 
-// PageRef holds a path to a file and an offset for all page references within that file.
+// PageRef holds a path to a file and an offset for all page references within that file, along with any bookmarks and
+// highlights that have been made within it. Books that have been split across several files may map ranges of pages to
+// other files, and pages with non-numeric labels, such as roman-numeral front matter, may be mapped explicitly.
 type PageRef struct {
-	ID     string `json:"-"`
-	Path   string `json:"path,omitempty"`
-	Offset int    `json:"offset,omitempty"`
+	ID         string          `json:"-"`
+	Path       string          `json:"path,omitempty"`
+	Offset     int             `json:"offset,omitempty"`
+	Ranges     []*PageRange    `json:"ranges,omitempty"`
+	Labels     []*PageLabel    `json:"labels,omitempty"`
+	Bookmarks  []*PDFBookmark  `json:"bookmarks,omitempty"`
+	Highlights []*PDFHighlight `json:"highlights,omitempty"`
 }
 
 // NewPageRefsFromFS creates a new set of page references from a file.
@@ -84,6 +95,7 @@ func (p *PageRefs) UnmarshalJSON(data []byte) error {
 	for k, v := range p.data {
 		v.ID = k
 	}
+	p.generation = pageRefsGeneration.Add(1)
 	return nil
 }
 
@@ -91,6 +103,8 @@ func (p *PageRefs) UnmarshalJSON(data []byte) error {
 func (p *PageRefs) Lookup(id string) *PageRef {
 	if ref, ok := p.data[id]; ok && xfs.FileIsReadable(ref.Path) {
 		r := *ref // Make a copy so that clients can't muck with our data
+		r.cloneTargets()
+		r.cloneAnnotations()
 		return &r
 	}
 	return nil
@@ -106,21 +120,34 @@ func (p *PageRefs) Set(pageRef *PageRef) {
 		p.data = make(map[string]*PageRef)
 	}
 	r := *pageRef
+	r.cloneTargets()
+	r.cloneAnnotations()
 	p.data[pageRef.ID] = &r
+	p.generation = pageRefsGeneration.Add(1)
 }
 
 // Remove the PageRef for the ID.
 func (p *PageRefs) Remove(id string) {
 	if p.data != nil {
 		delete(p.data, id)
+		p.generation = pageRefsGeneration.Add(1)
 	}
 }
 
+// Generation returns a value that changes each time the page references are modified, allowing clients to tell when
+// information they have derived from them is stale. Generations are unique across all sets of page references, so a
+// set that replaces another will not be mistaken for it once modified.
+func (p *PageRefs) Generation() uint64 {
+	return p.generation
+}
+
 // List returns a sorted list of page references.
 func (p *PageRefs) List() []*PageRef {
 	list := make([]*PageRef, 0, len(p.data))
 	for _, v := range p.data {
 		r := *v
+		r.cloneTargets()
+		r.cloneAnnotations()
 		list = append(list, &r)
 	}
 	sort.Slice(list, func(i, j int) bool { return txt.NaturalLess(list[i].ID, list[j].ID, true) })
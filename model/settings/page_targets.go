@@ -0,0 +1,161 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package settings
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PageRange maps a range of printed page numbers to a file other than the primary one for its PageRef. This allows a
+// book that has been split across several PDFs to be handled by a single page reference key. The offset is applied in
+// the same way as PageRef.Offset, so a file that starts with printed page 101 would typically use an offset of -100.
+type PageRange struct {
+	First  int    `json:"first"`
+	Last   int    `json:"last"`
+	Path   string `json:"path"`
+	Offset int    `json:"offset,omitempty"`
+}
+
+// Contains returns true if the printed page number falls within the range.
+func (r *PageRange) Contains(page int) bool {
+	return page >= r.First && page <= r.Last
+}
+
+// PageLabel maps a non-numeric page label, such as the roman numeral "xii", to a specific page within a file. If Path is
+// empty, the primary file of the PageRef is used. The page number is the 0-based page within the PDF file.
+type PageLabel struct {
+	Label      string `json:"label"`
+	Path       string `json:"path,omitempty"`
+	PageNumber int    `json:"page"`
+}
+
+// PageTarget identifies a specific page within a specific file.
+type PageTarget struct {
+	Path       string
+	PageNumber int // 0-based page number within the PDF
+}
+
+// ResolvePage returns the file and 0-based page within it for the page label, which is either a printed page number or
+// one of the labels that have been mapped explicitly. Labels are matched without regard to case.
+func (p *PageRef) ResolvePage(label string) (PageTarget, bool) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return PageTarget{}, false
+	}
+	for _, one := range p.Labels {
+		if strings.EqualFold(one.Label, label) {
+			return PageTarget{
+				Path:       p.resolvePath(one.Path),
+				PageNumber: one.PageNumber,
+			}, true
+		}
+	}
+	page, err := strconv.Atoi(label)
+	if err != nil {
+		return PageTarget{}, false
+	}
+	for _, one := range p.Ranges {
+		if one.Contains(page) {
+			return PageTarget{
+				Path:       p.resolvePath(one.Path),
+				PageNumber: page + one.Offset - 1, // The pdf package uses 0 for the first page, not 1
+			}, true
+		}
+	}
+	return PageTarget{
+		Path:       p.Path,
+		PageNumber: page + p.Offset - 1,
+	}, true
+}
+
+// Paths returns the distinct paths of all files this PageRef refers to, starting with the primary one.
+func (p *PageRef) Paths() []string {
+	paths := []string{p.Path}
+	for _, one := range p.Ranges {
+		paths = appendDistinctPath(paths, one.Path)
+	}
+	for _, one := range p.Labels {
+		paths = appendDistinctPath(paths, one.Path)
+	}
+	return paths
+}
+
+// RefersTo returns true if the file is one of the files this PageRef refers to.
+func (p *PageRef) RefersTo(filePath string) bool {
+	filePath = filepath.Clean(filePath)
+	for _, one := range p.Paths() {
+		if filepath.Clean(one) == filePath {
+			return true
+		}
+	}
+	return false
+}
+
+// OffsetFor returns the offset that applies to printed page numbers within the file. The file is expected to be one of
+// the files this PageRef refers to.
+func (p *PageRef) OffsetFor(filePath string) int {
+	filePath = filepath.Clean(filePath)
+	if filepath.Clean(p.Path) != filePath {
+		for _, one := range p.Ranges {
+			if filepath.Clean(one.Path) == filePath {
+				return one.Offset
+			}
+		}
+	}
+	return p.Offset
+}
+
+func (p *PageRef) resolvePath(filePath string) string {
+	if filePath == "" {
+		return p.Path
+	}
+	return filePath
+}
+
+func appendDistinctPath(paths []string, filePath string) []string {
+	if filePath == "" {
+		return paths
+	}
+	for _, one := range paths {
+		if one == filePath {
+			return paths
+		}
+	}
+	return append(paths, filePath)
+}
+
+func (p *PageRef) cloneTargets() {
+	if p.Ranges != nil {
+		list := make([]*PageRange, len(p.Ranges))
+		for i, one := range p.Ranges {
+			r := *one
+			list[i] = &r
+		}
+		p.Ranges = list
+	}
+	if p.Labels != nil {
+		list := make([]*PageLabel, len(p.Labels))
+		for i, one := range p.Labels {
+			l := *one
+			list[i] = &l
+		}
+		p.Labels = list
+	}
+}
+
+// Has returns true if a mapping exists for the ID, regardless of whether the file it points to is readable.
+func (p *PageRefs) Has(id string) bool {
+	_, ok := p.data[id]
+	return ok
+}
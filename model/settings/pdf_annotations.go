@@ -0,0 +1,132 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package settings
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/richardwilkes/unison"
+)
+
+// PageRefBookmarkSeparator separates the key from the bookmark name in a page reference that targets a bookmark, e.g.
+// "B:Grappling".
+const PageRefBookmarkSeparator = ":"
+
+// PDFBookmark holds a named location within a PDF. The page number is the 0-based page within the PDF file, rather than
+// the page number printed in the book, so that it remains correct regardless of the page reference offset. If Path is
+// empty, the primary file of the PageRef is used.
+type PDFBookmark struct {
+	Name       string `json:"name"`
+	Path       string `json:"path,omitempty"`
+	PageNumber int    `json:"page"`
+}
+
+// Target returns the file and page the bookmark refers to.
+func (b *PDFBookmark) Target(p *PageRef) PageTarget {
+	return PageTarget{
+		Path:       p.resolvePath(b.Path),
+		PageNumber: b.PageNumber,
+	}
+}
+
+// PDFHighlight holds a highlighted area within a page of a PDF. The area is expressed in page coordinates at 100% scale.
+// If Path is empty, the primary file of the PageRef is used.
+type PDFHighlight struct {
+	Path       string    `json:"path,omitempty"`
+	PageNumber int       `json:"page"`
+	Text       string    `json:"text,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	Areas      []PDFArea `json:"areas"`
+}
+
+// PDFArea holds a rectangular area within a PDF page.
+type PDFArea struct {
+	X      float32 `json:"x"`
+	Y      float32 `json:"y"`
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+}
+
+// NewPDFArea creates a new PDFArea from a rectangle at the given scale.
+func NewPDFArea(r unison.Rect, scale float32) PDFArea {
+	return PDFArea{
+		X:      r.X / scale,
+		Y:      r.Y / scale,
+		Width:  r.Width / scale,
+		Height: r.Height / scale,
+	}
+}
+
+// Rect returns the area as a rectangle at the given scale.
+func (a PDFArea) Rect(scale float32) unison.Rect {
+	return unison.NewRect(a.X*scale, a.Y*scale, a.Width*scale, a.Height*scale)
+}
+
+// NormalizeBookmarkName normalizes a bookmark name for comparison purposes. Case, spaces and underscores are ignored, so
+// that a bookmark named "All-Out Attack" may be referenced as "B:all-out_attack".
+func NormalizeBookmarkName(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "").Replace(name))
+}
+
+// Bookmark returns the bookmark with the given name, or nil.
+func (p *PageRef) Bookmark(name string) *PDFBookmark {
+	name = NormalizeBookmarkName(name)
+	for _, one := range p.Bookmarks {
+		if NormalizeBookmarkName(one.Name) == name {
+			return one
+		}
+	}
+	return nil
+}
+
+// HighlightsForPage returns the highlights on the given 0-based page of the file.
+func (p *PageRef) HighlightsForPage(filePath string, pageNumber int) []*PDFHighlight {
+	filePath = filepath.Clean(filePath)
+	var list []*PDFHighlight
+	for _, one := range p.Highlights {
+		if one.PageNumber == pageNumber && filepath.Clean(p.resolvePath(one.Path)) == filePath {
+			list = append(list, one)
+		}
+	}
+	return list
+}
+
+func (p *PageRef) cloneAnnotations() {
+	if p.Bookmarks != nil {
+		list := make([]*PDFBookmark, len(p.Bookmarks))
+		for i, one := range p.Bookmarks {
+			b := *one
+			list[i] = &b
+		}
+		p.Bookmarks = list
+	}
+	if p.Highlights != nil {
+		list := make([]*PDFHighlight, len(p.Highlights))
+		for i, one := range p.Highlights {
+			h := *one
+			h.Areas = append([]PDFArea(nil), one.Areas...)
+			list[i] = &h
+		}
+		p.Highlights = list
+	}
+}
+
+// LookupByPath returns the PageRef that refers to the given file, or nil.
+func (p *PageRefs) LookupByPath(filePath string) *PageRef {
+	for _, one := range p.List() {
+		if one.RefersTo(filePath) {
+			return one
+		}
+	}
+	return nil
+}
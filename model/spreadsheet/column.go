@@ -0,0 +1,216 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package spreadsheet
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/measure"
+	"github.com/richardwilkes/gcs/v5/model/gurps/skill"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// needsTechLevel is written for items that require a tech level but don't have one filled in yet, to distinguish them
+// from items that have no tech level at all.
+const needsTechLevel = "?"
+
+// column describes how a single spreadsheet column maps onto a field of T. The first name is the one written on
+// export; all of them are accepted as headers on import.
+type column[T gurps.NodeTypes] struct {
+	names []string
+	get   func(T) string
+	set   func(T, string) error
+}
+
+func (c *column[T]) matches(header string) bool {
+	header = strings.TrimSpace(header)
+	for _, name := range c.names {
+		if strings.EqualFold(name, header) {
+			return true
+		}
+	}
+	return false
+}
+
+func textColumn[T gurps.NodeTypes](field func(T) *string, names ...string) *column[T] {
+	return &column[T]{
+		names: names,
+		get:   func(node T) string { return *field(node) },
+		set: func(node T, value string) error {
+			*field(node) = value
+			return nil
+		},
+	}
+}
+
+func techLevelColumn[T gurps.NodeTypes](field func(T) **string) *column[T] {
+	return &column[T]{
+		names: []string{"TechLevel", "TL"},
+		get: func(node T) string {
+			tl := *field(node)
+			switch {
+			case tl == nil:
+				return ""
+			case *tl == "":
+				return needsTechLevel
+			default:
+				return *tl
+			}
+		},
+		set: func(node T, value string) error {
+			switch value {
+			case "":
+				*field(node) = nil
+			case needsTechLevel:
+				tl := ""
+				*field(node) = &tl
+			default:
+				*field(node) = &value
+			}
+			return nil
+		},
+	}
+}
+
+// numberColumn maps onto a numeric field. An empty cell is treated as the provided default.
+func numberColumn[T gurps.NodeTypes](field func(T) *fxp.Int, def fxp.Int, names ...string) *column[T] {
+	return &column[T]{
+		names: names,
+		get:   func(node T) string { return field(node).String() },
+		set: func(node T, value string) error {
+			if value == "" {
+				*field(node) = def
+				return nil
+			}
+			v, err := parseNumber(value)
+			if err != nil {
+				return err
+			}
+			*field(node) = v
+			return nil
+		},
+	}
+}
+
+func parseNumber(value string) (fxp.Int, error) {
+	v, err := fxp.FromString(strings.TrimPrefix(strings.ReplaceAll(value, ",", ""), "+"))
+	if err != nil {
+		return 0, errs.NewWithCause(i18n.Text("invalid number"), err)
+	}
+	return v, nil
+}
+
+func integerColumn[T gurps.NodeTypes](field func(T) *int, names ...string) *column[T] {
+	return &column[T]{
+		names: names,
+		get:   func(node T) string { return strconv.Itoa(*field(node)) },
+		set: func(node T, value string) error {
+			if value == "" {
+				*field(node) = 0
+				return nil
+			}
+			v, err := strconv.Atoi(strings.TrimPrefix(value, "+"))
+			if err != nil {
+				return errs.NewWithCause(i18n.Text("invalid integer"), err)
+			}
+			*field(node) = v
+			return nil
+		},
+	}
+}
+
+func boolColumn[T gurps.NodeTypes](field func(T) *bool, names ...string) *column[T] {
+	return &column[T]{
+		names: names,
+		get: func(node T) string {
+			if *field(node) {
+				return "yes"
+			}
+			return ""
+		},
+		set: func(node T, value string) error {
+			*field(node) = isTrue(value)
+			return nil
+		},
+	}
+}
+
+func weightColumn[T gurps.NodeTypes](field func(T) *measure.Weight) *column[T] {
+	return &column[T]{
+		names: []string{"Weight", "Wt"},
+		get:   func(node T) string { return field(node).String() },
+		set: func(node T, value string) error {
+			if value == "" {
+				*field(node) = 0
+				return nil
+			}
+			w, err := measure.WeightFromString(value, measure.Pound)
+			if err != nil {
+				return errs.NewWithCause(i18n.Text("invalid weight"), err)
+			}
+			*field(node) = w
+			return nil
+		},
+	}
+}
+
+func tagsColumn[T gurps.NodeTypes](field func(T) *[]string) *column[T] {
+	return &column[T]{
+		names: []string{"Tags", "Categories"},
+		get:   func(node T) string { return gurps.CombineTags(*field(node)) },
+		set: func(node T, value string) error {
+			*field(node) = gurps.ExtractTags(value)
+			return nil
+		},
+	}
+}
+
+func difficultyColumn[T gurps.NodeTypes](field func(T) *gurps.AttributeDifficulty) *column[T] {
+	return &column[T]{
+		names: []string{"Difficulty", "Diff"},
+		get:   func(node T) string { return field(node).Key() },
+		set: func(node T, value string) error {
+			d := field(node)
+			if parts := strings.SplitN(value, "/", 2); len(parts) == 1 {
+				d.Attribute = ""
+				d.Difficulty = skill.ExtractDifficulty(strings.TrimSpace(parts[0]))
+			} else {
+				d.Attribute = strings.TrimSpace(parts[0])
+				d.Difficulty = skill.ExtractDifficulty(strings.TrimSpace(parts[1]))
+			}
+			return nil
+		},
+	}
+}
+
+// commonColumns returns the columns shared by all of the list types.
+func commonColumns[T gurps.NodeTypes](pageRef, notes, vttNotes func(T) *string, tags func(T) *[]string) []*column[T] {
+	return []*column[T]{
+		tagsColumn(tags),
+		textColumn(pageRef, "PageRef", "Reference", "Ref", "Page"),
+		textColumn(notes, "Notes"),
+		textColumn(vttNotes, "VTTNotes", "VTT Notes"),
+	}
+}
+
+func isTrue(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "y", "true", "t", "x", "1":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xio"
+)
+
+// Spreadsheet file extensions.
+const (
+	CSVExt = ".csv"
+	TSVExt = ".tsv"
+)
+
+// CanConvert returns true if the list file at the path can be converted to and from a spreadsheet.
+func CanConvert(listPath string) bool {
+	return tableFor(filepath.Ext(listPath)) != nil
+}
+
+// Export the list file to a spreadsheet. The format is chosen by the spreadsheet's extension. Each item occupies one
+// row, with the names of its enclosing containers, separated by ParentSeparator, in the Parent column.
+func Export(listPath, sheetPath string) (err error) {
+	t := tableFor(filepath.Ext(listPath))
+	if t == nil {
+		return errs.Newf(i18n.Text("%s cannot be exported to a spreadsheet"), listPath)
+	}
+	var f *os.File
+	if f, err = os.Create(sheetPath); err != nil {
+		return errs.Wrap(err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = errs.Wrap(closeErr)
+		}
+	}()
+	w := csv.NewWriter(f)
+	if isTSV(sheetPath) {
+		w.Comma = '\t'
+	}
+	return t.export(listPath, w)
+}
+
+// Import the spreadsheet into the list file. The format is chosen by the spreadsheet's extension and the type of list
+// by the list file's extension. If the list file already exists, items whose IDs match rows of the spreadsheet are
+// updated in place. Items without a matching row are kept under their nearest remaining container, or dropped if
+// replace is true.
+func Import(sheetPath, listPath string, replace bool) error {
+	t := tableFor(filepath.Ext(listPath))
+	if t == nil {
+		return errs.Newf(i18n.Text("%s cannot be imported from a spreadsheet"), listPath)
+	}
+	f, err := os.Open(sheetPath)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer xio.CloseIgnoringErrors(f)
+	r := csv.NewReader(f)
+	if isTSV(sheetPath) {
+		r.Comma = '\t'
+	}
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	var records [][]string
+	if records, err = r.ReadAll(); err != nil {
+		return errs.NewWithCause(sheetPath, err)
+	}
+	if err = t.importRows(records, listPath, replace); err != nil {
+		return errs.NewWithCause(sheetPath, err)
+	}
+	return nil
+}
+
+// ExportFiles exports each of the list files to a spreadsheet alongside it, named by appending the spreadsheet
+// extension to the list file's name.
+func ExportFiles(ext string, paths ...string) error {
+	for _, p := range paths {
+		fmt.Printf(i18n.Text("Processing %s\n"), p)
+		if err := Export(p, p+ext); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportFiles imports each of the spreadsheets into the list file named by removing the spreadsheet extension from
+// its name, reversing ExportFiles. See Import for the meaning of replace.
+func ImportFiles(replace bool, paths ...string) error {
+	for _, p := range paths {
+		fmt.Printf(i18n.Text("Processing %s\n"), p)
+		listPath := strings.TrimSuffix(p, filepath.Ext(p))
+		if !CanConvert(listPath) {
+			return errs.Newf(i18n.Text("%s must be named after the list file it will produce, e.g. Gear.eqp.csv"), p)
+		}
+		if err := Import(p, listPath, replace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isTSV(sheetPath string) bool {
+	return strings.EqualFold(filepath.Ext(sheetPath), TSVExt)
+}
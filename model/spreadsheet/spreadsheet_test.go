@@ -0,0 +1,226 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package spreadsheet_test
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/gid"
+	"github.com/richardwilkes/gcs/v5/model/gurps/settings"
+	"github.com/richardwilkes/gcs/v5/model/gurps/skill"
+	"github.com/richardwilkes/gcs/v5/model/gurps/trait"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/gcs/v5/model/spreadsheet"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSettings struct {
+	general *settings.General
+	sheet   *gurps.SheetSettings
+}
+
+func (s *testSettings) GeneralSettings() *settings.General {
+	return s.general
+}
+
+func (s *testSettings) SheetSettings() *gurps.SheetSettings {
+	return s.sheet
+}
+
+func (s *testSettings) Libraries() library.Libraries {
+	return nil
+}
+
+func TestMain(m *testing.M) {
+	gurps.SettingsProvider = &testSettings{
+		general: settings.NewGeneral(),
+		sheet:   gurps.FactorySheetSettings(),
+	}
+	os.Exit(m.Run())
+}
+
+func TestRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	combat := gurps.NewSkill(nil, nil, true)
+	combat.Name = "Combat"
+	karate := gurps.NewSkill(nil, combat, false)
+	karate.Name = "Karate"
+	karate.Difficulty.Difficulty = skill.Hard
+	feint := gurps.NewTechnique(nil, combat, "Karate")
+	feint.Name = "Feint"
+	feint.TechniqueDefault.Modifier = -fxp.One
+	limit := fxp.Two
+	feint.TechniqueLimitModifier = &limit
+	combat.Children = []*gurps.Skill{karate, feint}
+	skillsPath := filepath.Join(dir, "Skills"+library.SkillsExt)
+	assert.NoError(t, gurps.SaveSkills([]*gurps.Skill{combat}, skillsPath))
+
+	temper := gurps.NewTrait(nil, nil, false)
+	temper.Name = "Bad Temper"
+	temper.BasePoints = fxp.From(-10)
+	temper.CR = trait.CR12
+	temper.CRAdj = gurps.ReactionPenalty
+	temper.Tags = []string{"Disadvantage", "Mental"}
+	traitsPath := filepath.Join(dir, "Traits"+library.TraitsExt)
+	assert.NoError(t, gurps.SaveTraits([]*gurps.Trait{temper}, traitsPath))
+
+	bag := gurps.NewEquipment(nil, nil, true)
+	bag.Name = "Backpack"
+	bag.Value = fxp.From(60)
+	rope := gurps.NewEquipment(nil, bag, false)
+	rope.Name = "Rope"
+	rope.Quantity = fxp.Two
+	bag.Children = []*gurps.Equipment{rope}
+	equipmentPath := filepath.Join(dir, "Gear"+library.EquipmentExt)
+	assert.NoError(t, gurps.SaveEquipment([]*gurps.Equipment{bag}, equipmentPath))
+
+	for _, listPath := range []string{skillsPath, traitsPath, equipmentPath} {
+		t.Run(filepath.Base(listPath), func(t *testing.T) {
+			sheetPath := listPath + spreadsheet.CSVExt
+			assert.NoError(t, spreadsheet.Export(listPath, sheetPath))
+			copyPath := filepath.Join(dir, "Copy"+filepath.Ext(listPath))
+			assert.NoError(t, spreadsheet.Import(sheetPath, copyPath, false))
+			copySheetPath := copyPath + spreadsheet.CSVExt
+			assert.NoError(t, spreadsheet.Export(copyPath, copySheetPath))
+			// The copy was imported into a new list file, so its items have new IDs
+			assert.Equal(t, withoutIDs(t, sheetPath), withoutIDs(t, copySheetPath))
+		})
+	}
+
+	skills, err := gurps.NewSkillsFromFile(os.DirFS(dir), "Copy"+library.SkillsExt)
+	assert.NoError(t, err)
+	if !assert.Len(t, skills, 1) || !assert.Len(t, skills[0].Children, 2) {
+		return
+	}
+	technique := skills[0].Children[1]
+	assert.Equal(t, gid.Technique, technique.Type)
+	if assert.NotNil(t, technique.TechniqueDefault) {
+		assert.Equal(t, "Karate", technique.TechniqueDefault.Name)
+		assert.Equal(t, -fxp.One, technique.TechniqueDefault.Modifier)
+	}
+	if assert.NotNil(t, technique.TechniqueLimitModifier) {
+		assert.Equal(t, fxp.Two, *technique.TechniqueLimitModifier)
+	}
+}
+
+func TestImportUnmatched(t *testing.T) {
+	dir := t.TempDir()
+	listPath := filepath.Join(dir, "Traits"+library.TraitsExt)
+	sheetPath := listPath + spreadsheet.CSVExt
+	save := func() (kept, dropped *gurps.Trait) {
+		kept = gurps.NewTrait(nil, nil, false)
+		kept.Name = "Fit"
+		dropped = gurps.NewTrait(nil, nil, false)
+		dropped.Name = "Lame"
+		assert.NoError(t, gurps.SaveTraits([]*gurps.Trait{kept, dropped}, listPath))
+		writeCSV(t, sheetPath, [][]string{
+			{"ID", "Name", "BasePoints"},
+			{kept.ID.String(), "Very Fit", "15"},
+		})
+		return kept, dropped
+	}
+
+	kept, dropped := save()
+	assert.NoError(t, spreadsheet.Import(sheetPath, listPath, false))
+	traits, err := gurps.NewTraitsFromFile(os.DirFS(dir), filepath.Base(listPath))
+	assert.NoError(t, err)
+	if assert.Len(t, traits, 2) {
+		assert.Equal(t, kept.ID, traits[0].ID)
+		assert.Equal(t, "Very Fit", traits[0].Name)
+		assert.Equal(t, dropped.ID, traits[1].ID)
+	}
+
+	kept, _ = save()
+	assert.NoError(t, spreadsheet.Import(sheetPath, listPath, true))
+	traits, err = gurps.NewTraitsFromFile(os.DirFS(dir), filepath.Base(listPath))
+	assert.NoError(t, err)
+	if assert.Len(t, traits, 1) {
+		assert.Equal(t, kept.ID, traits[0].ID)
+	}
+}
+
+func TestImportTechnique(t *testing.T) {
+	dir := t.TempDir()
+	listPath := filepath.Join(dir, "Skills"+library.SkillsExt)
+	sheetPath := listPath + spreadsheet.TSVExt
+	// The technique columns are deliberately placed after the columns that depend on them
+	f, err := os.Create(sheetPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	w := csv.NewWriter(f)
+	w.Comma = '\t'
+	assert.NoError(t, w.WriteAll([][]string{
+		{"Name", "TechniqueLimit", "TechniqueModifier", "Difficulty", "TechniqueDefault"},
+		{"Karate", "", "", "dx/h", ""},
+		{"Feint", "2", "-1", "h", "skill/Karate"},
+		{"Parry Missile Weapons", "", "", "h", "parry/Karate"},
+	}))
+	assert.NoError(t, f.Close())
+
+	assert.NoError(t, spreadsheet.Import(sheetPath, listPath, false))
+	skills, err := gurps.NewSkillsFromFile(os.DirFS(dir), filepath.Base(listPath))
+	assert.NoError(t, err)
+	if !assert.Len(t, skills, 3) {
+		return
+	}
+	assert.Equal(t, gid.Skill, skills[0].Type)
+	assert.Nil(t, skills[0].TechniqueDefault)
+	for _, one := range skills[1:] {
+		assert.Equal(t, gid.Technique, one.Type)
+		if assert.NotNil(t, one.TechniqueDefault) {
+			assert.Equal(t, "Karate", one.TechniqueDefault.Name)
+		}
+	}
+	assert.Equal(t, -fxp.One, skills[1].TechniqueDefault.Modifier)
+	if assert.NotNil(t, skills[1].TechniqueLimitModifier) {
+		assert.Equal(t, fxp.Two, *skills[1].TechniqueLimitModifier)
+	}
+	assert.Equal(t, gid.Parry, skills[2].TechniqueDefault.DefaultType)
+
+	badPath := filepath.Join(dir, "Bad"+spreadsheet.CSVExt)
+	writeCSV(t, badPath, [][]string{
+		{"Name", "TechniqueLimit", "TechniqueDefault"},
+		{"Karate", "2", ""},
+	})
+	assert.Error(t, spreadsheet.Import(badPath, listPath, false))
+}
+
+func writeCSV(t *testing.T, sheetPath string, records [][]string) {
+	t.Helper()
+	f, err := os.Create(sheetPath)
+	if assert.NoError(t, err) {
+		assert.NoError(t, csv.NewWriter(f).WriteAll(records))
+		assert.NoError(t, f.Close())
+	}
+}
+
+func withoutIDs(t *testing.T, sheetPath string) [][]string {
+	t.Helper()
+	f, err := os.Open(sheetPath)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	defer func() { assert.NoError(t, f.Close()) }()
+	records, err := csv.NewReader(f).ReadAll()
+	assert.NoError(t, err)
+	for i, record := range records {
+		records[i] = record[1:]
+	}
+	return records
+}
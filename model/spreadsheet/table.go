@@ -0,0 +1,242 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	xfs "github.com/richardwilkes/toolbox/xio/fs"
+)
+
+// Names of the columns every list type has.
+const (
+	idColumnName        = "ID"
+	parentColumnName    = "Parent"
+	containerColumnName = "Container"
+)
+
+// ParentSeparator separates the names of the containers in the Parent column.
+const ParentSeparator = " > "
+
+// table describes how a list file of T maps onto rows of a spreadsheet.
+type table[T gurps.NodeTypes] struct {
+	name    func(T) string
+	columns []*column[T]
+	create  func(parent T, container bool) T
+	load    func(fileSystem fs.FS, filePath string) ([]T, error)
+	save    func(list []T, filePath string) error
+}
+
+func (t *table[T]) export(listPath string, w *csv.Writer) error {
+	list, err := t.load(os.DirFS(filepath.Dir(listPath)), filepath.Base(listPath))
+	if err != nil {
+		return err
+	}
+	header := []string{idColumnName, parentColumnName, containerColumnName}
+	for _, col := range t.columns {
+		header = append(header, col.names[0])
+	}
+	if err = w.Write(header); err != nil {
+		return errs.Wrap(err)
+	}
+	if err = t.exportRows(w, list, ""); err != nil {
+		return err
+	}
+	w.Flush()
+	return errs.Wrap(w.Error())
+}
+
+func (t *table[T]) exportRows(w *csv.Writer, list []T, parentPath string) error {
+	for _, one := range list {
+		node := gurps.AsNode(one)
+		container := ""
+		if node.Container() {
+			container = "yes"
+		}
+		record := []string{node.UUID().String(), parentPath, container}
+		for _, col := range t.columns {
+			record = append(record, col.get(one))
+		}
+		if err := w.Write(record); err != nil {
+			return errs.Wrap(err)
+		}
+		if node.Container() {
+			if err := t.exportRows(w, node.NodeChildren(), joinPath(parentPath, t.name(one))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *table[T]) importRows(records [][]string, listPath string, replace bool) error {
+	if len(records) == 0 {
+		return errs.New(i18n.Text("no header row found"))
+	}
+	idIndex := -1
+	parentIndex := -1
+	containerIndex := -1
+	indexes := make([]int, len(t.columns))
+	for i := range indexes {
+		indexes[i] = -1
+	}
+	for i, header := range records[0] {
+		switch {
+		case strings.EqualFold(strings.TrimSpace(header), idColumnName):
+			idIndex = i
+		case strings.EqualFold(strings.TrimSpace(header), parentColumnName):
+			parentIndex = i
+		case strings.EqualFold(strings.TrimSpace(header), containerColumnName):
+			containerIndex = i
+		case strings.TrimSpace(header) == "":
+			// Unlabeled columns are ignored, allowing scratch work to live alongside the data
+		default:
+			found := false
+			for j, col := range t.columns {
+				if col.matches(header) {
+					indexes[j] = i
+					found = true
+					break
+				}
+			}
+			if !found {
+				return errs.Newf(i18n.Text("unknown column %q"), header)
+			}
+		}
+	}
+
+	// Rows that carry the ID of an item already in the list file are updated in place, which preserves the data that
+	// can't be represented in a spreadsheet, such as features, prerequisites and weapons.
+	var original []T
+	existing := make(map[uuid.UUID]T)
+	children := make(map[uuid.UUID][]T)
+	if xfs.FileExists(listPath) {
+		var err error
+		if original, err = t.load(os.DirFS(filepath.Dir(listPath)), filepath.Base(listPath)); err != nil {
+			return err
+		}
+		gurps.Traverse(func(one T) bool {
+			node := gurps.AsNode(one)
+			existing[node.UUID()] = one
+			children[node.UUID()] = node.NodeChildren()
+			return false
+		}, false, false, original...)
+	}
+
+	var top []T
+	containers := make(map[string]T)
+	for i, record := range records[1:] {
+		row := i + 2
+		if isBlank(record) {
+			continue
+		}
+		parentPath := strings.TrimSpace(cell(record, parentIndex))
+		container := isTrue(cell(record, containerIndex))
+		var parent T
+		if parentPath != "" {
+			var exists bool
+			if parent, exists = containers[parentPath]; !exists {
+				return errs.Newf(i18n.Text("row %d: no container named %q precedes this row"), row, parentPath)
+			}
+		}
+		var one T
+		reused := false
+		if id, err := uuid.Parse(strings.TrimSpace(cell(record, idIndex))); err == nil {
+			if prior, exists := existing[id]; exists && gurps.AsNode(prior).Container() == container {
+				one = prior
+				reused = true
+				delete(existing, id)
+				node := gurps.AsNode(one)
+				node.SetParent(parent)
+				node.SetChildren(nil)
+			}
+		}
+		if !reused {
+			one = t.create(parent, container)
+		}
+		// Cells are applied in the table's column order rather than the spreadsheet's, so that a column which changes
+		// the kind of item, such as a skill's technique default, is applied before the columns that depend on it.
+		for j, col := range t.columns {
+			if indexes[j] < 0 {
+				continue
+			}
+			if err := col.set(one, strings.TrimSpace(cell(record, indexes[j]))); err != nil {
+				return errs.NewWithCause(fmt.Sprintf(i18n.Text("row %d, column %q"), row, records[0][indexes[j]]), err)
+			}
+		}
+		if parentPath == "" {
+			top = append(top, one)
+		} else {
+			pnode := gurps.AsNode(parent)
+			pnode.SetChildren(append(pnode.NodeChildren(), one))
+		}
+		if container {
+			containers[joinPath(parentPath, t.name(one))] = one
+		}
+	}
+	if !replace {
+		top = append(top, keepUnmatched(original, existing, children)...)
+	}
+	return t.save(top, listPath)
+}
+
+// keepUnmatched returns the items of list that no row matched. Each one keeps those of its original children that no
+// row matched either, while the unmatched children of matched items are appended to the matched item's new children.
+func keepUnmatched[T gurps.NodeTypes](list []T, unmatched map[uuid.UUID]T, children map[uuid.UUID][]T) []T {
+	var kept []T
+	for _, one := range list {
+		node := gurps.AsNode(one)
+		keptChildren := keepUnmatched(children[node.UUID()], unmatched, children)
+		for _, child := range keptChildren {
+			gurps.AsNode(child).SetParent(one)
+		}
+		if _, exists := unmatched[node.UUID()]; exists {
+			node.SetChildren(keptChildren)
+			kept = append(kept, one)
+		} else if len(keptChildren) != 0 {
+			node.SetChildren(append(node.NodeChildren(), keptChildren...))
+		}
+	}
+	return kept
+}
+
+func joinPath(parentPath, name string) string {
+	if parentPath == "" {
+		return name
+	}
+	return parentPath + ParentSeparator + name
+}
+
+func cell(record []string, index int) string {
+	if index < 0 || index >= len(record) {
+		return ""
+	}
+	return record[index]
+}
+
+func isBlank(record []string) bool {
+	for _, one := range record {
+		if strings.TrimSpace(one) != "" {
+			return false
+		}
+	}
+	return true
+}
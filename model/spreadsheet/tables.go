@@ -0,0 +1,387 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/equipment"
+	"github.com/richardwilkes/gcs/v5/model/gurps/gid"
+	"github.com/richardwilkes/gcs/v5/model/gurps/measure"
+	"github.com/richardwilkes/gcs/v5/model/gurps/trait"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+type tabular interface {
+	export(listPath string, w *csv.Writer) error
+	importRows(records [][]string, listPath string, replace bool) error
+}
+
+// tableFor returns the table for the given list file extension, or nil if the extension isn't supported.
+func tableFor(ext string) tabular {
+	switch strings.ToLower(ext) {
+	case library.EquipmentExt:
+		return equipmentTable()
+	case library.EquipmentModifiersExt:
+		return equipmentModifierTable()
+	case library.SkillsExt:
+		return skillTable()
+	case library.SpellsExt:
+		return spellTable()
+	case library.TraitsExt:
+		return traitTable()
+	case library.TraitModifiersExt:
+		return traitModifierTable()
+	default:
+		return nil
+	}
+}
+
+func equipmentTable() *table[*gurps.Equipment] {
+	return &table[*gurps.Equipment]{
+		name: func(e *gurps.Equipment) string { return e.Name },
+		columns: append([]*column[*gurps.Equipment]{
+			textColumn(func(e *gurps.Equipment) *string { return &e.Name }, "Name", "Description", "Item"),
+			numberColumn(func(e *gurps.Equipment) *fxp.Int { return &e.Quantity }, fxp.One, "Quantity", "Qty"),
+			numberColumn(func(e *gurps.Equipment) *fxp.Int { return &e.Value }, 0, "Value", "Cost", "Price"),
+			weightColumn(func(e *gurps.Equipment) *measure.Weight { return &e.Weight }),
+			textColumn(func(e *gurps.Equipment) *string { return &e.TechLevel }, "TechLevel", "TL"),
+			textColumn(func(e *gurps.Equipment) *string { return &e.LegalityClass }, "LegalityClass", "LC"),
+			integerColumn(func(e *gurps.Equipment) *int { return &e.MaxUses }, "MaxUses"),
+			integerColumn(func(e *gurps.Equipment) *int { return &e.Uses }, "Uses"),
+		}, commonColumns(
+			func(e *gurps.Equipment) *string { return &e.PageRef },
+			func(e *gurps.Equipment) *string { return &e.LocalNotes },
+			func(e *gurps.Equipment) *string { return &e.VTTNotes },
+			func(e *gurps.Equipment) *[]string { return &e.Tags },
+		)...),
+		create: func(parent *gurps.Equipment, container bool) *gurps.Equipment {
+			return gurps.NewEquipment(nil, parent, container)
+		},
+		load: gurps.NewEquipmentFromFile,
+		save: gurps.SaveEquipment,
+	}
+}
+
+func equipmentModifierTable() *table[*gurps.EquipmentModifier] {
+	return &table[*gurps.EquipmentModifier]{
+		name: func(m *gurps.EquipmentModifier) string { return m.Name },
+		columns: append([]*column[*gurps.EquipmentModifier]{
+			textColumn(func(m *gurps.EquipmentModifier) *string { return &m.Name }, "Name", "Description"),
+			{
+				names: []string{"CostType"},
+				get:   func(m *gurps.EquipmentModifier) string { return m.CostType.Key() },
+				set: func(m *gurps.EquipmentModifier, value string) error {
+					m.CostType = equipment.ExtractModifierCostType(value)
+					return nil
+				},
+			},
+			textColumn(func(m *gurps.EquipmentModifier) *string { return &m.CostAmount }, "Cost", "Value"),
+			{
+				names: []string{"WeightType"},
+				get:   func(m *gurps.EquipmentModifier) string { return m.WeightType.Key() },
+				set: func(m *gurps.EquipmentModifier, value string) error {
+					m.WeightType = equipment.ExtractModifierWeightType(value)
+					return nil
+				},
+			},
+			textColumn(func(m *gurps.EquipmentModifier) *string { return &m.WeightAmount }, "Weight", "Wt"),
+			textColumn(func(m *gurps.EquipmentModifier) *string { return &m.TechLevel }, "TechLevel", "TL"),
+			boolColumn(func(m *gurps.EquipmentModifier) *bool { return &m.Disabled }, "Disabled"),
+		}, commonColumns(
+			func(m *gurps.EquipmentModifier) *string { return &m.PageRef },
+			func(m *gurps.EquipmentModifier) *string { return &m.LocalNotes },
+			func(m *gurps.EquipmentModifier) *string { return &m.VTTNotes },
+			func(m *gurps.EquipmentModifier) *[]string { return &m.Tags },
+		)...),
+		create: func(parent *gurps.EquipmentModifier, container bool) *gurps.EquipmentModifier {
+			return gurps.NewEquipmentModifier(nil, parent, container)
+		},
+		load: gurps.NewEquipmentModifiersFromFile,
+		save: gurps.SaveEquipmentModifiers,
+	}
+}
+
+func skillTable() *table[*gurps.Skill] {
+	return &table[*gurps.Skill]{
+		name: func(s *gurps.Skill) string { return s.Name },
+		columns: append([]*column[*gurps.Skill]{
+			textColumn(func(s *gurps.Skill) *string { return &s.Name }, "Name"),
+			textColumn(func(s *gurps.Skill) *string { return &s.Specialization }, "Specialization", "Spec"),
+			techniqueDefaultColumn(),
+			{
+				names: []string{"TechniqueModifier"},
+				get: func(s *gurps.Skill) string {
+					if s.TechniqueDefault == nil {
+						return ""
+					}
+					return s.TechniqueDefault.Modifier.String()
+				},
+				set: func(s *gurps.Skill, value string) error {
+					if s.TechniqueDefault == nil {
+						if value != "" {
+							return errs.New(i18n.Text("only techniques have a default modifier"))
+						}
+						return nil
+					}
+					if value == "" {
+						s.TechniqueDefault.Modifier = 0
+						return nil
+					}
+					v, err := parseNumber(value)
+					if err != nil {
+						return err
+					}
+					s.TechniqueDefault.Modifier = v
+					return nil
+				},
+			},
+			{
+				names: []string{"TechniqueLimit"},
+				get: func(s *gurps.Skill) string {
+					if s.TechniqueLimitModifier == nil {
+						return ""
+					}
+					return s.TechniqueLimitModifier.String()
+				},
+				set: func(s *gurps.Skill, value string) error {
+					if value == "" {
+						s.TechniqueLimitModifier = nil
+						return nil
+					}
+					if s.TechniqueDefault == nil {
+						return errs.New(i18n.Text("only techniques have a limit"))
+					}
+					v, err := parseNumber(value)
+					if err != nil {
+						return err
+					}
+					s.TechniqueLimitModifier = &v
+					return nil
+				},
+			},
+			techLevelColumn(func(s *gurps.Skill) **string { return &s.TechLevel }),
+			difficultyColumn(func(s *gurps.Skill) *gurps.AttributeDifficulty { return &s.Difficulty }),
+			numberColumn(func(s *gurps.Skill) *fxp.Int { return &s.Points }, 0, "Points", "Pts"),
+			numberColumn(func(s *gurps.Skill) *fxp.Int { return &s.EncumbrancePenaltyMultiplier }, 0,
+				"EncumbrancePenaltyMultiplier"),
+		}, commonColumns(
+			func(s *gurps.Skill) *string { return &s.PageRef },
+			func(s *gurps.Skill) *string { return &s.LocalNotes },
+			func(s *gurps.Skill) *string { return &s.VTTNotes },
+			func(s *gurps.Skill) *[]string { return &s.Tags },
+		)...),
+		create: func(parent *gurps.Skill, container bool) *gurps.Skill {
+			return gurps.NewSkill(nil, parent, container)
+		},
+		load: gurps.NewSkillsFromFile,
+		save: gurps.SaveSkills,
+	}
+}
+
+func spellTable() *table[*gurps.Spell] {
+	return &table[*gurps.Spell]{
+		name: func(s *gurps.Spell) string { return s.Name },
+		columns: append([]*column[*gurps.Spell]{
+			textColumn(func(s *gurps.Spell) *string { return &s.Name }, "Name"),
+			techLevelColumn(func(s *gurps.Spell) **string { return &s.TechLevel }),
+			difficultyColumn(func(s *gurps.Spell) *gurps.AttributeDifficulty { return &s.Difficulty }),
+			{
+				names: []string{"College", "Colleges"},
+				get:   func(s *gurps.Spell) string { return strings.Join(s.College, ", ") },
+				set: func(s *gurps.Spell, value string) error {
+					s.College = gurps.ExtractTags(value)
+					return nil
+				},
+			},
+			textColumn(func(s *gurps.Spell) *string { return &s.PowerSource }, "PowerSource"),
+			textColumn(func(s *gurps.Spell) *string { return &s.Class }, "Class"),
+			textColumn(func(s *gurps.Spell) *string { return &s.Resist }, "Resist", "Resistance"),
+			textColumn(func(s *gurps.Spell) *string { return &s.CastingCost }, "CastingCost", "Cost"),
+			textColumn(func(s *gurps.Spell) *string { return &s.MaintenanceCost }, "MaintenanceCost", "Maintain"),
+			textColumn(func(s *gurps.Spell) *string { return &s.CastingTime }, "CastingTime", "Time"),
+			textColumn(func(s *gurps.Spell) *string { return &s.Duration }, "Duration"),
+			numberColumn(func(s *gurps.Spell) *fxp.Int { return &s.Points }, 0, "Points", "Pts"),
+		}, commonColumns(
+			func(s *gurps.Spell) *string { return &s.PageRef },
+			func(s *gurps.Spell) *string { return &s.LocalNotes },
+			func(s *gurps.Spell) *string { return &s.VTTNotes },
+			func(s *gurps.Spell) *[]string { return &s.Tags },
+		)...),
+		create: func(parent *gurps.Spell, container bool) *gurps.Spell {
+			return gurps.NewSpell(nil, parent, container)
+		},
+		load: gurps.NewSpellsFromFile,
+		save: gurps.SaveSpells,
+	}
+}
+
+func traitTable() *table[*gurps.Trait] {
+	return &table[*gurps.Trait]{
+		name: func(t *gurps.Trait) string { return t.Name },
+		columns: append([]*column[*gurps.Trait]{
+			textColumn(func(t *gurps.Trait) *string { return &t.Name }, "Name"),
+			numberColumn(func(t *gurps.Trait) *fxp.Int { return &t.BasePoints }, 0, "BasePoints", "Points", "Pts"),
+			numberColumn(func(t *gurps.Trait) *fxp.Int { return &t.PointsPerLevel }, 0, "PointsPerLevel"),
+			numberColumn(func(t *gurps.Trait) *fxp.Int { return &t.Levels }, 0, "Levels", "Level"),
+			boolColumn(func(t *gurps.Trait) *bool { return &t.CanLevel }, "CanLevel"),
+			boolColumn(func(t *gurps.Trait) *bool { return &t.RoundCostDown }, "RoundCostDown"),
+			{
+				names: []string{"CR", "SelfControlRoll"},
+				get: func(t *gurps.Trait) string {
+					if t.CR == trait.None {
+						return ""
+					}
+					return strconv.Itoa(int(t.CR))
+				},
+				set: func(t *gurps.Trait, value string) error {
+					if value == "" {
+						t.CR = trait.None
+						return nil
+					}
+					cr, err := strconv.Atoi(value)
+					if err != nil {
+						return errs.NewWithCause(i18n.Text("invalid self-control roll"), err)
+					}
+					t.CR = trait.SelfControlRoll(cr).EnsureValid()
+					return nil
+				},
+			},
+			{
+				names: []string{"CRAdj", "SelfControlRollAdj"},
+				get: func(t *gurps.Trait) string {
+					if t.CRAdj == gurps.NoCRAdj {
+						return ""
+					}
+					return t.CRAdj.Key()
+				},
+				set: func(t *gurps.Trait, value string) error {
+					t.CRAdj = gurps.ExtractSelfControlRollAdj(value)
+					return nil
+				},
+			},
+			boolColumn(func(t *gurps.Trait) *bool { return &t.Disabled }, "Disabled"),
+		}, commonColumns(
+			func(t *gurps.Trait) *string { return &t.PageRef },
+			func(t *gurps.Trait) *string { return &t.LocalNotes },
+			func(t *gurps.Trait) *string { return &t.VTTNotes },
+			func(t *gurps.Trait) *[]string { return &t.Tags },
+		)...),
+		create: func(parent *gurps.Trait, container bool) *gurps.Trait {
+			return gurps.NewTrait(nil, parent, container)
+		},
+		load: gurps.NewTraitsFromFile,
+		save: gurps.SaveTraits,
+	}
+}
+
+// techniqueDefaultColumn maps onto the default of a technique, written as the default's type followed by the skill's
+// name and specialization when the default is skill-based, e.g. "skill/Karate" or "dx". Filling in the cell turns a
+// skill into a technique and clearing it turns a technique back into a skill.
+func techniqueDefaultColumn() *column[*gurps.Skill] {
+	return &column[*gurps.Skill]{
+		names: []string{"TechniqueDefault", "Technique"},
+		get: func(s *gurps.Skill) string {
+			def := s.TechniqueDefault
+			if def == nil {
+				return ""
+			}
+			parts := []string{def.DefaultType}
+			if def.SkillBased() {
+				parts = append(parts, def.Name)
+				if def.Specialization != "" {
+					parts = append(parts, def.Specialization)
+				}
+			}
+			return strings.Join(parts, "/")
+		},
+		set: func(s *gurps.Skill, value string) error {
+			if value == "" {
+				if s.TechniqueDefault != nil {
+					s.Type = gid.Skill
+					s.TechniqueDefault = nil
+					s.TechniqueLimitModifier = nil
+					if s.Difficulty.Attribute == "" {
+						s.Difficulty.Attribute = gurps.AttributeIDFor(nil, gid.Dexterity)
+					}
+				}
+				return nil
+			}
+			if s.Container() {
+				return errs.New(i18n.Text("containers can't be techniques"))
+			}
+			parts := strings.SplitN(value, "/", 3)
+			def := &gurps.SkillDefault{}
+			def.SetType(strings.TrimSpace(parts[0]))
+			if def.SkillBased() {
+				if len(parts) < 2 || strings.TrimSpace(parts[1]) == "" {
+					return errs.New(i18n.Text("a skill-based default needs a skill name, e.g. skill/Karate"))
+				}
+				def.Name = strings.TrimSpace(parts[1])
+				if len(parts) > 2 {
+					def.Specialization = strings.TrimSpace(parts[2])
+				}
+			}
+			if s.TechniqueDefault != nil {
+				def.Modifier = s.TechniqueDefault.Modifier
+			}
+			s.Type = gid.Technique
+			s.TechniqueDefault = def
+			s.Difficulty.Attribute = ""
+			return nil
+		},
+	}
+}
+
+func traitModifierTable() *table[*gurps.TraitModifier] {
+	return &table[*gurps.TraitModifier]{
+		name: func(m *gurps.TraitModifier) string { return m.Name },
+		columns: append([]*column[*gurps.TraitModifier]{
+			textColumn(func(m *gurps.TraitModifier) *string { return &m.Name }, "Name"),
+			numberColumn(func(m *gurps.TraitModifier) *fxp.Int { return &m.Cost }, 0, "Cost"),
+			{
+				names: []string{"CostType"},
+				get:   func(m *gurps.TraitModifier) string { return m.CostType.Key() },
+				set: func(m *gurps.TraitModifier, value string) error {
+					m.CostType = trait.ExtractModifierCostType(value)
+					return nil
+				},
+			},
+			numberColumn(func(m *gurps.TraitModifier) *fxp.Int { return &m.Levels }, 0, "Levels", "Level"),
+			{
+				names: []string{"Affects"},
+				get:   func(m *gurps.TraitModifier) string { return m.Affects.Key() },
+				set: func(m *gurps.TraitModifier, value string) error {
+					m.Affects = trait.ExtractAffects(value)
+					return nil
+				},
+			},
+			boolColumn(func(m *gurps.TraitModifier) *bool { return &m.Disabled }, "Disabled"),
+		}, commonColumns(
+			func(m *gurps.TraitModifier) *string { return &m.PageRef },
+			func(m *gurps.TraitModifier) *string { return &m.LocalNotes },
+			func(m *gurps.TraitModifier) *string { return &m.VTTNotes },
+			func(m *gurps.TraitModifier) *[]string { return &m.Tags },
+		)...),
+		create: func(parent *gurps.TraitModifier, container bool) *gurps.TraitModifier {
+			return gurps.NewTraitModifier(nil, parent, container)
+		},
+		load: gurps.NewTraitModifiersFromFile,
+		save: gurps.SaveTraitModifiers,
+	}
+}
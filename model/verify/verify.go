@@ -0,0 +1,335 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/json"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/txt"
+	"github.com/richardwilkes/toolbox/xio/fs"
+)
+
+// calcKey is the key of the blocks holding calculated values within a sheet.
+const calcKey = "calc"
+
+// identityKeys are the keys used to pair up the entries of a list in the stored data with those in the freshly
+// calculated data, in order of preference. Entries without any of them are paired by position.
+var identityKeys = []string{"id", "attr_id"}
+
+// labelKeys are the keys used to give a human-readable label to an entry, in order of preference.
+var labelKeys = []string{"name", "description", "usage", "choice_name", "attr_id"}
+
+// Difference describes a calculated value stored within a sheet that doesn't match the value calculated now. Stored
+// or Calculated is nil when the value is present on only one side.
+type Difference struct {
+	Path       string `json:"path"`
+	Label      string `json:"label,omitempty"`
+	Stored     any    `json:"stored"`
+	Calculated any    `json:"calculated"`
+}
+
+func (d *Difference) String() string {
+	var buffer strings.Builder
+	buffer.WriteString(d.Path)
+	if d.Label != "" {
+		fmt.Fprintf(&buffer, " (%s)", d.Label)
+	}
+	fmt.Fprintf(&buffer, i18n.Text(": stored %s, calculated %s"), describe(d.Stored), describe(d.Calculated))
+	return buffer.String()
+}
+
+// Report holds the result of verifying a single sheet.
+type Report struct {
+	File        string        `json:"file"`
+	Error       string        `json:"error,omitempty"`
+	Differences []*Difference `json:"differences,omitempty"`
+}
+
+// Verify loads the sheet, recalculates it, and compares each of the calc blocks stored within the file to the one GCS
+// writes now.
+func Verify(filePath string) (*Report, error) {
+	report := &Report{File: filePath}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	var stored any
+	if stored, err = decode(data); err != nil {
+		report.Error = err.Error()
+		return report, nil
+	}
+	var entity *gurps.Entity
+	if entity, err = gurps.NewEntityFromFile(os.DirFS(filepath.Dir(filePath)), filepath.Base(filePath)); err != nil {
+		report.Error = err.Error()
+		return report, nil
+	}
+	entity.Recalculate()
+	var buffer bytes.Buffer
+	if err = jio.Save(context.Background(), &buffer, entity); err != nil {
+		return nil, err
+	}
+	var fresh any
+	if fresh, err = decode(buffer.Bytes()); err != nil {
+		return nil, err
+	}
+	report.compare(stored, fresh, "", "", false)
+	return report, nil
+}
+
+// Files verifies the sheets, printing a report of the differences found in each one, either as text or as JSON.
+// Directories are traversed recursively, verifying the sheets within them. Returns the number of sheets that had
+// differences or could not be loaded.
+func Files(asJSON bool, paths ...string) (int, error) {
+	list, err := collectSheets(paths)
+	if err != nil {
+		return 0, err
+	}
+	reports := make([]*Report, 0, len(list))
+	failed := 0
+	for _, p := range list {
+		var report *Report
+		if report, err = Verify(p); err != nil {
+			return failed, err
+		}
+		if report.Error != "" || len(report.Differences) != 0 {
+			failed++
+		}
+		reports = append(reports, report)
+	}
+	if asJSON {
+		return failed, jio.Save(context.Background(), os.Stdout, reports)
+	}
+	for _, report := range reports {
+		if report.Error != "" {
+			fmt.Printf(i18n.Text("%s: unable to load: %s\n"), report.File, report.Error)
+		}
+		for _, d := range report.Differences {
+			fmt.Printf("%s: %s\n", report.File, d)
+		}
+	}
+	fmt.Printf(i18n.Text("%d of %d sheets had differences\n"), failed, len(list))
+	return failed, nil
+}
+
+func collectSheets(paths []string) ([]string, error) {
+	paths, err := fs.UniquePaths(paths...)
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	for _, p := range paths {
+		if err = filepath.WalkDir(p, func(path string, d iofs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if d.IsDir() {
+				if path != p && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if path == p || strings.EqualFold(filepath.Ext(path), library.SheetExt) {
+				list = append(list, path)
+			}
+			return nil
+		}); err != nil {
+			return nil, errs.Wrap(err)
+		}
+	}
+	txt.SortStringsNaturalAscending(list)
+	return list, nil
+}
+
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var result any
+	if err := decoder.Decode(&result); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return result, nil
+}
+
+// compare walks the stored and fresh data in parallel. Outside of calc blocks, only the structure is followed, since
+// differences there are just the normalization GCS applies when it writes a file. Inside calc blocks, every value is
+// compared.
+func (r *Report) compare(stored, fresh any, path, label string, inCalc bool) {
+	switch s := stored.(type) {
+	case map[string]any:
+		f, ok := fresh.(map[string]any)
+		if !ok {
+			if inCalc {
+				r.add(path, label, stored, fresh)
+			}
+			return
+		}
+		if !inCalc {
+			label = labelFor(s, label)
+		}
+		for _, key := range unionKeys(s, f, inCalc) {
+			sv, sExists := s[key]
+			fv, fExists := f[key]
+			switch {
+			case inCalc || key == calcKey:
+				switch {
+				case sv == nil && fv == nil:
+				case !sExists || !fExists:
+					r.add(joinPath(path, key), label, sv, fv)
+				default:
+					r.compare(sv, fv, joinPath(path, key), label, true)
+				}
+			case sExists && fExists:
+				r.compare(sv, fv, joinPath(path, key), label, false)
+			}
+		}
+	case []any:
+		f, ok := fresh.([]any)
+		if !ok {
+			if inCalc {
+				r.add(path, label, stored, fresh)
+			}
+			return
+		}
+		if inCalc {
+			for i := 0; i < len(s) || i < len(f); i++ {
+				var sv, fv any
+				if i < len(s) {
+					sv = s[i]
+				}
+				if i < len(f) {
+					fv = f[i]
+				}
+				r.compare(sv, fv, fmt.Sprintf("%s[%d]", path, i), label, true)
+			}
+			return
+		}
+		byIdentity := make(map[string]any)
+		for _, one := range f {
+			if id := identityOf(one); id != "" {
+				byIdentity[id] = one
+			}
+		}
+		for i, one := range s {
+			var match any
+			if id := identityOf(one); id != "" {
+				match = byIdentity[id]
+			} else if i < len(f) {
+				match = f[i]
+			}
+			if match != nil {
+				r.compare(one, match, fmt.Sprintf("%s[%d]", path, i), label, false)
+			}
+		}
+	default:
+		if inCalc && !equal(stored, fresh) {
+			r.add(path, label, stored, fresh)
+		}
+	}
+}
+
+func (r *Report) add(path, label string, stored, fresh any) {
+	r.Differences = append(r.Differences, &Difference{
+		Path:       path,
+		Label:      label,
+		Stored:     stored,
+		Calculated: fresh,
+	})
+}
+
+// unionKeys returns the keys of both maps in sorted order. Outside of calc blocks, only the keys present in the stored
+// data matter, along with any calc block that only the fresh data has.
+func unionKeys(stored, fresh map[string]any, inCalc bool) []string {
+	keys := make([]string, 0, len(stored)+1)
+	for k := range stored {
+		keys = append(keys, k)
+	}
+	for k := range fresh {
+		if _, exists := stored[k]; !exists && (inCalc || k == calcKey) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func identityOf(value any) string {
+	if m, ok := value.(map[string]any); ok {
+		for _, key := range identityKeys {
+			if id, ok2 := m[key].(string); ok2 && id != "" {
+				return key + ":" + id
+			}
+		}
+	}
+	return ""
+}
+
+func labelFor(m map[string]any, fallback string) string {
+	for _, key := range labelKeys {
+		if label, ok := m[key].(string); ok && label != "" {
+			return label
+		}
+	}
+	return fallback
+}
+
+func equal(stored, fresh any) bool {
+	if sn, ok := stored.(json.Number); ok {
+		if fn, ok2 := fresh.(json.Number); ok2 {
+			if sn == fn {
+				return true
+			}
+			sv, err1 := strconv.ParseFloat(string(sn), 64)
+			fv, err2 := strconv.ParseFloat(string(fn), 64)
+			return err1 == nil && err2 == nil && sv == fv
+		}
+		return false
+	}
+	return stored == fresh
+}
+
+func describe(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return i18n.Text("(missing)")
+	case string:
+		return strconv.Quote(v)
+	case json.Number:
+		return string(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package verify_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/datafile"
+	"github.com/richardwilkes/gcs/v5/model/gurps/settings"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/gcs/v5/model/verify"
+	"github.com/richardwilkes/json"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSettings struct {
+	general *settings.General
+	sheet   *gurps.SheetSettings
+}
+
+func (s *testSettings) GeneralSettings() *settings.General {
+	return s.general
+}
+
+func (s *testSettings) SheetSettings() *gurps.SheetSettings {
+	return s.sheet
+}
+
+func (s *testSettings) Libraries() library.Libraries {
+	return nil
+}
+
+func TestMain(m *testing.M) {
+	gurps.SettingsProvider = &testSettings{
+		general: settings.NewGeneral(),
+		sheet:   gurps.FactorySheetSettings(),
+	}
+	os.Exit(m.Run())
+}
+
+func TestVerifyCurrentSheet(t *testing.T) {
+	sheetPath := filepath.Join(t.TempDir(), "Current"+library.SheetExt)
+	assert.NoError(t, gurps.NewEntity(datafile.PC).Save(sheetPath))
+	report, err := verify.Verify(sheetPath)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Error)
+	assert.Empty(t, report.Differences)
+}
+
+func TestVerifyPairsByIdentity(t *testing.T) {
+	// The attributes are stored in reverse order, so pairing them by position would report differences
+	sheetPath := writeFixture(t, t.TempDir(), func(data map[string]any) {
+		reverse(t, data["attributes"])
+	})
+	report, err := verify.Verify(sheetPath)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Error)
+	assert.Empty(t, report.Differences)
+}
+
+func TestVerifyStaleCalc(t *testing.T) {
+	var dxIndex int
+	sheetPath := writeFixture(t, t.TempDir(), func(data map[string]any) {
+		calc, _ := data["calc"].(map[string]any)
+		calc["basic_lift"] = "1000 lb"
+		attributes := reverse(t, data["attributes"])
+		for i, one := range attributes {
+			if attr, _ := one.(map[string]any); attr["attr_id"] == "dx" {
+				dxIndex = i
+				attrCalc, _ := attr["calc"].(map[string]any)
+				attrCalc["value"] = 99
+			}
+		}
+		// Values outside of calc blocks are never reported
+		data["total_points"] = 1
+	})
+	report, err := verify.Verify(sheetPath)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Error)
+	if !assert.Len(t, report.Differences, 2) {
+		return
+	}
+	assert.Equal(t, fmt.Sprintf("attributes[%d].calc.value", dxIndex), report.Differences[0].Path)
+	assert.Equal(t, "dx", report.Differences[0].Label)
+	assert.Equal(t, json.Number("99"), report.Differences[0].Stored)
+	assert.Equal(t, "calc.basic_lift", report.Differences[1].Path)
+	assert.Equal(t, "1000 lb", report.Differences[1].Stored)
+	assert.NotEqual(t, "1000 lb", report.Differences[1].Calculated)
+}
+
+func TestVerifyMissingCalc(t *testing.T) {
+	sheetPath := writeFixture(t, t.TempDir(), func(data map[string]any) {
+		delete(data, "calc")
+	})
+	report, err := verify.Verify(sheetPath)
+	assert.NoError(t, err)
+	if assert.Len(t, report.Differences, 1) {
+		assert.Equal(t, "calc", report.Differences[0].Path)
+		assert.Nil(t, report.Differences[0].Stored)
+		assert.NotNil(t, report.Differences[0].Calculated)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, gurps.NewEntity(datafile.PC).Save(filepath.Join(dir, "Current"+library.SheetExt)))
+	writeFixture(t, dir, func(data map[string]any) {
+		calc, _ := data["calc"].(map[string]any)
+		calc["basic_lift"] = "1000 lb"
+	})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a sheet"), 0o640))
+	failed, err := verify.Files(false, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, failed)
+}
+
+// writeFixture saves a new sheet into the directory, then rewrites it after letting adjust alter its raw data.
+func writeFixture(t *testing.T, dir string, adjust func(data map[string]any)) string {
+	t.Helper()
+	sheetPath := filepath.Join(dir, "Stale"+library.SheetExt)
+	assert.NoError(t, gurps.NewEntity(datafile.PC).Save(sheetPath))
+	buffer, err := os.ReadFile(sheetPath)
+	assert.NoError(t, err)
+	var data map[string]any
+	assert.NoError(t, json.Unmarshal(buffer, &data))
+	adjust(data)
+	buffer, err = json.Marshal(data)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(sheetPath, buffer, 0o640))
+	return sheetPath
+}
+
+func reverse(t *testing.T, value any) []any {
+	t.Helper()
+	list, ok := value.([]any)
+	assert.True(t, ok)
+	assert.Greater(t, len(list), 1)
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+	return list
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package pdf
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/richardwilkes/pdf"
+	"github.com/richardwilkes/toolbox/errs"
+)
+
+const (
+	// searchDPI is the resolution used when scanning pages for text. Matching doesn't depend on the resolution, so the
+	// lowest practical value is used to minimize the cost of rendering.
+	searchDPI = 9
+	// maxSearchMatches is the most matches counted on a single page.
+	maxSearchMatches = 1000
+	// maxCachedSearches is the number of searches whose results are retained for each PDF.
+	maxCachedSearches = 256
+)
+
+// SearchSource identifies a PDF to be searched, along with the page reference key and offset that map to it.
+type SearchSource struct {
+	Key    string
+	Path   string
+	Offset int
+}
+
+// SearchHit holds a page within a source that contains at least one match.
+type SearchHit struct {
+	Source     *SearchSource
+	PageNumber int // 0-based page number within the PDF
+	Matches    int
+}
+
+// PageReference returns the page reference for the hit, e.g. "B123". Returns an empty string if the page precedes the
+// first page the reference key's offset maps to.
+func (h *SearchHit) PageReference() string {
+	page := h.PageNumber + 1 - h.Source.Offset
+	if page < 1 {
+		return ""
+	}
+	return h.Source.Key + strconv.Itoa(page)
+}
+
+type indexEntry struct {
+	modTime time.Time
+	size    int64
+	results map[string][]pageMatches
+	order   []string // The keys of results, oldest first
+}
+
+type pageMatches struct {
+	pageNumber int
+	matches    int
+}
+
+// Index holds the results of prior searches over PDFs, so that repeated searches for the same text don't have to scan
+// the documents again. Matching is done by the same search the PDF viewer uses to highlight text, so every hit can be
+// highlighted when its page is opened. The results for a PDF are discarded when the file changes or is no longer among
+// the sources being searched, and only the most recent searches are retained for each one.
+type Index struct {
+	lock    sync.Mutex
+	entries map[string]*indexEntry
+}
+
+var sharedIndex = &Index{entries: make(map[string]*indexEntry)}
+
+// SharedIndex returns the index shared by the application.
+func SharedIndex() *Index {
+	return sharedIndex
+}
+
+// Search the sources for the given text. found is called for each page with at least one match. See SearchAll for
+// details.
+func (x *Index) Search(sources []*SearchSource, text string, found func(hit *SearchHit), progress func(done, total int), canceled func() bool) error {
+	return x.SearchAll(sources, []string{text}, func(_ int, hit *SearchHit) { found(hit) }, progress, canceled)
+}
+
+// SearchAll searches the sources for each of the texts, which are matched without regard to case, opening each PDF
+// at most once. found is called with the index of the text for each page with at least one match of it; pages that
+// precede the first page the source's offset maps to are skipped. progress, if not nil, is called after each source
+// has been processed. Searching stops early if canceled returns true. Searching a PDF for text that hasn't been
+// searched for before may take a considerable amount of time on large documents, so this should not be called from the
+// UI thread. Sources that can't be searched are skipped, with the first such problem being returned once the others
+// have been searched.
+func (x *Index) SearchAll(sources []*SearchSource, texts []string, found func(which int, hit *SearchHit), progress func(done, total int), canceled func() bool) error {
+	x.retain(sources)
+	keys := make([]string, len(texts))
+	for i, text := range texts {
+		keys[i] = searchKey(text)
+	}
+	var firstErr error
+	for i, source := range sources {
+		if canceled() {
+			return nil
+		}
+		results, err := x.searchOne(source.Path, keys, canceled)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+		} else {
+			if results == nil {
+				return nil // Canceled
+			}
+			for which, key := range keys {
+				for _, one := range results[key] {
+					if one.pageNumber+1-source.Offset >= 1 {
+						found(which, &SearchHit{
+							Source:     source,
+							PageNumber: one.pageNumber,
+							Matches:    one.matches,
+						})
+					}
+				}
+			}
+		}
+		if progress != nil {
+			progress(i+1, len(sources))
+		}
+	}
+	return firstErr
+}
+
+// retain discards the results for any files that aren't among the sources.
+func (x *Index) retain(sources []*SearchSource) {
+	paths := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		paths[source.Path] = true
+	}
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	for filePath := range x.entries {
+		if !paths[filePath] {
+			delete(x.entries, filePath)
+		}
+	}
+}
+
+// searchOne returns the results for each of the keys within the file, searching the file for those that haven't been
+// searched for yet. Returns nil if canceled.
+func (x *Index) searchOne(filePath string, keys []string, canceled func() bool) (map[string][]pageMatches, error) {
+	fi, err := os.Stat(filePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	results := make(map[string][]pageMatches, len(keys))
+	var missing []string
+	x.lock.Lock()
+	entry, ok := x.entries[filePath]
+	if !ok || !entry.modTime.Equal(fi.ModTime()) || entry.size != fi.Size() {
+		entry = &indexEntry{
+			modTime: fi.ModTime(),
+			size:    fi.Size(),
+			results: make(map[string][]pageMatches),
+		}
+		x.entries[filePath] = entry
+	}
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if pages, cached := entry.results[key]; cached {
+			results[key] = pages
+		} else if _, seen := results[key]; !seen {
+			results[key] = nil
+			missing = append(missing, key)
+		}
+	}
+	x.lock.Unlock()
+	if len(missing) == 0 {
+		return results, nil
+	}
+	var data []byte
+	if data, err = os.ReadFile(filePath); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	var doc *pdf.Document
+	if doc, err = pdf.New(data, 0); err != nil {
+		return nil, errs.NewWithCause(filePath, err)
+	}
+	count := doc.PageCount()
+	for i := 0; i < count; i++ {
+		for _, key := range missing {
+			if canceled() {
+				// Don't record partial results
+				return nil, nil
+			}
+			page, renderErr := doc.RenderPage(i, searchDPI, maxSearchMatches, key)
+			if renderErr != nil {
+				return nil, errs.NewWithCause(filePath, renderErr)
+			}
+			if len(page.SearchHits) != 0 {
+				results[key] = append(results[key], pageMatches{
+					pageNumber: i,
+					matches:    len(page.SearchHits),
+				})
+			}
+		}
+	}
+	x.lock.Lock()
+	if x.entries[filePath] == entry {
+		for _, key := range missing {
+			entry.results[key] = results[key]
+			entry.order = append(entry.order, key)
+		}
+		if excess := len(entry.order) - maxCachedSearches; excess > 0 {
+			for _, key := range entry.order[:excess] {
+				delete(entry.results, key)
+			}
+			entry.order = append([]string(nil), entry.order[excess:]...)
+		}
+	}
+	x.lock.Unlock()
+	return results, nil
+}
+
+// searchKey returns the text in the form used both for searching and for keying the results. The PDF search ignores
+// case, so the text is lowercased to let searches that differ only by case share their results.
+func searchKey(text string) string {
+	return strings.ToLower(strings.TrimSpace(text))
+}
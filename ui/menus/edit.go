@@ -41,6 +41,16 @@ var (
 	IncreaseUses *unison.Action
 	// DecreaseUses decrements the uses of the selection.
 	DecreaseUses *unison.Action
+	// Buy purchases more of the selection using the character's cash.
+	Buy *unison.Action
+	// Sell sells some of the selection for cash.
+	Sell *unison.Action
+	// FireWeapon expends a shot from the selected weapon(s).
+	FireWeapon *unison.Action
+	// ReloadWeapon reloads the selected weapon(s).
+	ReloadWeapon *unison.Action
+	// CastSpell casts the selected spell.
+	CastSpell *unison.Action
 	// IncreaseSkillLevel increments the uses of the skill level.
 	IncreaseSkillLevel *unison.Action
 	// DecreaseSkillLevel decrements the uses of the skill level.
@@ -165,6 +175,36 @@ func registerEditMenuActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	}
+	Buy = &unison.Action{
+		ID:              constants.BuyItemID,
+		Title:           i18n.Text("Buy…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	}
+	Sell = &unison.Action{
+		ID:              constants.SellItemID,
+		Title:           i18n.Text("Sell…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	}
+	FireWeapon = &unison.Action{
+		ID:              constants.FireWeaponItemID,
+		Title:           i18n.Text("Fire"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	}
+	ReloadWeapon = &unison.Action{
+		ID:              constants.ReloadWeaponItemID,
+		Title:           i18n.Text("Reload"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	}
+	CastSpell = &unison.Action{
+		ID:              constants.CastSpellItemID,
+		Title:           i18n.Text("Cast…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	}
 	IncreaseSkillLevel = &unison.Action{
 		ID:              constants.IncrementSkillLevelItemID,
 		Title:           i18n.Text("Increase Skill Level"),
@@ -230,6 +270,11 @@ func registerEditMenuActions() {
 	settings.RegisterKeyBinding("dec", Decrement)
 	settings.RegisterKeyBinding("inc.uses", IncreaseUses)
 	settings.RegisterKeyBinding("dec.uses", DecreaseUses)
+	settings.RegisterKeyBinding("buy", Buy)
+	settings.RegisterKeyBinding("sell", Sell)
+	settings.RegisterKeyBinding("fire", FireWeapon)
+	settings.RegisterKeyBinding("reload", ReloadWeapon)
+	settings.RegisterKeyBinding("cast", CastSpell)
 	settings.RegisterKeyBinding("inc.sl", IncreaseSkillLevel)
 	settings.RegisterKeyBinding("dec.sl", DecreaseSkillLevel)
 	settings.RegisterKeyBinding("inc.tl", IncreaseTechLevel)
@@ -262,6 +307,11 @@ func setupEditMenu(bar unison.Menu) {
 	i = insertItem(m, i, Decrement.NewMenuItem(f))
 	i = insertItem(m, i, IncreaseUses.NewMenuItem(f))
 	i = insertItem(m, i, DecreaseUses.NewMenuItem(f))
+	i = insertItem(m, i, Buy.NewMenuItem(f))
+	i = insertItem(m, i, Sell.NewMenuItem(f))
+	i = insertItem(m, i, FireWeapon.NewMenuItem(f))
+	i = insertItem(m, i, ReloadWeapon.NewMenuItem(f))
+	i = insertItem(m, i, CastSpell.NewMenuItem(f))
 	i = insertItem(m, i, IncreaseSkillLevel.NewMenuItem(f))
 	i = insertItem(m, i, DecreaseSkillLevel.NewMenuItem(f))
 	i = insertItem(m, i, IncreaseTechLevel.NewMenuItem(f))
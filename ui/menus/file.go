@@ -42,6 +42,10 @@ var (
 	NewCharacterSheet *unison.Action
 	// NewCharacterTemplate creates a new character template.
 	NewCharacterTemplate *unison.Action
+	// NewVehicle creates a new vehicle.
+	NewVehicle *unison.Action
+	// ImportStatBlock creates a new character sheet from the text of a stat block.
+	ImportStatBlock *unison.Action
 	// NewTraitsLibrary creates a new traits library.
 	NewTraitsLibrary *unison.Action
 	// NewTraitModifiersLibrary creates a new trait modifiers library.
@@ -72,6 +76,16 @@ var (
 	ExportAsPNG *unison.Action
 	// ExportAsJPEG exports the content as a JPEG.
 	ExportAsJPEG *unison.Action
+	// ExportAsStatBlock exports the content as a compact stat block.
+	ExportAsStatBlock *unison.Action
+	// ExportAsFantasyGrounds exports the content in the Fantasy Grounds GURPS ruleset XML format.
+	ExportAsFantasyGrounds *unison.Action
+	// ExportAsHTML exports the content as a self-contained, interactive HTML page.
+	ExportAsHTML *unison.Action
+	// ExportAsCards exports spells, powers and equipment as printable cards.
+	ExportAsCards *unison.Action
+	// ExportAsBundle exports the content as a portable bundle that includes the library files it depends on.
+	ExportAsBundle *unison.Action
 	// Print the content.
 	Print *unison.Action
 )
@@ -93,6 +107,13 @@ func registerFileMenuActions() {
 			workspace.DisplayNewDockable(nil, sheet.NewTemplate("untitled"+library.TemplatesExt, gurps.NewTemplate()))
 		},
 	}
+	NewVehicle = &unison.Action{
+		ID:    constants.NewVehicleItemID,
+		Title: i18n.Text("New Vehicle"),
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			workspace.DisplayNewDockable(nil, sheet.NewVehicle("untitled"+library.VehiclesExt, gurps.NewVehicle()))
+		},
+	}
 	NewTraitsLibrary = &unison.Action{
 		ID:    constants.NewTraitsLibraryItemID,
 		Title: i18n.Text("New Traits Library"),
@@ -144,6 +165,11 @@ func registerFileMenuActions() {
 			workspace.DisplayNewDockable(nil, lists.NewSpellTableDockable("Spells"+library.SpellsExt, nil))
 		},
 	}
+	ImportStatBlock = &unison.Action{
+		ID:              constants.ImportStatBlockItemID,
+		Title:           i18n.Text("Import Stat Block…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { sheet.ImportStatBlock() },
+	}
 	Open = &unison.Action{
 		ID:         constants.OpenItemID,
 		Title:      i18n.Text("Open…"),
@@ -237,6 +263,36 @@ func registerFileMenuActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	}
+	ExportAsStatBlock = &unison.Action{
+		ID:              constants.ExportAsStatBlockItemID,
+		Title:           i18n.Text("Stat Block…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	}
+	ExportAsFantasyGrounds = &unison.Action{
+		ID:              constants.ExportAsFantasyGroundsItemID,
+		Title:           i18n.Text("Fantasy Grounds XML…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	}
+	ExportAsHTML = &unison.Action{
+		ID:              constants.ExportAsHTMLItemID,
+		Title:           i18n.Text("Interactive HTML…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	}
+	ExportAsCards = &unison.Action{
+		ID:              constants.ExportAsCardsItemID,
+		Title:           i18n.Text("Cards…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	}
+	ExportAsBundle = &unison.Action{
+		ID:              constants.ExportAsBundleItemID,
+		Title:           i18n.Text("Portable Bundle…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	}
 	Print = &unison.Action{
 		ID:              constants.PrintItemID,
 		Title:           i18n.Text("Print…"),
@@ -247,6 +303,8 @@ func registerFileMenuActions() {
 
 	settings.RegisterKeyBinding("new.char.sheet", NewCharacterSheet)
 	settings.RegisterKeyBinding("new.char.template", NewCharacterTemplate)
+	settings.RegisterKeyBinding("new.vehicle", NewVehicle)
+	settings.RegisterKeyBinding("import.stat.block", ImportStatBlock)
 	settings.RegisterKeyBinding("new.adq.lib", NewTraitsLibrary)
 	settings.RegisterKeyBinding("new.adm.lib", NewTraitModifiersLibrary)
 	settings.RegisterKeyBinding("new.eqp.lib", NewEquipmentLibrary)
@@ -262,6 +320,11 @@ func registerFileMenuActions() {
 	settings.RegisterKeyBinding("export.webp", ExportAsWEBP)
 	settings.RegisterKeyBinding("export.png", ExportAsPNG)
 	settings.RegisterKeyBinding("export.jpeg", ExportAsJPEG)
+	settings.RegisterKeyBinding("export.stat.block", ExportAsStatBlock)
+	settings.RegisterKeyBinding("export.fantasy.grounds", ExportAsFantasyGrounds)
+	settings.RegisterKeyBinding("export.html", ExportAsHTML)
+	settings.RegisterKeyBinding("export.cards", ExportAsCards)
+	settings.RegisterKeyBinding("export.bundle", ExportAsBundle)
 	settings.RegisterKeyBinding("print", Print)
 }
 
@@ -270,6 +333,8 @@ func setupFileMenu(bar unison.Menu) {
 	m := bar.Menu(unison.FileMenuID)
 	i := insertItem(m, 0, NewCharacterSheet.NewMenuItem(f))
 	i = insertItem(m, i, NewCharacterTemplate.NewMenuItem(f))
+	i = insertItem(m, i, NewVehicle.NewMenuItem(f))
+	i = insertItem(m, i, ImportStatBlock.NewMenuItem(f))
 
 	i = insertSeparator(m, i)
 	i = insertItem(m, i, NewTraitsLibrary.NewMenuItem(f))
@@ -334,6 +399,11 @@ func exportToUpdater(menu unison.Menu) {
 	menu.InsertItem(-1, ExportAsWEBP.NewMenuItem(factory))
 	menu.InsertItem(-1, ExportAsPNG.NewMenuItem(factory))
 	menu.InsertItem(-1, ExportAsJPEG.NewMenuItem(factory))
+	menu.InsertItem(-1, ExportAsStatBlock.NewMenuItem(factory))
+	menu.InsertItem(-1, ExportAsFantasyGrounds.NewMenuItem(factory))
+	menu.InsertItem(-1, ExportAsHTML.NewMenuItem(factory))
+	menu.InsertItem(-1, ExportAsCards.NewMenuItem(factory))
+	menu.InsertItem(-1, ExportAsBundle.NewMenuItem(factory))
 	menu.InsertSeparator(-1, false)
 	index := 0
 	for _, lib := range settings.Global().Libraries().List() {
@@ -14,6 +14,7 @@ package menus
 import (
 	"github.com/richardwilkes/gcs/v5/constants"
 	"github.com/richardwilkes/gcs/v5/model/settings"
+	uisettings "github.com/richardwilkes/gcs/v5/ui/workspace/settings"
 	"github.com/richardwilkes/toolbox/i18n"
 	"github.com/richardwilkes/unison"
 )
@@ -29,6 +30,12 @@ var (
 	NewTraitContainerModifier *unison.Action
 	// AddNaturalAttacks creates the natural attacks.
 	AddNaturalAttacks *unison.Action
+	// AdvanceTime moves the in-game date forward.
+	AdvanceTime *unison.Action
+	// ActiveSpells shows the spells currently in effect.
+	ActiveSpells *unison.Action
+	// Effects shows the temporary effects currently applied.
+	Effects *unison.Action
 	// NewSkill creates a new skill.
 	NewSkill *unison.Action
 	// NewSkillContainer creates a new skill container.
@@ -65,6 +72,10 @@ var (
 	OpenOnePageReference *unison.Action
 	// OpenEachPageReference opens each page reference associated with the selected items.
 	OpenEachPageReference *unison.Action
+	// SearchSourcebooks searches the PDFs mapped to page reference keys.
+	SearchSourcebooks *unison.Action
+	// FillMissingPageReferences suggests page references for each item missing one.
+	FillMissingPageReferences *unison.Action
 )
 
 func registerItemMenuActions() {
@@ -106,6 +117,27 @@ func registerItemMenuActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	}
+	// AdvanceTime moves the in-game date forward.
+	AdvanceTime = &unison.Action{
+		ID:              constants.AdvanceTimeItemID,
+		Title:           i18n.Text("Advance Time…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	}
+	// ActiveSpells shows the spells currently in effect.
+	ActiveSpells = &unison.Action{
+		ID:              constants.ActiveSpellsItemID,
+		Title:           i18n.Text("Active Spells…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	}
+	// Effects shows the temporary effects currently applied.
+	Effects = &unison.Action{
+		ID:              constants.EffectsItemID,
+		Title:           i18n.Text("Effects…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	}
 	// NewSkill creates a new skill.
 	NewSkill = &unison.Action{
 		ID:              constants.NewSkillItemID,
@@ -250,12 +282,28 @@ func registerItemMenuActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	}
+	// SearchSourcebooks searches the PDFs mapped to page reference keys.
+	SearchSourcebooks = &unison.Action{
+		ID:              constants.SearchSourcebooksItemID,
+		Title:           i18n.Text("Search Sourcebooks…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { uisettings.ShowPDFSearch("", nil) },
+	}
+	// FillMissingPageReferences suggests page references for each item missing one.
+	FillMissingPageReferences = &unison.Action{
+		ID:              constants.FillMissingPageReferencesItemID,
+		Title:           i18n.Text("Fill Missing Page References…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	}
 
 	settings.RegisterKeyBinding("new.adq", NewTrait)
 	settings.RegisterKeyBinding("new.adq.container", NewTraitContainer)
 	settings.RegisterKeyBinding("new.adm", NewTraitModifier)
 	settings.RegisterKeyBinding("new.adm.container", NewTraitContainerModifier)
 	settings.RegisterKeyBinding("add.natural.attacks", AddNaturalAttacks)
+	settings.RegisterKeyBinding("advance.time", AdvanceTime)
+	settings.RegisterKeyBinding("active.spells", ActiveSpells)
+	settings.RegisterKeyBinding("effects", Effects)
 	settings.RegisterKeyBinding("new.skl", NewSkill)
 	settings.RegisterKeyBinding("new.skl.container", NewSkillContainer)
 	settings.RegisterKeyBinding("new.skl.technique", NewTechnique)
@@ -274,6 +322,8 @@ func registerItemMenuActions() {
 	settings.RegisterKeyBinding("new.ranged", NewRangedWeapon)
 	settings.RegisterKeyBinding("pageref.open.first", OpenOnePageReference)
 	settings.RegisterKeyBinding("pageref.open.all", OpenEachPageReference)
+	settings.RegisterKeyBinding("pageref.search", SearchSourcebooks)
+	settings.RegisterKeyBinding("pageref.fill", FillMissingPageReferences)
 }
 
 func createItemMenu(f unison.MenuFactory) unison.Menu {
@@ -314,5 +364,12 @@ func createItemMenu(f unison.MenuFactory) unison.Menu {
 	m.InsertSeparator(-1, false)
 	m.InsertItem(-1, OpenOnePageReference.NewMenuItem(f))
 	m.InsertItem(-1, OpenEachPageReference.NewMenuItem(f))
+	m.InsertItem(-1, SearchSourcebooks.NewMenuItem(f))
+	m.InsertItem(-1, FillMissingPageReferences.NewMenuItem(f))
+
+	m.InsertSeparator(-1, false)
+	m.InsertItem(-1, AdvanceTime.NewMenuItem(f))
+	m.InsertItem(-1, ActiveSpells.NewMenuItem(f))
+	m.InsertItem(-1, Effects.NewMenuItem(f))
 	return m
 }
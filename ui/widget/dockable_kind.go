@@ -15,6 +15,7 @@ package widget
 const (
 	SheetDockableKind    = "sheet"
 	TemplateDockableKind = "template"
+	VehicleDockableKind  = "vehicle"
 	ListDockableKind     = "list"
 )
 
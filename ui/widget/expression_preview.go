@@ -0,0 +1,153 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package widget
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/eval"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+// expressionDiagnoser evaluates the text, returning the result and a diagnostic for each expression it contains, or
+// false if the text isn't an expression.
+type expressionDiagnoser func(text string) (string, []*gurps.ExpressionDiagnostic, bool)
+
+// ExpressionPreview provides a live view of the evaluation of an expression, showing its result, the values of any
+// variables it references, and the location of any error.
+type ExpressionPreview struct {
+	unison.Panel
+	expression  func() string
+	diagnose    expressionDiagnoser
+	result      *NonEditableField
+	details     *unison.Label
+	diagnostics []*gurps.ExpressionDiagnostic
+	normalInk   unison.Ink
+}
+
+// NewExpressionPreview creates a new expression preview. If resolver is nil or returns nil, a default entity, created
+// once for the preview, is used to resolve variables.
+func NewExpressionPreview(expression func() string, resolver func() eval.VariableResolver) *ExpressionPreview {
+	var fallback eval.VariableResolver
+	return newExpressionPreview(expression, func(text string) (string, []*gurps.ExpressionDiagnostic, bool) {
+		var r eval.VariableResolver
+		if resolver != nil {
+			r = resolver()
+		}
+		if r == nil {
+			if fallback == nil {
+				fallback = gurps.NewScratchEntity(nil)
+			}
+			r = fallback
+		}
+		d := gurps.DiagnoseExpression(text, r)
+		return d.Result.String(), []*gurps.ExpressionDiagnostic{d}, true
+	})
+}
+
+// NewWeaponExpressionPreview creates a new expression preview for a weapon stat field, which may hold several
+// expressions enclosed in braces.
+func NewWeaponExpressionPreview(expression func() string, w *gurps.Weapon) *ExpressionPreview {
+	return newExpressionPreview(expression, w.DiagnoseExpressions)
+}
+
+func newExpressionPreview(expression func() string, diagnose expressionDiagnoser) *ExpressionPreview {
+	p := &ExpressionPreview{
+		expression: expression,
+		diagnose:   diagnose,
+	}
+	p.Self = p
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing / 2,
+	})
+	p.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	p.result = NewNonEditableField(func(_ *NonEditableField) {})
+	p.normalInk = p.result.OnBackgroundInk
+	p.AddChild(p.result)
+	p.details = unison.NewLabel()
+	p.details.Font = unison.FieldFont
+	p.details.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	p.AddChild(p.details)
+	p.Sync()
+	return p
+}
+
+// Diagnostics returns the diagnostics from the last sync, one for each expression evaluated.
+func (p *ExpressionPreview) Diagnostics() []*gurps.ExpressionDiagnostic {
+	return p.diagnostics
+}
+
+// Sync the preview to the current expression.
+func (p *ExpressionPreview) Sync() {
+	result, list, isExpression := p.diagnose(p.expression())
+	p.diagnostics = list
+	var failed *gurps.ExpressionDiagnostic
+	for _, d := range list {
+		if d.Err != nil {
+			failed = d
+			break
+		}
+	}
+	switch {
+	case !isExpression:
+		p.result.Text = i18n.Text("Not an expression")
+		p.result.OnBackgroundInk = p.normalInk
+	case failed != nil:
+		p.result.Text = i18n.Text("Error: ") + failed.Err.Error()
+		p.result.OnBackgroundInk = unison.ErrorColor
+	default:
+		p.result.Text = "= " + result
+		p.result.OnBackgroundInk = p.normalInk
+	}
+	var details []string
+	for _, d := range list {
+		if len(list) > 1 {
+			details = append(details, d.Expression)
+		}
+		if d.Expanded != "" && d.Expanded != d.Expression {
+			details = append(details, i18n.Text("Expanded: ")+d.Expanded)
+		}
+		if d.ErrorIndex != -1 {
+			details = append(details, fmt.Sprintf(i18n.Text("At position %d: %s ▸%s"), d.ErrorIndex+1,
+				d.Expression[:d.ErrorIndex], d.Expression[d.ErrorIndex:]))
+		}
+		if len(d.Variables) != 0 {
+			vars := make([]string, 0, len(d.Variables))
+			for _, v := range d.Variables {
+				value := v.Value
+				if !v.Resolved() {
+					value = "?"
+				}
+				vars = append(vars, fmt.Sprintf("$%s = %s", v.Name, value))
+			}
+			details = append(details, i18n.Text("Variables: ")+strings.Join(vars, ", "))
+		}
+	}
+	p.details.Text = strings.Join(details, "  •  ")
+	if len(details) != 0 {
+		p.details.Tooltip = unison.NewTooltipWithText(strings.Join(details, "\n"))
+	} else {
+		p.details.Tooltip = nil
+	}
+	p.MarkForLayoutAndRedraw()
+}
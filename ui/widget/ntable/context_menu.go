@@ -28,6 +28,8 @@ var DefaultContextMenuItems = []ContextMenuItem{
 	{i18n.Text("Decrement"), constants.DecrementItemID},
 	{i18n.Text("Increase Uses"), constants.IncrementUsesItemID},
 	{i18n.Text("Decrease Uses"), constants.DecrementUsesItemID},
+	{i18n.Text("Buy…"), constants.BuyItemID},
+	{i18n.Text("Sell…"), constants.SellItemID},
 	{i18n.Text("Increase Skill Level"), constants.IncrementSkillLevelItemID},
 	{i18n.Text("Decrease Skill Level"), constants.DecrementSkillLevelItemID},
 	{i18n.Text("Increase Tech Level"), constants.IncrementTechLevelItemID},
@@ -73,6 +75,8 @@ var OtherEquipmentExtraContextMenuItems = []ContextMenuItem{
 // RangedWeaponExtraContextMenuItems holds context menu items specific to the ranged weapon list.
 var RangedWeaponExtraContextMenuItems = []ContextMenuItem{
 	{i18n.Text("New Ranged Weapon"), constants.NewRangedWeaponItemID},
+	{i18n.Text("Fire"), constants.FireWeaponItemID},
+	{i18n.Text("Reload"), constants.ReloadWeaponItemID},
 }
 
 // SkillExtraContextMenuItems holds context menu items specific to the skill list.
@@ -87,6 +91,7 @@ var SpellExtraContextMenuItems = []ContextMenuItem{
 	{i18n.Text("New Spell"), constants.NewSpellItemID},
 	{i18n.Text("New Spell Container"), constants.NewSpellContainerItemID},
 	{i18n.Text("New Ritual Magic Spell"), constants.NewRitualMagicSpellItemID},
+	{i18n.Text("Cast…"), constants.CastSpellItemID},
 }
 
 // TraitExtraContextMenuItems holds context menu items specific to the trait list.
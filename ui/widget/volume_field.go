@@ -0,0 +1,48 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package widget
+
+import (
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/measure"
+)
+
+// VolumeField is field that holds a volume value.
+type VolumeField = NumericField[measure.Volume]
+
+// NewVolumeField creates a new field that holds a volume.
+func NewVolumeField(targetMgr *TargetMgr, targetKey, undoTitle string, entity *gurps.Entity, get func() measure.Volume, set func(measure.Volume), min, max measure.Volume, noMinWidth bool) *VolumeField {
+	var getPrototypes func(min, max measure.Volume) []measure.Volume
+	if !noMinWidth {
+		getPrototypes = func(min, max measure.Volume) []measure.Volume {
+			if min == measure.Volume(fxp.Min) {
+				min = measure.Volume(-fxp.One)
+			}
+			min = measure.Volume(fxp.Int(min).Trunc() + fxp.One - 1)
+			if max == measure.Volume(fxp.Max) {
+				max = measure.Volume(fxp.One)
+			}
+			max = measure.Volume(fxp.Int(max).Trunc() + fxp.One - 1)
+			return []measure.Volume{min, measure.Volume(fxp.Two - 1), max}
+		}
+	}
+	format := func(value measure.Volume) string {
+		return gurps.SheetSettingsFor(entity).DefaultVolumeUnits.Format(value)
+	}
+	extract := func(s string) (measure.Volume, error) {
+		return measure.VolumeFromString(s, gurps.SheetSettingsFor(entity).DefaultVolumeUnits)
+	}
+	f := NewNumericField[measure.Volume](targetMgr, targetKey, undoTitle, getPrototypes, get, set, format, extract, min, max)
+	f.RuneTypedCallback = f.DefaultRuneTyped
+	return f
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package editors
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xmath"
+	"github.com/richardwilkes/unison"
+)
+
+type effectDurationUnit struct {
+	name    string
+	seconds int
+}
+
+func (u effectDurationUnit) String() string {
+	return u.name
+}
+
+func effectDurationUnits() []effectDurationUnit {
+	return []effectDurationUnit{
+		{name: i18n.Text("Turns"), seconds: gurps.SecondsPerTurn},
+		{name: i18n.Text("Minutes"), seconds: gurps.SecondsPerMinute},
+		{name: i18n.Text("Hours"), seconds: gurps.SecondsPerHour},
+		{name: i18n.Text("Days"), seconds: gurps.SecondsPerDay},
+		{name: i18n.Text("Weeks"), seconds: gurps.SecondsPerWeek},
+	}
+}
+
+// EditEffect displays a dialog for editing the effect, which may be a new one. If the dialog is accepted, the effect is
+// updated and true is returned. A duration of 0 means the effect lasts until it is removed.
+func EditEffect(entity *gurps.Entity, effect *gurps.Effect) bool {
+	edited := effect.Clone()
+	edited.ID = effect.ID
+	units := effectDurationUnits()
+	unit := units[0]
+	for i := len(units) - 1; i >= 0 && edited.Duration > 0; i-- {
+		if edited.Duration%units[i].seconds == 0 {
+			unit = units[i]
+			break
+		}
+	}
+	amount := edited.Duration / unit.seconds
+
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	addNameLabelAndField(panel, &edited.Name)
+	addLabelAndStringField(panel, i18n.Text("Source"), "", &edited.Source)
+	addNotesLabelAndField(panel, &edited.Notes)
+	durationTitle := i18n.Text("Duration")
+	wrapper := addFlowWrapper(panel, durationTitle, 2)
+	addIntegerField(wrapper, nil, "", durationTitle, i18n.Text("Use 0 for an effect that lasts until it is removed"),
+		&amount, 0, 99999)
+	addPopup(wrapper, units, &unit)
+	panel.AddChild(unison.NewPanel())
+	wrapper = unison.NewPanel()
+	wrapper.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing,
+	})
+	addCheckBox(wrapper, i18n.Text("Stackable, to a maximum of"), &edited.Stackable)
+	addIntegerField(wrapper, nil, "", i18n.Text("Maximum Stacks"), i18n.Text("Use 0 for no limit"),
+		&edited.MaxStacks, 0, 999)
+	wrapper.AddChild(widget.NewFieldTrailingLabel(i18n.Text("stacks")))
+	panel.AddChild(wrapper)
+	panel.AddChild(newFeaturesPanel(entity, edited, &edited.Features))
+
+	if unison.QuestionDialogWithPanel(panel) != unison.ModalResponseOK {
+		return false
+	}
+	if edited.Name = strings.TrimSpace(edited.Name); edited.Name == "" {
+		edited.Name = i18n.Text("Effect")
+	}
+	edited.Duration = amount * unit.seconds
+	if edited.Remaining <= 0 || edited.Remaining > edited.Duration {
+		edited.Remaining = edited.Duration
+	}
+	if edited.Stackable {
+		edited.AddStacks(0)
+	} else {
+		edited.Stacks = xmath.Min(edited.Stacks, 1)
+	}
+	*effect = *edited
+	return true
+}
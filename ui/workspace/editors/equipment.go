@@ -44,8 +44,9 @@ func EditEquipment(owner widget.Rebuildable, equipment *gurps.Equipment, carried
 				addLabelAndDecimalField(content, nil, "", qtyLabel, "", &e.editorData.Quantity, 0, fxp.Max-1)
 			}
 			valueLabel := i18n.Text("Value")
-			wrapper := addFlowWrapper(content, valueLabel, 3)
+			wrapper := addFlowWrapper(content, valueLabel, 4)
 			addDecimalField(wrapper, nil, "", valueLabel, "", &e.editorData.Value, 0, fxp.Max-1)
+			addCurrencyPopup(wrapper, &e.editorData.Currency)
 			wrapper.AddChild(widget.NewFieldInteriorLeadingLabel(i18n.Text("Extended")))
 			wrapper.AddChild(widget.NewNonEditableField(func(field *widget.NonEditableField) {
 				var value fxp.Int
@@ -53,7 +54,7 @@ func EditEquipment(owner widget.Rebuildable, equipment *gurps.Equipment, carried
 					value = gurps.ValueAdjustedForModifiers(e.editorData.Value, e.editorData.Modifiers)
 					if e.target.Container() {
 						for _, one := range e.target.Children {
-							value += one.ExtendedValue()
+							value += gurps.ConvertCurrency(one.ExtendedValue(), one.Currency, e.editorData.Currency)
 						}
 					}
 					value = value.Mul(e.editorData.Quantity)
@@ -77,14 +78,26 @@ func EditEquipment(owner widget.Rebuildable, equipment *gurps.Equipment, carried
 			}))
 			content.AddChild(unison.NewPanel())
 			addCheckBox(content, i18n.Text("Ignore weight for skills"), &e.editorData.WeightIgnoredForSkills)
+			volumeLabel := i18n.Text("Volume")
+			wrapper = addFlowWrapper(content, volumeLabel, 3)
+			addVolumeField(wrapper, nil, "", volumeLabel, "", e.target.Entity, &e.editorData.Volume, false)
+			wrapper.AddChild(widget.NewFieldInteriorLeadingLabel(i18n.Text("Extended")))
+			wrapper.AddChild(widget.NewNonEditableField(func(field *widget.NonEditableField) {
+				field.Text = gurps.SheetSettingsFor(e.target.Entity).DefaultVolumeUnits.Format(
+					gurps.ExtendedVolumeFor(e.editorData.Quantity, e.editorData.Volume, e.target.Children))
+				field.MarkForLayoutAndRedraw()
+			}))
 			usesLabel := i18n.Text("Uses")
 			wrapper = addFlowWrapper(content, usesLabel, 3)
 			usesField := addIntegerField(wrapper, nil, "", usesLabel, "", &e.editorData.Uses, 0, 9999999)
 			maxUsesLabel := i18n.Text("Maximum Uses")
 			wrapper.AddChild(widget.NewFieldInteriorLeadingLabel(maxUsesLabel))
 			addIntegerField(wrapper, nil, "", maxUsesLabel, "", &e.editorData.MaxUses, 0, 9999999)
+			addLabelAndStringField(content, i18n.Text("Ammo Damage Type"),
+				i18n.Text("When used as ammunition, replaces the damage type of the weapon firing it"),
+				&e.editorData.AmmoDamageType)
 			addTagsLabelAndField(content, &e.editorData.Tags)
-			addPageRefLabelAndField(content, &e.editorData.PageRef)
+			addPageRefLabelAndField(content, &e.editorData.PageRef, func() string { return e.editorData.Name })
 			adjustFieldBlank(usesField, e.editorData.MaxUses <= 0)
 			content.AddChild(newPrereqPanel(e.target.Entity, &e.editorData.Prereq))
 			content.AddChild(newFeaturesPanel(e.target.Entity, e.target, &e.editorData.Features))
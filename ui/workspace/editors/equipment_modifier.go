@@ -42,7 +42,7 @@ func initEquipmentModifierEditor(e *editor[*gurps.EquipmentModifier, *gurps.Equi
 		addEquipmentWeightFields(content, e)
 	}
 	addTagsLabelAndField(content, &e.editorData.Tags)
-	addPageRefLabelAndField(content, &e.editorData.PageRef)
+	addPageRefLabelAndField(content, &e.editorData.PageRef, func() string { return e.editorData.Name })
 	if !e.target.Container() {
 		content.AddChild(newFeaturesPanel(e.target.Entity, e.target, &e.editorData.Features))
 	}
@@ -282,11 +282,11 @@ func (p *equipmentProvider) descriptionText() string {
 	if p.forPage {
 		if entity, ok := p.provider.(*gurps.Entity); ok {
 			if p.carried {
-				title = fmt.Sprintf(i18n.Text("Carried Equipment (%s; $%s)"),
+				title = fmt.Sprintf(i18n.Text("Carried Equipment (%s; %s)"),
 					entity.SheetSettings.DefaultWeightUnits.Format(entity.WeightCarried(false)),
-					entity.WealthCarried().String())
+					entity.FormatWealth(entity.WealthCarried()))
 			} else {
-				title = fmt.Sprintf(i18n.Text("Other Equipment ($%s)"), entity.WealthNotCarried().String())
+				title = fmt.Sprintf(i18n.Text("Other Equipment (%s)"), entity.FormatWealth(entity.WealthNotCarried()))
 			}
 		}
 	}
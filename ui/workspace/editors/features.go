@@ -76,7 +76,7 @@ func newFeaturesPanel(entity *gurps.Entity, featureParent fmt.Stringer, features
 		if created := p.createFeatureForType(lastFeatureTypeUsed); created != nil {
 			*features = slices.Insert(*features, 0, created)
 			p.insertFeaturePanel(1, created)
-			unison.Ancestor[*unison.DockContainer](p).MarkForLayoutRecursively()
+			markFeaturesForLayout(p)
 			widget.MarkModified(p)
 		}
 	}
@@ -87,6 +87,17 @@ func newFeaturesPanel(entity *gurps.Entity, featureParent fmt.Stringer, features
 	return p
 }
 
+// markFeaturesForLayout lays out the dockable the panel is in again or, if it isn't within one, such as when it is in a
+// dialog, its window.
+func markFeaturesForLayout(p unison.Paneler) {
+	if dc := unison.Ancestor[*unison.DockContainer](p); dc != nil {
+		dc.MarkForLayoutRecursively()
+	} else if wnd := p.AsPanel().Window(); wnd != nil {
+		wnd.Content().MarkForLayoutRecursively()
+		wnd.Pack()
+	}
+}
+
 func (p *featuresPanel) insertFeaturePanel(index int, f feature.Feature) {
 	var panel *unison.Panel
 	switch one := f.(type) {
@@ -139,7 +150,7 @@ func (p *featuresPanel) createBasePanel(f feature.Feature) *unison.Panel {
 			*p.features = slices.Delete(*p.features, i, i+1)
 		}
 		panel.RemoveFromParent()
-		unison.Ancestor[*unison.DockContainer](p).MarkForLayoutRecursively()
+		markFeaturesForLayout(p)
 		widget.MarkModified(p)
 	}
 	panel.AddChild(deleteButton)
@@ -268,7 +279,7 @@ func (p *featuresPanel) createSkillBonusPanel(f *feature.SkillBonus) *unison.Pan
 			panel.RemoveChildAtIndex(i + j)
 		}
 		p.createSecondarySkillPanels(panel, i, f)
-		unison.Ancestor[*unison.DockContainer](p).MarkForLayoutRecursively()
+		markFeaturesForLayout(p)
 		widget.MarkModified(p)
 	}
 	criteriaPopup, criteriaField = addStringCriteriaPanel(wrapper, "", "", i18n.Text("Name Qualifier"), &f.NameCriteria, 1, false)
@@ -428,7 +439,7 @@ func (p *featuresPanel) createWeaponDamageBonusPanel(f *feature.WeaponBonus) *un
 			panel.RemoveChildAtIndex(i + j)
 		}
 		p.createSecondaryWeaponPanels(panel, i, f)
-		unison.Ancestor[*unison.DockContainer](p).MarkForLayoutRecursively()
+		markFeaturesForLayout(p)
 		widget.MarkModified(p)
 	}
 	criteriaPopup, criteriaField = addStringCriteriaPanel(wrapper, "", "", i18n.Text("Name Qualifier"), &f.NameCriteria, 1, false)
@@ -611,7 +622,7 @@ func (p *featuresPanel) addTypeSwitcher(parent *unison.Panel, f feature.Feature)
 			i := slices.IndexFunc(list, func(one feature.Feature) bool { return one == f })
 			list[i] = newFeature
 			p.insertFeaturePanel(i+1, newFeature)
-			unison.Ancestor[*unison.DockContainer](p).MarkForLayoutRecursively()
+			markFeaturesForLayout(p)
 			widget.MarkModified(p)
 		}
 	}
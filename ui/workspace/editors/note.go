@@ -15,6 +15,7 @@ import (
 	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/gcs/v5/res"
 	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/toolbox/i18n"
 	"github.com/richardwilkes/unison"
 )
 
@@ -25,6 +26,9 @@ func EditNote(owner widget.Rebuildable, note *gurps.Note) {
 
 func initNoteEditor(e *editor[*gurps.Note, *gurps.NoteEditData], content *unison.Panel) func() {
 	addNotesLabelAndField(content, &e.editorData.Text)
-	addPageRefLabelAndField(content, &e.editorData.PageRef)
+	addPageRefLabelAndField(content, &e.editorData.PageRef, func() string { return "" })
+	dateField := addLabelAndStringField(content, i18n.Text("Date"),
+		i18n.Text("The in-game date this note applies to, which may be left empty"), &e.editorData.Date)
+	dateField.ValidateCallback = func() bool { return e.target.Entity.ValidDate(dateField.Text()) }
 	return nil
 }
@@ -12,9 +12,16 @@
 package editors
 
 import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
 	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/pdf"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
 	"github.com/richardwilkes/gcs/v5/ui/widget/ntable"
 	"github.com/richardwilkes/gcs/v5/ui/workspace/settings"
+	"github.com/richardwilkes/toolbox/i18n"
 	"github.com/richardwilkes/unison"
 )
 
@@ -56,3 +63,303 @@ func OpenEachPageRef[T gurps.NodeTypes](table *unison.Table[*ntable.Node[T]]) {
 		}
 	}
 }
+
+const maxPageRefSuggestions = 10
+
+// PageRefFor returns a pointer to the page reference field of the data, or nil if it doesn't have one.
+func PageRefFor(data any) *string {
+	switch t := data.(type) {
+	case *gurps.Trait:
+		return &t.PageRef
+	case *gurps.TraitModifier:
+		return &t.PageRef
+	case *gurps.Skill:
+		return &t.PageRef
+	case *gurps.Spell:
+		return &t.PageRef
+	case *gurps.Equipment:
+		return &t.PageRef
+	case *gurps.EquipmentModifier:
+		return &t.PageRef
+	case *gurps.Note:
+		return &t.PageRef
+	default:
+		return nil
+	}
+}
+
+// SuggestPageRef searches the mapped PDFs for the text and presents a menu of ranked candidates below the button. The
+// chosen candidate is passed to apply.
+func SuggestPageRef(button *unison.Button, text string, apply func(ref string)) {
+	if strings.TrimSpace(text) == "" {
+		unison.WarningDialogWithMessage(i18n.Text("Nothing to search for"),
+			i18n.Text("Enter a name before asking for page reference suggestions."))
+		return
+	}
+	button.SetEnabled(false)
+	go func() {
+		hits, err := settings.SuggestPageRefs(text, func() bool { return false })
+		unison.InvokeTask(func() {
+			button.SetEnabled(true)
+			if err != nil {
+				unison.ErrorDialogWithError(i18n.Text("Unable to search sourcebooks"), err)
+				return
+			}
+			if len(hits) == 0 {
+				unison.WarningDialogWithMessage(i18n.Text("No suggestions"),
+					fmt.Sprintf(i18n.Text("No mapped sourcebook mentions \"%s\"."), text))
+				return
+			}
+			if len(hits) > maxPageRefSuggestions {
+				hits = hits[:maxPageRefSuggestions]
+			}
+			f := unison.DefaultMenuFactory()
+			id := unison.ContextMenuIDFlag
+			m := f.NewMenu(id, "", nil)
+			for _, hit := range hits {
+				id++
+				ref := hit.PageReference()
+				m.InsertItem(-1, f.NewItem(id, fmt.Sprintf(i18n.Text("%s (%d matches)"), ref, hit.Matches),
+					unison.KeyBinding{}, nil, func(_ unison.MenuItem) { apply(ref) }))
+			}
+			m.Popup(button.RectToRoot(button.ContentRect(true)), 0)
+		})
+	}()
+}
+
+type pageRefFillUndoEdit = *unison.UndoEdit[*pageRefFillList]
+
+type pageRefFillList struct {
+	Owner widget.Rebuildable
+	List  []*pageRefFill
+}
+
+func (p *pageRefFillList) Apply() {
+	for _, one := range p.List {
+		*one.Target = one.PageRef
+	}
+	if p.Owner != nil {
+		widget.MarkModified(p.Owner)
+		p.Owner.Rebuild(true)
+	}
+}
+
+type pageRefFill struct {
+	Target  *string
+	PageRef string
+}
+
+type pageRefCandidate struct {
+	name    string
+	target  *string
+	ref     string
+	include bool
+}
+
+// CanFillMissingPageRefs returns true if the table has at least one row that is missing a page reference.
+func CanFillMissingPageRefs[T gurps.NodeTypes](table *unison.Table[*ntable.Node[T]]) bool {
+	return len(collectMissingPageRefs(table)) != 0
+}
+
+// FillMissingPageRefs searches the mapped PDFs in a single pass for each row in the table that is missing a page
+// reference, showing the progress of the search, then presents the best candidates for review before applying them.
+func FillMissingPageRefs[T gurps.NodeTypes](table *unison.Table[*ntable.Node[T]]) {
+	candidates := collectMissingPageRefs(table)
+	if len(candidates) == 0 {
+		return
+	}
+	if len(settings.PageRefSearchSources()) == 0 {
+		unison.WarningDialogWithMessage(i18n.Text("No page reference mappings"),
+			i18n.Text("Map page reference keys to PDFs before searching for page references."))
+		return
+	}
+	owner := unison.AncestorOrSelf[widget.Rebuildable](table)
+	texts := make([]string, len(candidates))
+	for i, one := range candidates {
+		texts[i] = one.name
+	}
+	hits, canceled, err := searchForPageRefs(table.Window(), texts)
+	if canceled {
+		return
+	}
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to search sourcebooks"), err)
+		return
+	}
+	for i, hit := range hits {
+		if hit != nil {
+			candidates[i].ref = hit.PageReference()
+			candidates[i].include = true
+		}
+	}
+	reviewMissingPageRefs(owner, table, candidates)
+}
+
+// searchForPageRefs runs a modal progress dialog while the mapped PDFs are searched for the texts, returning the best
+// hit for each.
+func searchForPageRefs(owner *unison.Window, texts []string) (hits []*pdf.SearchHit, canceled bool, err error) {
+	var frame unison.Rect
+	if owner != nil {
+		frame = owner.FrameRect()
+	} else {
+		frame = unison.PrimaryDisplay().Usable
+	}
+	var wnd *unison.Window
+	if wnd, err = unison.NewWindow(i18n.Text("Searching…"), unison.FloatingWindowOption(),
+		unison.NotResizableWindowOption(), unison.UndecoratedWindowOption(), unison.TransientWindowOption()); err != nil {
+		return nil, false, err
+	}
+	content := unison.NewPanel()
+	content.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ControlEdgeColor, 0,
+		unison.NewUniformInsets(1), false), unison.NewEmptyBorder(unison.NewUniformInsets(2*unison.StdHSpacing))))
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	label.Text = fmt.Sprintf(i18n.Text("Searching the mapped sourcebooks for %d items…"), len(texts))
+	content.AddChild(label)
+	progress := unison.NewProgressBar(0)
+	progress.SetLayoutData(&unison.FlexLayoutData{
+		MinSize: unison.Size{Width: 500},
+		HAlign:  unison.FillAlignment,
+		HGrab:   true,
+	})
+	content.AddChild(progress)
+	var cancelFlag atomic.Bool
+	cancelButton := unison.NewButton()
+	cancelButton.Text = i18n.Text("Cancel")
+	cancelButton.ClickCallback = func() {
+		cancelFlag.Store(true)
+		cancelButton.SetEnabled(false)
+	}
+	cancelButton.SetLayoutData(&unison.FlexLayoutData{HAlign: unison.EndAlignment})
+	content.AddChild(cancelButton)
+	wnd.SetContent(content)
+	wnd.Pack()
+	wndFrame := wnd.FrameRect()
+	frame.Y += (frame.Height - wndFrame.Height) / 3
+	frame.Height = wndFrame.Height
+	frame.X += (frame.Width - wndFrame.Width) / 2
+	frame.Width = wndFrame.Width
+	frame.Align()
+	wnd.SetFrameRect(frame)
+	wnd.ToFront()
+	go func() {
+		found, searchErr := settings.BestPageRefs(texts, func(done, total int) {
+			unison.InvokeTask(func() {
+				progress.SetMaximum(float32(total))
+				progress.SetCurrent(float32(done))
+			})
+		}, cancelFlag.Load)
+		unison.InvokeTask(func() {
+			hits = found
+			err = searchErr
+			wnd.StopModal(unison.ModalResponseOK)
+		})
+	}()
+	wnd.RunModal()
+	if cancelFlag.Load() {
+		return nil, true, nil
+	}
+	return hits, false, err
+}
+
+func collectMissingPageRefs[T gurps.NodeTypes](table *unison.Table[*ntable.Node[T]]) []*pageRefCandidate {
+	var candidates []*pageRefCandidate
+	var collect func(rows []*ntable.Node[T])
+	collect = func(rows []*ntable.Node[T]) {
+		for _, row := range rows {
+			data := row.Data()
+			if target := PageRefFor(data); target != nil && strings.TrimSpace(*target) == "" {
+				var cell gurps.CellData
+				gurps.AsNode(data).CellData(gurps.PageRefCellAlias, &cell)
+				if name := strings.TrimSpace(cell.Secondary); name != "" {
+					candidates = append(candidates, &pageRefCandidate{
+						name:   name,
+						target: target,
+					})
+				}
+			}
+			if row.CanHaveChildren() {
+				collect(row.Children())
+			}
+		}
+	}
+	collect(table.RootRows())
+	return candidates
+}
+
+func reviewMissingPageRefs[T gurps.NodeTypes](owner widget.Rebuildable, table *unison.Table[*ntable.Node[T]], candidates []*pageRefCandidate) {
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	found := 0
+	for _, one := range candidates {
+		candidate := one
+		if candidate.ref == "" {
+			continue
+		}
+		found++
+		content.AddChild(widget.NewCheckBox(nil, "", candidate.name,
+			func() unison.CheckState { return unison.CheckStateFromBool(candidate.include) },
+			func(state unison.CheckState) { candidate.include = state == unison.OnCheckState }))
+		field := widget.NewStringField(nil, "", candidate.name,
+			func() string { return candidate.ref },
+			func(s string) { candidate.ref = s })
+		field.SetMinimumTextWidthUsing("MA123, B456")
+		content.AddChild(field)
+	}
+	if found == 0 {
+		unison.WarningDialogWithMessage(i18n.Text("No suggestions"),
+			i18n.Text("None of the items missing a page reference were found in the mapped sourcebooks."))
+		return
+	}
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(content, unison.FillBehavior, unison.FillBehavior)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		SizeHint: unison.Size{Height: 400},
+		HAlign:   unison.FillAlignment,
+		VAlign:   unison.FillAlignment,
+		HGrab:    true,
+		VGrab:    true,
+	})
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	label.Text = fmt.Sprintf(i18n.Text("Review the suggested page references for %d of %d items:"), found,
+		len(candidates))
+	panel.AddChild(label)
+	panel.AddChild(scroll)
+	if unison.QuestionDialogWithPanel(panel) != unison.ModalResponseOK {
+		return
+	}
+	before := &pageRefFillList{Owner: owner}
+	after := &pageRefFillList{Owner: owner}
+	for _, one := range candidates {
+		if ref := strings.TrimSpace(one.ref); one.include && ref != "" {
+			before.List = append(before.List, &pageRefFill{Target: one.target, PageRef: *one.target})
+			after.List = append(after.List, &pageRefFill{Target: one.target, PageRef: ref})
+		}
+	}
+	if len(after.List) == 0 {
+		return
+	}
+	after.Apply()
+	if mgr := unison.UndoManagerFor(table); mgr != nil {
+		mgr.Add(&unison.UndoEdit[*pageRefFillList]{
+			ID:         unison.NextUndoID(),
+			EditName:   i18n.Text("Fill Missing Page References"),
+			UndoFunc:   func(edit pageRefFillUndoEdit) { edit.BeforeData.Apply() },
+			RedoFunc:   func(edit pageRefFillUndoEdit) { edit.AfterData.Apply() },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+}
@@ -172,7 +172,7 @@ func initSkillEditor(e *editor[*gurps.Skill, *gurps.SkillEditData], content *uni
 			wrapper.AddChild(levelField)
 		}
 	}
-	addPageRefLabelAndField(content, &e.editorData.PageRef)
+	addPageRefLabelAndField(content, &e.editorData.PageRef, func() string { return e.editorData.Name })
 	if !e.target.Container() {
 		content.AddChild(newPrereqPanel(e.target.Entity, &e.editorData.Prereq))
 		content.AddChild(newDefaultsPanel(e.target.Entity, &e.editorData.Defaults))
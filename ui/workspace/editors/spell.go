@@ -93,7 +93,7 @@ func initSpellEditor(e *editor[*gurps.Spell, *gurps.SpellEditData], content *uni
 	addNotesLabelAndField(content, &e.editorData.LocalNotes)
 	addVTTNotesLabelAndField(content, &e.editorData.VTTNotes)
 	addTagsLabelAndField(content, &e.editorData.Tags)
-	addPageRefLabelAndField(content, &e.editorData.PageRef)
+	addPageRefLabelAndField(content, &e.editorData.PageRef, func() string { return e.editorData.Name })
 	if !e.target.Container() {
 		content.AddChild(newPrereqPanel(e.target.Entity, &e.editorData.Prereq))
 		for _, wt := range weapon.AllType {
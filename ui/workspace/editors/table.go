@@ -29,6 +29,9 @@ func newTable[T gurps.NodeTypes](parent *unison.Panel, provider ntable.TableProv
 	table.InstallCmdHandlers(constants.OpenEachPageReferenceItemID,
 		func(_ any) bool { return CanOpenPageRef(table) },
 		func(_ any) { OpenEachPageRef(table) })
+	table.InstallCmdHandlers(constants.FillMissingPageReferencesItemID,
+		func(_ any) bool { return CanFillMissingPageRefs(table) },
+		func(_ any) { FillMissingPageRefs(table) })
 	table.InstallCmdHandlers(unison.DeleteItemID,
 		func(_ any) bool { return table.HasSelection() },
 		func(_ any) { ntable.DeleteSelection(table) })
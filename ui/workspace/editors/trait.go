@@ -96,7 +96,7 @@ func initTraitEditor(e *editor[*gurps.Trait, *gurps.TraitEditData], content *uni
 		ancestryPopup = addLabelAndPopup(content, i18n.Text("Ancestry"), "", choices, &e.editorData.Ancestry)
 		adjustPopupBlank(ancestryPopup, e.editorData.ContainerType != trait.Race)
 	}
-	addPageRefLabelAndField(content, &e.editorData.PageRef)
+	addPageRefLabelAndField(content, &e.editorData.PageRef, func() string { return e.editorData.Name })
 	modifiersPanel := newTraitModifiersPanel(e.target.Entity, &e.editorData.Modifiers)
 	if e.target.Container() {
 		content.AddChild(modifiersPanel)
@@ -83,7 +83,7 @@ func initTraitModifierEditor(e *editor[*gurps.TraitModifier, *gurps.TraitModifie
 		}
 	}
 	addTagsLabelAndField(content, &e.editorData.Tags)
-	addPageRefLabelAndField(content, &e.editorData.PageRef)
+	addPageRefLabelAndField(content, &e.editorData.PageRef, func() string { return e.editorData.Name })
 	if !e.target.Container() {
 		content.AddChild(newFeaturesPanel(e.target.Entity, e.target, &e.editorData.Features))
 	}
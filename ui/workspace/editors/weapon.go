@@ -41,17 +41,75 @@ func initWeaponEditor(e *editor[*gurps.Weapon, *gurps.Weapon], content *unison.P
 	addLabelAndStringField(content, i18n.Text("Fragmentation Type"), "", &e.editorData.Damage.FragmentationType)
 	switch e.editorData.Type {
 	case weapon.Melee:
-		addLabelAndStringField(content, i18n.Text("Reach"), "", &e.editorData.Reach)
-		addLabelAndStringField(content, i18n.Text("Parry Modifier"), "", &e.editorData.Parry)
-		addLabelAndStringField(content, i18n.Text("Block Modifier"), "", &e.editorData.Block)
+		addWeaponExpressionField(content, e.editorData, i18n.Text("Reach"), &e.editorData.Reach)
+		addWeaponExpressionField(content, e.editorData, i18n.Text("Parry Modifier"), &e.editorData.Parry)
+		addWeaponExpressionField(content, e.editorData, i18n.Text("Block Modifier"), &e.editorData.Block)
 	case weapon.Ranged:
-		addLabelAndStringField(content, i18n.Text("Accuracy"), "", &e.editorData.Accuracy)
-		addLabelAndStringField(content, i18n.Text("Rate of Fire"), "", &e.editorData.RateOfFire)
-		addLabelAndStringField(content, i18n.Text("Range"), "", &e.editorData.Range)
-		addLabelAndStringField(content, i18n.Text("Recoil"), "", &e.editorData.Recoil)
-		addLabelAndStringField(content, i18n.Text("Shots"), "", &e.editorData.Shots)
-		addLabelAndStringField(content, i18n.Text("Bulk"), "", &e.editorData.Bulk)
+		addWeaponExpressionField(content, e.editorData, i18n.Text("Accuracy"), &e.editorData.Accuracy)
+		addWeaponExpressionField(content, e.editorData, i18n.Text("Rate of Fire"), &e.editorData.RateOfFire)
+		addWeaponExpressionField(content, e.editorData, i18n.Text("Range"), &e.editorData.Range)
+		addWeaponExpressionField(content, e.editorData, i18n.Text("Recoil"), &e.editorData.Recoil)
+		addWeaponExpressionField(content, e.editorData, i18n.Text("Shots"), &e.editorData.Shots)
+		addAmmoPopup(content, e.editorData.Entity(), &e.editorData.AmmoID)
+		addWeaponExpressionField(content, e.editorData, i18n.Text("Bulk"), &e.editorData.Bulk)
 	}
 	content.AddChild(newDefaultsPanel(e.editorData.Entity(), &e.editorData.Defaults))
 	return nil
 }
+
+type ammoChoice struct {
+	id    string
+	title string
+}
+
+func (a ammoChoice) String() string {
+	return a.title
+}
+
+func addAmmoPopup(parent *unison.Panel, entity *gurps.Entity, fieldData *string) {
+	choices := []ammoChoice{{title: i18n.Text("None")}}
+	current := choices[0]
+	if entity != nil {
+		f := func(eqp *gurps.Equipment) bool {
+			choice := ammoChoice{id: eqp.ID.String(), title: eqp.Description()}
+			if choice.id == *fieldData {
+				current = choice
+			}
+			choices = append(choices, choice)
+			return false
+		}
+		gurps.Traverse(f, false, true, entity.CarriedEquipment...)
+		gurps.Traverse(f, false, true, entity.OtherEquipment...)
+	}
+	popup := addLabelAndPopup(parent, i18n.Text("Ammunition"),
+		i18n.Text("The equipment used to reload this weapon, such as magazines or arrows"), choices, &current)
+	popup.SelectionCallback = func(_ int, item ammoChoice) {
+		*fieldData = item.id
+		widget.MarkModified(parent)
+	}
+}
+
+// addWeaponExpressionField adds a field for a weapon stat that may contain expressions, followed by a live preview of
+// their evaluation.
+func addWeaponExpressionField(parent *unison.Panel, w *gurps.Weapon, labelText string, fieldData *string) {
+	tooltip := weaponExpressionTooltip()
+	label := widget.NewFieldLeadingLabel(labelText)
+	label.Tooltip = unison.NewTooltipWithText(tooltip)
+	parent.AddChild(label)
+	preview := widget.NewWeaponExpressionPreview(func() string { return *fieldData }, w)
+	field := widget.NewStringField(nil, "", labelText,
+		func() string { return *fieldData },
+		func(value string) {
+			*fieldData = value
+			preview.Sync()
+			widget.MarkModified(parent)
+		})
+	field.Tooltip = unison.NewTooltipWithText(tooltip)
+	parent.AddChild(field)
+	parent.AddChild(unison.NewPanel())
+	parent.AddChild(preview)
+}
+
+func weaponExpressionTooltip() string {
+	return i18n.Text("May contain expressions, which will be resolved using the character's values. The whole field is treated as an expression if it references a variable, such as \"$st*15\". Alternatively, enclose one or more expressions in braces, such as \"{$dx/4}F\". In addition to the character's variables, $skill_level, $parry_bonus, $block_bonus, $throwing_st and $min_st are available. Within braces, their leading \"$\" may be omitted.\n\nFor parry and block, the result is used as the final value, rather than as a modifier to the value derived from the skill.") + "\n\n" + gurps.EvaluatorFunctionsTooltip()
+}
@@ -68,6 +68,11 @@ func newWeaponsPanel(cmdRoot widget.Rebuildable, weaponOwner gurps.WeaponOwner,
 	return p
 }
 
+// NewWeaponsPanel creates a new panel for editing the weapons of the given type from the list.
+func NewWeaponsPanel(cmdRoot widget.Rebuildable, weaponOwner gurps.WeaponOwner, weaponType weapon.Type, weapons *[]*gurps.Weapon) *unison.Panel {
+	return newWeaponsPanel(cmdRoot, weaponOwner, weaponType, weapons).AsPanel()
+}
+
 func (p *weaponsPanel) Entity() *gurps.Entity {
 	return p.entity
 }
@@ -20,10 +20,13 @@ import (
 	"github.com/richardwilkes/gcs/v5/model/gurps/feature"
 	"github.com/richardwilkes/gcs/v5/model/gurps/measure"
 	"github.com/richardwilkes/gcs/v5/model/gurps/skill"
+	"github.com/richardwilkes/gcs/v5/res"
 	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/gcs/v5/ui/workspace/settings"
 	"github.com/richardwilkes/rpgtools/dice"
 	"github.com/richardwilkes/toolbox/i18n"
 	"github.com/richardwilkes/unison"
+	"golang.org/x/exp/slices"
 )
 
 func addNameLabelAndField(parent *unison.Panel, fieldData *string) {
@@ -34,8 +37,37 @@ func addSpecializationLabelAndField(parent *unison.Panel, fieldData *string) {
 	addLabelAndStringField(parent, i18n.Text("Specialization"), "", fieldData)
 }
 
-func addPageRefLabelAndField(parent *unison.Panel, fieldData *string) {
-	addLabelAndStringField(parent, i18n.Text("Page Reference"), gurps.PageRefTooltipText, fieldData)
+func addPageRefLabelAndField(parent *unison.Panel, fieldData *string, searchText func() string) {
+	labelText := i18n.Text("Page Reference")
+	wrapper := addFlowWrapper(parent, labelText, 3)
+	wrapper.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	field := addStringField(wrapper, labelText, gurps.PageRefTooltipText, fieldData)
+	field.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		VAlign: unison.MiddleAlignment,
+		HGrab:  true,
+	})
+	searchButton := unison.NewSVGButton(res.SearchSVG)
+	searchButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Search the sourcebooks for this item"))
+	searchButton.ClickCallback = func() {
+		settings.ShowPDFSearch(searchText(), func(ref string) {
+			if field.Window() != nil {
+				field.SetText(settings.AppendPageRef(field.Text(), ref))
+			}
+		})
+	}
+	wrapper.AddChild(searchButton)
+	suggestButton := unison.NewSVGButton(res.SignPostSVG)
+	suggestButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Suggest page references for this item"))
+	suggestButton.ClickCallback = func() {
+		SuggestPageRef(suggestButton, searchText(), func(ref string) {
+			field.SetText(settings.AppendPageRef(field.Text(), ref))
+		})
+	}
+	wrapper.AddChild(suggestButton)
 }
 
 func addNotesLabelAndField(parent *unison.Panel, fieldData *string) {
@@ -251,6 +283,20 @@ func addWeightField(parent *unison.Panel, targetMgr *widget.TargetMgr, targetKey
 	return field
 }
 
+func addVolumeField(parent *unison.Panel, targetMgr *widget.TargetMgr, targetKey, labelText, tooltip string, entity *gurps.Entity, fieldData *measure.Volume, noMinWidth bool) *widget.VolumeField {
+	field := widget.NewVolumeField(targetMgr, targetKey, labelText, entity,
+		func() measure.Volume { return *fieldData },
+		func(value measure.Volume) {
+			*fieldData = value
+			widget.MarkModified(parent)
+		}, 0, measure.Volume(fxp.Max), noMinWidth)
+	if tooltip != "" {
+		field.Tooltip = unison.NewTooltipWithText(tooltip)
+	}
+	parent.AddChild(field)
+	return field
+}
+
 func addCheckBox(parent *unison.Panel, labelText string, fieldData *bool) *widget.CheckBox {
 	checkBox := widget.NewCheckBox(nil, "", labelText,
 		func() unison.CheckState { return unison.CheckStateFromBool(*fieldData) },
@@ -422,15 +468,37 @@ func addStringCriteriaPanel(parent *unison.Panel, prefix, notPrefix, undoTitle s
 	popup.SelectionCallback = func(index int, _ string) {
 		strCriteria.Compare = criteria.AllStringCompareTypes[index]
 		adjustFieldBlank(criteriaField, strCriteria.Compare == criteria.Any)
+		adjustStringCriteriaField(criteriaField, strCriteria)
 		widget.MarkModified(panel)
 	}
 	panel.AddChild(popup)
 	criteriaField = addStringField(panel, undoTitle, "", &strCriteria.Qualifier)
+	criteriaField.ValidateCallback = func() bool {
+		if strCriteria.Compare.UsesRegex() {
+			_, err := criteria.CompileRegex(criteriaField.Text())
+			return err == nil
+		}
+		return true
+	}
 	adjustFieldBlank(criteriaField, strCriteria.Compare == criteria.Any)
+	adjustStringCriteriaField(criteriaField, strCriteria)
 	parent.AddChild(panel)
 	return popup, criteriaField
 }
 
+func adjustStringCriteriaField(field *widget.StringField, strCriteria *criteria.String) {
+	switch {
+	case strCriteria.Compare.UsesRegex():
+		field.Tooltip = unison.NewTooltipWithText(i18n.Text("A regular expression, which is matched without regard to case"))
+	case strCriteria.Compare.UsesList():
+		field.Tooltip = unison.NewTooltipWithText(fmt.Sprintf(i18n.Text(`A list of choices separated by "%s", e.g. "Broadsword%s Shortsword"`),
+			criteria.OneOfSeparator, criteria.OneOfSeparator))
+	default:
+		field.Tooltip = nil
+	}
+	field.Validate()
+}
+
 func addLevelCriteriaPanel(parent *unison.Panel, targetMgr *widget.TargetMgr, targetKey string, numCriteria *criteria.Numeric, hSpan int, includeEmptyFiller bool) {
 	addNumericCriteriaPanel(parent, targetMgr, targetKey, i18n.Text("and whose level"), i18n.Text("Level Qualifier"),
 		numCriteria, 0, fxp.Thousand, hSpan, false, includeEmptyFiller)
@@ -442,7 +510,7 @@ func addNumericCriteriaPanel(parent *unison.Panel, targetMgr *widget.TargetMgr,
 	}
 	panel := unison.NewPanel()
 	panel.SetLayout(&unison.FlexLayout{
-		Columns:  2,
+		Columns:  4,
 		HSpacing: unison.StdHSpacing,
 		VSpacing: unison.StdVSpacing,
 		VAlign:   unison.MiddleAlignment,
@@ -452,7 +520,8 @@ func addNumericCriteriaPanel(parent *unison.Panel, targetMgr *widget.TargetMgr,
 		HAlign: unison.FillAlignment,
 		HGrab:  true,
 	})
-	var field unison.Paneler
+	var field, upperField unison.Paneler
+	andLabel := widget.NewFieldInteriorLeadingLabel("")
 	popup := unison.NewPopupMenu[string]()
 	for _, one := range criteria.PrefixedNumericCompareTypeChoices(prefix) {
 		popup.AddItem(one)
@@ -461,24 +530,53 @@ func addNumericCriteriaPanel(parent *unison.Panel, targetMgr *widget.TargetMgr,
 	popup.SelectionCallback = func(index int, _ string) {
 		numCriteria.Compare = criteria.AllNumericCompareTypes[index]
 		adjustFieldBlank(field, numCriteria.Compare == criteria.AnyNumber)
+		adjustUpperCriteriaField(andLabel, upperField, numCriteria.Compare)
 		widget.MarkModified(panel)
 	}
 	panel.AddChild(popup)
 	if integerOnly {
-		field = widget.NewIntegerField(targetMgr, targetKey, undoTitle,
-			func() int { return fxp.As[int](numCriteria.Qualifier) },
-			func(value int) {
-				numCriteria.Qualifier = fxp.From(value)
-				widget.MarkModified(panel)
-			}, fxp.As[int](min), fxp.As[int](max), false, false)
-		panel.AddChild(field)
+		field = addIntegerCriteriaField(panel, targetMgr, targetKey, undoTitle, &numCriteria.Qualifier, min, max)
 	} else {
 		field = addDecimalField(panel, targetMgr, targetKey, undoTitle, "", &numCriteria.Qualifier, min, max)
 	}
+	panel.AddChild(andLabel)
+	var upperTargetKey string
+	if targetKey != "" {
+		upperTargetKey = targetKey + ".upper"
+	}
+	if integerOnly {
+		upperField = addIntegerCriteriaField(panel, targetMgr, upperTargetKey, undoTitle, &numCriteria.Upper, min, max)
+	} else {
+		upperField = addDecimalField(panel, targetMgr, upperTargetKey, undoTitle, "", &numCriteria.Upper, min, max)
+	}
 	adjustFieldBlank(field, numCriteria.Compare == criteria.AnyNumber)
+	adjustUpperCriteriaField(andLabel, upperField, numCriteria.Compare)
 	parent.AddChild(panel)
 }
 
+func addIntegerCriteriaField(parent *unison.Panel, targetMgr *widget.TargetMgr, targetKey, undoTitle string, fieldData *fxp.Int, min, max fxp.Int) *widget.IntegerField {
+	field := widget.NewIntegerField(targetMgr, targetKey, undoTitle,
+		func() int { return fxp.As[int](*fieldData) },
+		func(value int) {
+			*fieldData = fxp.From(value)
+			widget.MarkModified(parent)
+		}, fxp.As[int](min), fxp.As[int](max), false, false)
+	parent.AddChild(field)
+	return field
+}
+
+// adjustUpperCriteriaField adjusts the upper end of a numeric criteria range, which is only used by the "between"
+// comparison.
+func adjustUpperCriteriaField(andLabel *unison.Label, upperField unison.Paneler, compare criteria.NumericCompareType) {
+	if compare == criteria.Between {
+		andLabel.Text = i18n.Text("and")
+	} else {
+		andLabel.Text = ""
+	}
+	andLabel.MarkForLayoutAndRedraw()
+	adjustFieldBlank(upperField, compare != criteria.Between)
+}
+
 func addWeightCriteriaPanel(parent *unison.Panel, targetMgr *widget.TargetMgr, targetKey string, entity *gurps.Entity, weightCriteria *criteria.Weight) {
 	popup := unison.NewPopupMenu[string]()
 	for _, one := range criteria.PrefixedNumericCompareTypeChoices(i18n.Text("which")) {
@@ -488,12 +586,22 @@ func addWeightCriteriaPanel(parent *unison.Panel, targetMgr *widget.TargetMgr, t
 	parent.AddChild(popup)
 	field := addWeightField(parent, targetMgr, targetKey, i18n.Text("Weight Qualifier"), "", entity,
 		&weightCriteria.Qualifier, false)
+	andLabel := widget.NewFieldInteriorLeadingLabel("")
+	parent.AddChild(andLabel)
+	var upperTargetKey string
+	if targetKey != "" {
+		upperTargetKey = targetKey + ".upper"
+	}
+	upperField := addWeightField(parent, targetMgr, upperTargetKey, i18n.Text("Weight Qualifier"), "", entity,
+		&weightCriteria.Upper, false)
 	popup.SelectionCallback = func(index int, _ string) {
 		weightCriteria.Compare = criteria.AllNumericCompareTypes[index]
 		adjustFieldBlank(field, weightCriteria.Compare == criteria.AnyNumber)
+		adjustUpperCriteriaField(andLabel, upperField, weightCriteria.Compare)
 		widget.MarkModified(parent)
 	}
 	adjustFieldBlank(field, weightCriteria.Compare == criteria.AnyNumber)
+	adjustUpperCriteriaField(andLabel, upperField, weightCriteria.Compare)
 	parent.SetLayout(&unison.FlexLayout{
 		Columns:  len(parent.Children()),
 		HSpacing: unison.StdHSpacing,
@@ -550,3 +658,28 @@ func addLeveledAmountPanel(parent *unison.Panel, targetMgr *widget.TargetMgr, ta
 		}, fxp.Min, fxp.Max, true, false))
 	addCheckBox(parent, title, &amount.PerLevel)
 }
+
+func addCurrencyPopup(parent *unison.Panel, fieldData *string) *unison.PopupMenu[string] {
+	choices := gurps.Currencies().IDs()
+	if *fieldData != "" && !slices.Contains(choices, *fieldData) {
+		choices = append(choices, *fieldData)
+	}
+	if len(choices) == 0 {
+		choices = append(choices, "")
+	}
+	current := *fieldData
+	if current == "" {
+		current = choices[0]
+	}
+	popup := addPopup(parent, choices, &current)
+	popup.SelectionCallback = func(index int, item string) {
+		if index == 0 {
+			// The first currency is the base currency, which is stored as an empty string
+			item = ""
+		}
+		*fieldData = item
+		widget.MarkModified(parent)
+	}
+	popup.Tooltip = unison.NewTooltipWithText(i18n.Text("Currency"))
+	return popup
+}
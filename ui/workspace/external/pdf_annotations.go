@@ -0,0 +1,266 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package external
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/settings"
+	"github.com/richardwilkes/gcs/v5/model/theme"
+	"github.com/richardwilkes/gcs/v5/res"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+// pageRef returns the page reference mapping for this PDF, or nil if it hasn't been mapped to a key. Annotations can
+// only be stored for mapped PDFs, since they are kept with the mapping. The result is cached until the page references
+// change, since this is called each time the page is drawn.
+func (d *PDFDockable) pageRef() *settings.PageRef {
+	d.resolvePageRef()
+	return d.cachedPageRef
+}
+
+// pageRefOffset returns the offset that applies to printed page numbers within this PDF, or 0 if it hasn't been mapped
+// to a key.
+func (d *PDFDockable) pageRefOffset() int {
+	d.resolvePageRef()
+	return d.cachedPageRefOffset
+}
+
+func (d *PDFDockable) resolvePageRef() {
+	refs := &settings.Global().PageRefs
+	if d.pageRefCacheValid && d.pageRefGeneration == refs.Generation() {
+		return
+	}
+	d.cachedPageRef = refs.LookupByPath(d.path)
+	d.cachedPageRefOffset = 0
+	if d.cachedPageRef != nil {
+		d.cachedPageRefOffset = d.cachedPageRef.OffsetFor(d.path)
+	}
+	d.pageRefGeneration = refs.Generation()
+	d.pageRefCacheValid = true
+}
+
+// invalidatePageRef discards the cached page reference mapping.
+func (d *PDFDockable) invalidatePageRef() {
+	d.pageRefCacheValid = false
+	d.cachedPageRef = nil
+}
+
+// annotationPath returns the path to record in annotations made within this PDF. The primary file of a mapping is
+// recorded as an empty path, so that the annotations follow it if the mapping is pointed at a different copy.
+func (d *PDFDockable) annotationPath(ref *settings.PageRef) string {
+	if filepath.Clean(ref.Path) == filepath.Clean(d.path) {
+		return ""
+	}
+	return d.path
+}
+
+// isForThisPDF returns true if the annotation path refers to this PDF.
+func (d *PDFDockable) isForThisPDF(ref *settings.PageRef, annotationPath string) bool {
+	if annotationPath == "" {
+		annotationPath = ref.Path
+	}
+	return filepath.Clean(annotationPath) == filepath.Clean(d.path)
+}
+
+func (d *PDFDockable) createAnnotations() {
+	d.annotationsPanel = unison.NewPanel()
+	d.annotationsPanel.SetBorder(unison.NewEmptyBorder(unison.StdInsets()))
+	d.annotationsPanel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	d.annotationsScroll = unison.NewScrollPanel()
+	d.annotationsScroll.SetLayoutData(&unison.FlexLayoutData{
+		SizeHint: unison.Size{Height: 150},
+		HAlign:   unison.FillAlignment,
+		VAlign:   unison.FillAlignment,
+		HGrab:    true,
+	})
+	d.annotationsScroll.SetContent(d.annotationsPanel, unison.FillBehavior, unison.UnmodifiedBehavior)
+}
+
+func (d *PDFDockable) syncAnnotations() {
+	d.annotationsPanel.RemoveAllChildren()
+	ref := d.pageRef()
+	if ref == nil {
+		label := unison.NewLabel()
+		label.Text = i18n.Text("Map this PDF to a page reference key to add bookmarks and highlights")
+		label.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+		d.annotationsPanel.AddChild(label)
+	} else {
+		d.sideBarButton.SetEnabled(true)
+		for _, one := range ref.Bookmarks {
+			if !d.isForThisPDF(ref, one.Path) {
+				continue
+			}
+			bookmark := one
+			d.addAnnotationRow(fmt.Sprintf(i18n.Text("%s (%s%s%s)"), bookmark.Name, ref.ID,
+				settings.PageRefBookmarkSeparator, strings.ReplaceAll(bookmark.Name, " ", "_")), bookmark.PageNumber,
+				func(r *settings.PageRef) {
+					for i, b := range r.Bookmarks {
+						if b.Name == bookmark.Name && b.Path == bookmark.Path && b.PageNumber == bookmark.PageNumber {
+							r.Bookmarks = append(r.Bookmarks[:i], r.Bookmarks[i+1:]...)
+							break
+						}
+					}
+				})
+		}
+		for i, one := range ref.Highlights {
+			if !d.isForThisPDF(ref, one.Path) {
+				continue
+			}
+			index := i
+			text := one.Note
+			if text == "" {
+				text = one.Text
+			}
+			title := fmt.Sprintf(i18n.Text("Highlight: %s (page %d)"), text, one.PageNumber+1)
+			if page := one.PageNumber + 1 - d.pageRefOffset(); page > 0 {
+				title = fmt.Sprintf(i18n.Text("Highlight: %s (%s%d)"), text, ref.ID, page)
+			}
+			d.addAnnotationRow(title, one.PageNumber,
+				func(r *settings.PageRef) {
+					if index < len(r.Highlights) {
+						r.Highlights = append(r.Highlights[:index], r.Highlights[index+1:]...)
+					}
+				})
+		}
+	}
+	d.annotationsPanel.MarkForLayoutAndRedraw()
+	d.annotationsScroll.MarkForLayoutAndRedraw()
+	d.syncAnnotationButtons()
+	d.docPanel.MarkForRedraw()
+}
+
+func (d *PDFDockable) addAnnotationRow(title string, pageNumber int, remover func(r *settings.PageRef)) {
+	label := unison.NewLabel()
+	label.Text = title
+	label.Tooltip = unison.NewTooltipWithText(fmt.Sprintf(i18n.Text("Go to page %d"), pageNumber+1))
+	label.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		VAlign: unison.MiddleAlignment,
+		HGrab:  true,
+	})
+	label.MouseDownCallback = func(_ unison.Point, _, _ int, _ unison.Modifiers) bool {
+		d.LoadPage(pageNumber)
+		return true
+	}
+	d.annotationsPanel.AddChild(label)
+	b := unison.NewSVGButton(res.TrashSVG)
+	b.Tooltip = unison.NewTooltipWithText(i18n.Text("Remove"))
+	b.ClickCallback = func() {
+		if ref := d.pageRef(); ref != nil {
+			remover(ref)
+			settings.Global().PageRefs.Set(ref)
+			d.syncAnnotations()
+		}
+	}
+	d.annotationsPanel.AddChild(b)
+}
+
+func (d *PDFDockable) syncAnnotationButtons() {
+	mapped := d.pageRef() != nil
+	d.bookmarkButton.SetEnabled(mapped)
+	d.highlightButton.SetEnabled(mapped && d.page != nil && len(d.page.Matches) != 0)
+}
+
+func (d *PDFDockable) addBookmark() {
+	ref := d.pageRef()
+	if ref == nil || d.page == nil {
+		return
+	}
+	pageNumber := d.page.PageNumber
+	name := strings.TrimSpace(d.searchField.Text())
+	if name == "" {
+		name = fmt.Sprintf(i18n.Text("Page %d"), pageNumber+1)
+	}
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	nameTitle := i18n.Text("Bookmark Name")
+	panel.AddChild(widget.NewFieldLeadingLabel(nameTitle))
+	field := widget.NewStringField(nil, "", nameTitle,
+		func() string { return name },
+		func(s string) { name = s })
+	field.SetMinimumTextWidthUsing("All-Out Attack (Determined)")
+	field.Tooltip = unison.NewTooltipWithText(fmt.Sprintf(i18n.Text(`Page references of the form "%s%sName" will open this page`),
+		ref.ID, settings.PageRefBookmarkSeparator))
+	panel.AddChild(field)
+	if unison.QuestionDialogWithPanel(panel) != unison.ModalResponseOK {
+		return
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		return
+	}
+	if existing := ref.Bookmark(name); existing != nil {
+		existing.Path = d.annotationPath(ref)
+		existing.PageNumber = pageNumber
+	} else {
+		ref.Bookmarks = append(ref.Bookmarks, &settings.PDFBookmark{
+			Name:       name,
+			Path:       d.annotationPath(ref),
+			PageNumber: pageNumber,
+		})
+	}
+	settings.Global().PageRefs.Set(ref)
+	d.syncAnnotations()
+}
+
+func (d *PDFDockable) addHighlight() {
+	ref := d.pageRef()
+	if ref == nil || d.page == nil || len(d.page.Matches) == 0 {
+		return
+	}
+	scale := float32(d.scale) / 100
+	highlight := &settings.PDFHighlight{
+		Path:       d.annotationPath(ref),
+		PageNumber: d.page.PageNumber,
+		Text:       strings.TrimSpace(d.searchField.Text()),
+		Areas:      make([]settings.PDFArea, 0, len(d.page.Matches)),
+	}
+	for _, match := range d.page.Matches {
+		highlight.Areas = append(highlight.Areas, settings.NewPDFArea(match, scale))
+	}
+	ref.Highlights = append(ref.Highlights, highlight)
+	settings.Global().PageRefs.Set(ref)
+	d.syncAnnotations()
+}
+
+func (d *PDFDockable) drawHighlights(gc *unison.Canvas) {
+	ref := d.pageRef()
+	if ref == nil {
+		return
+	}
+	highlights := ref.HighlightsForPage(d.path, d.page.PageNumber)
+	if len(highlights) == 0 {
+		return
+	}
+	scale := float32(d.scale) / 100
+	p := unison.NewPaint()
+	p.SetStyle(unison.Fill)
+	p.SetBlendMode(unison.ModulateBlendMode)
+	p.SetColor(theme.PDFMarkerHighlightColor.GetColor())
+	for _, one := range highlights {
+		for _, area := range one.Areas {
+			gc.DrawRect(area.Rect(scale), p)
+		}
+	}
+}
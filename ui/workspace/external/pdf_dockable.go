@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/gcs/v5/model/settings"
 	"github.com/richardwilkes/gcs/v5/model/theme"
 	"github.com/richardwilkes/gcs/v5/pdf"
 	"github.com/richardwilkes/gcs/v5/res"
@@ -52,7 +53,10 @@ type PDFDockable struct {
 	docScroll              *unison.ScrollPanel
 	docPanel               *unison.Panel
 	tocScroll              *unison.ScrollPanel
+	annotationsScroll      *unison.ScrollPanel
 	tocPanel               *unison.Table[*tocNode]
+	sideBar                *unison.Panel
+	annotationsPanel       *unison.Panel
 	divider                *unison.Panel
 	tocScrollLayoutData    *unison.FlexLayoutData
 	pageNumberField        *unison.Field
@@ -60,6 +64,8 @@ type PDFDockable struct {
 	searchField            *unison.Field
 	matchesLabel           *unison.Label
 	sideBarButton          *unison.Button
+	bookmarkButton         *unison.Button
+	highlightButton        *unison.Button
 	backButton             *unison.Button
 	forwardButton          *unison.Button
 	firstPageButton        *unison.Button
@@ -72,6 +78,10 @@ type PDFDockable struct {
 	scale                  int
 	historyPos             int
 	history                []int
+	cachedPageRef          *settings.PageRef
+	cachedPageRefOffset    int
+	pageRefGeneration      uint64
+	pageRefCacheValid      bool
 	noUpdate               bool
 	adjustTableSizePending bool
 }
@@ -102,6 +112,7 @@ func NewPDFDockable(filePath string) (unison.Dockable, error) {
 	d.AddChild(d.toolbar)
 	d.AddChild(d.content)
 
+	d.syncAnnotations()
 	d.noUpdate = false
 	d.LoadPage(0)
 
@@ -221,6 +232,18 @@ func (d *PDFDockable) createToolbar() {
 	d.matchesLabel.Tooltip = unison.NewTooltipWithText(i18n.Text("Number of matches found"))
 	d.toolbar.AddChild(d.matchesLabel)
 
+	d.toolbar.AddChild(widget.NewToolbarSeparator(unison.StdHSpacing))
+
+	d.bookmarkButton = unison.NewSVGButton(res.BookmarkSVG)
+	d.bookmarkButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Bookmark this page"))
+	d.bookmarkButton.ClickCallback = d.addBookmark
+	d.toolbar.AddChild(d.bookmarkButton)
+
+	d.highlightButton = unison.NewSVGButton(res.CircledAddSVG)
+	d.highlightButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Save the search matches on this page as a highlight"))
+	d.highlightButton.ClickCallback = d.addHighlight
+	d.toolbar.AddChild(d.highlightButton)
+
 	d.toolbar.SetLayout(&unison.FlexLayout{
 		Columns:  len(d.toolbar.Children()),
 		HSpacing: unison.StdHSpacing,
@@ -234,14 +257,30 @@ func (d *PDFDockable) createTOC() {
 	d.tocPanel.SelectionChangedCallback = d.tocSelectionChanged
 
 	d.tocScroll = unison.NewScrollPanel()
+	d.tocScroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		VAlign: unison.FillAlignment,
+		HGrab:  true,
+		VGrab:  true,
+	})
+	d.tocScroll.SetContent(d.tocPanel, unison.FillBehavior, unison.FillBehavior)
+
+	d.createAnnotations()
+
+	d.sideBar = unison.NewPanel()
+	d.sideBar.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
 	d.tocScrollLayoutData = &unison.FlexLayoutData{
 		SizeHint: unison.Size{Width: 200},
 		HAlign:   unison.FillAlignment,
 		VAlign:   unison.FillAlignment,
 		VGrab:    true,
 	}
-	d.tocScroll.SetLayoutData(d.tocScrollLayoutData)
-	d.tocScroll.SetContent(d.tocPanel, unison.FillBehavior, unison.FillBehavior)
+	d.sideBar.SetLayoutData(d.tocScrollLayoutData)
+	d.sideBar.AddChild(d.tocScroll)
+	d.sideBar.AddChild(d.annotationsScroll)
 
 	d.divider = unison.NewPanel()
 	d.divider.SetLayoutData(&unison.FlexLayoutData{
@@ -326,12 +365,12 @@ func (d *PDFDockable) toggleSideBar() {
 	if layout, ok := d.content.Layout().(*unison.FlexLayout); ok {
 		if layout.Columns == 1 {
 			layout.Columns = 3
-			d.content.AddChildAtIndex(d.tocScroll, 0)
+			d.content.AddChildAtIndex(d.sideBar, 0)
 			d.content.AddChildAtIndex(d.divider, 1)
 		} else {
 			layout.Columns = 1
 			d.divider.RemoveFromParent()
-			d.tocScroll.RemoveFromParent()
+			d.sideBar.RemoveFromParent()
 		}
 		d.content.MarkForLayoutAndRedraw()
 	}
@@ -409,6 +448,7 @@ func (d *PDFDockable) pageLoaded() {
 			d.history = append(d.history, pageNumber)
 		}
 	}
+	d.syncAnnotationButtons()
 	lastPageNumber := d.pdf.PageCount() - 1
 	d.backButton.SetEnabled(d.historyPos > 0)
 	d.forwardButton.SetEnabled(d.historyPos < len(d.history)-1)
@@ -542,6 +582,7 @@ func (d *PDFDockable) draw(gc *unison.Canvas, dirty unison.Rect) {
 				gc.DrawRect(match, p)
 			}
 		}
+		d.drawHighlights(gc)
 		if d.link != nil {
 			p := unison.NewPaint()
 			p.SetStyle(unison.Fill)
@@ -643,6 +684,7 @@ func (d *PDFDockable) BackingFilePath() string {
 // SetBackingFilePath implements workspace.FileBackedDockable
 func (d *PDFDockable) SetBackingFilePath(p string) {
 	d.path = p
+	d.invalidatePageRef()
 	if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
 		dc.UpdateTitle(d)
 	}
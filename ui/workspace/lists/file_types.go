@@ -23,6 +23,7 @@ import (
 func RegisterFileTypes() {
 	registerExportableGCSFileInfo("GCS Sheet", library.SheetExt, res.GCSSheetSVG, sheet.NewSheetFromFile)
 	registerGCSFileInfo("GCS Template", library.TemplatesExt, []string{library.TemplatesExt}, res.GCSTemplateSVG, sheet.NewTemplateFromFile)
+	registerGCSFileInfo("GCS Vehicle", library.VehiclesExt, []string{library.VehiclesExt}, res.GCSVehicleSVG, sheet.NewVehicleFromFile)
 	groupWith := []string{
 		library.TraitsExt,
 		library.TraitModifiersExt,
@@ -39,6 +40,17 @@ func RegisterFileTypes() {
 	registerGCSFileInfo("GCS Skills", library.SkillsExt, groupWith, res.GCSSkillsSVG, NewSkillTableDockableFromFile)
 	registerGCSFileInfo("GCS Spells", library.SpellsExt, groupWith, res.GCSSpellsSVG, NewSpellTableDockableFromFile)
 	registerGCSFileInfo("GCS Notes", library.NotesExt, groupWith, res.GCSNotesSVG, NewNoteTableDockableFromFile)
+	library.FileInfo{
+		Name:       "GCS Portable Bundle",
+		UTI:        cmdline.AppIdentifier + library.BundleExt,
+		ConformsTo: []string{"public.zip-archive"},
+		Extensions: []string{library.BundleExt},
+		GroupWith:  []string{library.BundleExt},
+		MimeTypes:  []string{"application/x-gcs-" + library.BundleExt[1:]},
+		SVG:        res.GCSSheetSVG,
+		Load:       sheet.NewSheetFromBundleFile,
+	}.Register()
+	registerImportableFileInfo("GCA Character", []string{library.GCA4Ext, library.GCA5Ext}, res.GCAFileSVG, sheet.NewSheetFromGCAFile)
 }
 
 func registerGCSFileInfo(name, ext string, groupWith []string, svg *unison.SVG, loader func(filePath string) (unison.Dockable, error)) {
@@ -69,3 +81,20 @@ func registerExportableGCSFileInfo(name, ext string, svg *unison.SVG, loader fun
 		IsExportable: true,
 	}.Register()
 }
+
+func registerImportableFileInfo(name string, extensions []string, svg *unison.SVG, loader func(filePath string) (unison.Dockable, error)) {
+	mimeTypes := make([]string, 0, len(extensions))
+	for _, ext := range extensions {
+		mimeTypes = append(mimeTypes, "application/x-"+ext[1:])
+	}
+	library.FileInfo{
+		Name:       name,
+		UTI:        cmdline.AppIdentifier + ".import" + extensions[0],
+		ConformsTo: []string{"public.xml"},
+		Extensions: extensions,
+		GroupWith:  extensions,
+		MimeTypes:  mimeTypes,
+		SVG:        svg,
+		Load:       loader,
+	}.Register()
+}
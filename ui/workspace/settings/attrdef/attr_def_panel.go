@@ -163,12 +163,18 @@ func (p *attrDefPanel) createContent() *unison.Panel {
 
 		text = i18n.Text("Base Value")
 		content.AddChild(widget.NewFieldLeadingLabel(text))
+		preview := widget.NewExpressionPreview(func() string { return p.def.AttributeBase }, p.dockable.resolver)
 		field = widget.NewStringField(p.dockable.targetMgr, p.def.KeyPrefix+"base", text,
 			func() string { return p.def.AttributeBase },
-			func(s string) { p.def.AttributeBase = s })
+			func(s string) {
+				p.def.AttributeBase = s
+				preview.Sync()
+			})
 		field.SetMinimumTextWidthUsing("floor($basic_speed)")
-		field.Tooltip = unison.NewTooltipWithText(i18n.Text("The base value, which may be a number or a formula"))
+		field.Tooltip = unison.NewTooltipWithText(i18n.Text("The base value, which may be a number or a formula") + "\n\n" + gurps.EvaluatorFunctionsTooltip())
 		content.AddChild(field)
+		content.AddChild(unison.NewPanel())
+		content.AddChild(preview)
 
 		text = i18n.Text("Cost per Point")
 		content.AddChild(widget.NewFieldLeadingLabel(text))
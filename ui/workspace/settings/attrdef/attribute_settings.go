@@ -12,6 +12,7 @@ import (
 	"github.com/richardwilkes/gcs/v5/ui/widget"
 	"github.com/richardwilkes/gcs/v5/ui/workspace"
 	wsettings "github.com/richardwilkes/gcs/v5/ui/workspace/settings"
+	"github.com/richardwilkes/toolbox/eval"
 	"github.com/richardwilkes/toolbox/i18n"
 	"github.com/richardwilkes/toolbox/log/jot"
 	"github.com/richardwilkes/unison"
@@ -32,6 +33,8 @@ type attributesDockable struct {
 	targetMgr       *widget.TargetMgr
 	undoMgr         *unison.UndoManager
 	defs            *gurps.AttributeDefs
+	scratch         *gurps.Entity
+	scratchCRC      uint64
 	originalCRC     uint64
 	toolbar         *unison.Panel
 	content         *unison.Panel
@@ -205,6 +208,21 @@ func (d *attributesDockable) Entity() *gurps.Entity {
 	return nil
 }
 
+// resolver returns an entity that uses the attribute definitions being edited, for previewing expressions. The entity
+// is only recalculated when the definitions have changed since it was last used.
+func (d *attributesDockable) resolver() eval.VariableResolver {
+	crc := d.defs.CRC64()
+	if d.scratch == nil {
+		d.scratch = gurps.NewScratchEntity(d.Entity())
+	} else if crc == d.scratchCRC {
+		return d.scratch
+	}
+	d.scratch.SetAttributeDefs(d.defs)
+	d.scratch.Recalculate()
+	d.scratchCRC = crc
+	return d.scratch
+}
+
 func (d *attributesDockable) applyAttrDefs(defs *gurps.AttributeDefs) {
 	d.defs = defs.Clone()
 	d.sync()
@@ -277,19 +295,7 @@ func (d *attributesDockable) apply() {
 		return
 	}
 	entity := d.owner.Entity()
-	entity.SheetSettings.Attributes = d.defs.Clone()
-	for attrID, def := range entity.SheetSettings.Attributes.Set {
-		if attr, exists := entity.Attributes.Set[attrID]; exists {
-			attr.Order = def.Order
-		} else {
-			entity.Attributes.Set[attrID] = gurps.NewAttribute(entity, attrID, def.Order)
-		}
-	}
-	for attrID := range entity.Attributes.Set {
-		if _, exists := d.defs.Set[attrID]; !exists {
-			delete(entity.Attributes.Set, attrID)
-		}
-	}
+	entity.SetAttributeDefs(d.defs.Clone())
 	for _, wnd := range unison.Windows() {
 		if ws := workspace.FromWindow(wnd); ws != nil {
 			ws.DocumentDock.RootDockLayout().ForEachDockContainer(func(dc *unison.DockContainer) bool {
@@ -98,12 +98,18 @@ func (p *thresholdPanel) createContent() *unison.Panel {
 
 	text = i18n.Text("Threshold")
 	content.AddChild(widget.NewFieldLeadingLabel(text))
+	preview := widget.NewExpressionPreview(func() string { return p.threshold.Expression }, p.pool.dockable.resolver)
 	field = widget.NewStringField(p.pool.dockable.targetMgr, p.threshold.KeyPrefix+"threshold", text,
 		func() string { return p.threshold.Expression },
-		func(s string) { p.threshold.Expression = s })
+		func(s string) {
+			p.threshold.Expression = s
+			preview.Sync()
+		})
 	field.SetMinimumTextWidthUsing("round($self*100/50+20)")
-	field.Tooltip = unison.NewTooltipWithText(i18n.Text("An expression to calculate the threshold value"))
+	field.Tooltip = unison.NewTooltipWithText(i18n.Text("An expression to calculate the threshold value") + "\n\n" + gurps.EvaluatorFunctionsTooltip())
 	content.AddChild(field)
+	content.AddChild(unison.NewPanel())
+	content.AddChild(preview)
 
 	for _, op := range attribute.AllThresholdOp[1:] {
 		content.AddChild(unison.NewPanel())
@@ -38,6 +38,7 @@ type generalSettingsDockable struct {
 	pointsField                   *widget.DecimalField
 	techLevelField                *widget.StringField
 	calendarPopup                 *unison.PopupMenu[string]
+	currencyPopup                 *unison.PopupMenu[string]
 	initialListScaleField         *widget.PercentageField
 	initialSheetScaleField        *widget.PercentageField
 	exportResolutionField         *widget.IntegerField
@@ -78,6 +79,7 @@ func (d *generalSettingsDockable) initContent(content *unison.Panel) {
 	d.createInitialPointsFields(content)
 	d.createTechLevelField(content)
 	d.createCalendarPopup(content)
+	d.createCurrencyPopup(content)
 	initialListScaleTitle := i18n.Text("Initial List Scale")
 	content.AddChild(widget.NewFieldLeadingLabel(initialListScaleTitle))
 	d.initialListScaleField = widget.NewPercentageField(nil, "", initialListScaleTitle,
@@ -179,6 +181,24 @@ func (d *generalSettingsDockable) createCalendarPopup(content *unison.Panel) {
 	content.AddChild(d.calendarPopup)
 }
 
+func (d *generalSettingsDockable) createCurrencyPopup(content *unison.Panel) {
+	content.AddChild(widget.NewFieldLeadingLabel(i18n.Text("Currencies")))
+	d.currencyPopup = unison.NewPopupMenu[string]()
+	libraries := settings.Global().Libraries()
+	for _, lib := range gsettings.AvailableCurrencyRefs(libraries) {
+		d.currencyPopup.AddDisabledItem(lib.Name)
+		for _, one := range lib.List {
+			d.currencyPopup.AddItem(one.Name)
+		}
+	}
+	d.currencyPopup.Select(settings.Global().General.CurrencyRef(libraries).Name)
+	d.currencyPopup.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+	d.currencyPopup.SelectionCallback = func(_ int, item string) {
+		settings.Global().General.CurrencyName = item
+	}
+	content.AddChild(d.currencyPopup)
+}
+
 func (d *generalSettingsDockable) createImageResolutionField(content *unison.Panel) {
 	title := i18n.Text("Image Export Resolution")
 	content.AddChild(widget.NewFieldLeadingLabel(title))
@@ -273,6 +293,7 @@ func (d *generalSettingsDockable) sync() {
 	d.pointsField.SetText(s.InitialPoints.String())
 	d.techLevelField.SetText(s.DefaultTechLevel)
 	d.calendarPopup.Select(s.CalendarRef(settings.Global().Libraries()).Name)
+	d.currencyPopup.Select(s.CurrencyRef(settings.Global().Libraries()).Name)
 	widget.SetFieldValue(d.initialListScaleField.Field, d.initialListScaleField.Format(s.InitialListUIScale))
 	widget.SetFieldValue(d.initialSheetScaleField.Field, d.initialSheetScaleField.Format(s.InitialSheetUIScale))
 	d.exportResolutionField.SetText(strconv.Itoa(s.ImageResolution))
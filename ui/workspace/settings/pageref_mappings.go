@@ -47,6 +47,16 @@ func ExtractPageReferences(s string) []string {
 	return list
 }
 
+// PageReferenceKey returns the key of the page reference mapping the page reference uses, or an empty string if it
+// doesn't use one, such as for a link.
+func PageReferenceKey(ref string) string {
+	if strings.HasPrefix(strings.ToLower(ref), "http") {
+		return ""
+	}
+	key, _ := parsePageReference(ref)
+	return key
+}
+
 // OpenPageReference opens the given page reference in the given window, which should contain a workspace. May pass nil
 // for wnd to let it pick the first such window it discovers. Returns true if the the user asked to cancel further
 // processing.
@@ -60,22 +70,8 @@ func OpenPageReference(wnd *unison.Window, ref, highlight string, promptContext
 	if promptContext == nil {
 		promptContext = make(map[string]bool)
 	}
-	i := len(ref) - 1
-	for i >= 0 {
-		ch := ref[i]
-		if ch >= '0' && ch <= '9' {
-			i--
-		} else {
-			i++
-			break
-		}
-	}
-	if i > 0 {
-		page, err := strconv.Atoi(ref[i:])
-		if err != nil {
-			return false
-		}
-		key := ref[:i]
+	key, target := parsePageReference(ref)
+	if key != "" {
 		s := settings.Global()
 		pageRef := s.PageRefs.Lookup(key)
 		if pageRef == nil && !promptContext[key] {
@@ -88,17 +84,7 @@ Would you like to create one by choosing a PDF to map to this key?`), key), pdfN
 			case unison.ModalResponseDiscard:
 				promptContext[key] = true
 			case unison.ModalResponseOK:
-				dialog := unison.NewOpenDialog()
-				dialog.SetAllowsMultipleSelection(false)
-				dialog.SetResolvesAliases(true)
-				dialog.SetAllowedExtensions("pdf")
-				dialog.SetCanChooseDirectories(false)
-				dialog.SetCanChooseFiles(true)
-				global := settings.Global()
-				dialog.SetInitialDirectory(global.LastDir(settings.DefaultLastDirKey))
-				if dialog.RunModal() {
-					p := dialog.Path()
-					global.SetLastDir(settings.DefaultLastDirKey, filepath.Dir(p))
+				if p, ok := choosePageRefPDF(); ok {
 					pageRef = &settings.PageRef{
 						ID:   key,
 						Path: p,
@@ -111,19 +97,24 @@ Would you like to create one by choosing a PDF to map to this key?`), key), pdfN
 			}
 		}
 		if pageRef != nil {
+			page, ok := target(pageRef)
+			if !ok {
+				unison.ErrorDialogWithMessage(i18n.Text("Unable to open page reference"),
+					fmt.Sprintf(i18n.Text(`There is no page label or bookmark for "%s".`), ref))
+				return false
+			}
 			if strings.TrimSpace(s.General.ExternalPDFCmdLine) == "" {
-				if d, wasOpen := workspace.OpenFile(wnd, pageRef.Path); d != nil {
+				if d, wasOpen := workspace.OpenFile(wnd, page.Path); d != nil {
 					if pdfDockable, ok := d.(*external.PDFDockable); ok {
 						pdfDockable.SetSearchText(highlight)
-						pdfDockable.LoadPage(page + pageRef.Offset - 1) // The pdf package uses 0 for the first page, not 1
+						pdfDockable.LoadPage(page.PageNumber)
 						if !wasOpen {
 							pdfDockable.ClearHistory()
 						}
 					}
 				}
 			} else {
-				var parts []string
-				parts, err = cmdline.Parse(strings.TrimSpace(strings.ReplaceAll(strings.ReplaceAll(s.General.ExternalPDFCmdLine, "$FILE", "\""+pageRef.Path+"\""), "$PAGE", strconv.Itoa(page+pageRef.Offset))))
+				parts, err := cmdline.Parse(strings.TrimSpace(strings.ReplaceAll(strings.ReplaceAll(s.General.ExternalPDFCmdLine, "$FILE", "\""+page.Path+"\""), "$PAGE", strconv.Itoa(page.PageNumber+1))))
 				errTitle := i18n.Text("Unable to use external PDF command line")
 				if err != nil {
 					unison.ErrorDialogWithError(errTitle, err)
@@ -151,6 +142,80 @@ Would you like to create one by choosing a PDF to map to this key?`), key), pdfN
 	return false
 }
 
+// parsePageReference parses a page reference into its key and a function that determines the file and 0-based page
+// within it that the reference targets. References take one of these forms:
+//
+//	"B123"         a key followed by a printed page number
+//	"B123-125"     a range of pages, which targets the first page of the range
+//	"Bxii"         a key followed by a non-numeric page label, which must have been mapped
+//	"B:Grappling"  a key followed by a bookmark name
+//
+// Returns an empty key if the reference can't be parsed.
+func parsePageReference(ref string) (key string, target func(pageRef *settings.PageRef) (settings.PageTarget, bool)) {
+	if i := strings.Index(ref, settings.PageRefBookmarkSeparator); i > 0 {
+		name := ref[i+len(settings.PageRefBookmarkSeparator):]
+		return ref[:i], func(pageRef *settings.PageRef) (settings.PageTarget, bool) {
+			if bookmark := pageRef.Bookmark(name); bookmark != nil {
+				return bookmark.Target(pageRef), true
+			}
+			return settings.PageTarget{}, false
+		}
+	}
+	if i := strings.LastIndexByte(ref, '-'); i > 0 && i < len(ref)-1 && isPageLabel(ref[i+1:]) {
+		ref = ref[:i]
+	}
+	var label string
+	key, label = splitPageReference(ref)
+	if key == "" {
+		return "", nil
+	}
+	return key, func(pageRef *settings.PageRef) (settings.PageTarget, bool) {
+		return pageRef.ResolvePage(label)
+	}
+}
+
+// splitPageReference splits a page reference into its key and page label. A trailing page number is preferred, but if
+// that doesn't produce a mapped key, the longest mapped key that prefixes the reference is used, which allows for
+// non-numeric page labels.
+func splitPageReference(ref string) (key, label string) {
+	i := len(ref) - 1
+	for i >= 0 {
+		ch := ref[i]
+		if ch >= '0' && ch <= '9' {
+			i--
+		} else {
+			i++
+			break
+		}
+	}
+	if i > 0 && i < len(ref) {
+		key = ref[:i]
+		label = ref[i:]
+	}
+	pageRefs := &settings.Global().PageRefs
+	if key != "" && pageRefs.Has(key) {
+		return key, label
+	}
+	var longest string
+	for _, one := range pageRefs.List() {
+		if len(one.ID) > len(longest) && len(one.ID) < len(ref) && strings.HasPrefix(ref, one.ID) {
+			longest = one.ID
+		}
+	}
+	if longest != "" {
+		return longest, ref[len(longest):]
+	}
+	return key, label
+}
+
+// isPageLabel returns true if the text looks like a page number or roman-numeral page label.
+func isPageLabel(text string) bool {
+	if _, err := strconv.Atoi(text); err == nil {
+		return true
+	}
+	return strings.Trim(strings.ToLower(text), "ivxlcdm") == ""
+}
+
 // RefreshPageRefMappingsView causes the Page References Mappings view to be refreshed if it is open.
 func RefreshPageRefMappingsView() {
 	ws := workspace.Any()
@@ -193,7 +258,7 @@ func ShowPageRefMappings() {
 func (d *pageRefMappingsDockable) initContent(content *unison.Panel) {
 	d.content = content
 	d.content.SetLayout(&unison.FlexLayout{
-		Columns:  4,
+		Columns:  5,
 		HSpacing: unison.StdHSpacing,
 		VSpacing: unison.StdVSpacing,
 	})
@@ -211,9 +276,16 @@ func (d *pageRefMappingsDockable) sync() {
 		d.createIDField(one)
 		d.createOffsetField(one)
 		d.createNameField(one)
+		d.createAddTargetField(one)
 		d.createTrashField(one)
+		for _, r := range one.Ranges {
+			d.createRangeRow(one, r)
+		}
+		for _, l := range one.Labels {
+			d.createLabelRow(one, l)
+		}
 	}
-	d.MarkForRedraw()
+	d.MarkForLayoutAndRedraw()
 }
 
 func (d *pageRefMappingsDockable) createIDField(ref *settings.PageRef) {
@@ -273,12 +345,7 @@ func (d *pageRefMappingsDockable) createTrashField(ref *settings.PageRef) {
 		if unison.QuestionDialog(fmt.Sprintf(i18n.Text("Are you sure you want to remove\n%s (%s)?"), ref.ID,
 			filepath.Base(ref.Path)), "") == unison.ModalResponseOK {
 			settings.Global().PageRefs.Remove(ref.ID)
-			parent := b.Parent()
-			index := parent.IndexOfChild(b)
-			for i := index; i > index-4; i-- {
-				parent.RemoveChildAtIndex(i)
-			}
-			parent.MarkForLayoutAndRedraw()
+			d.sync()
 		}
 	}
 	b.SetLayoutData(&unison.FlexLayoutData{
@@ -0,0 +1,235 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package settings
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/richardwilkes/gcs/v5/model/settings"
+	"github.com/richardwilkes/gcs/v5/res"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+func (d *pageRefMappingsDockable) createAddTargetField(ref *settings.PageRef) {
+	b := unison.NewSVGButton(res.CircledAddSVG)
+	b.Tooltip = unison.NewTooltipWithText(i18n.Text("Add a page range mapped to another file, or a non-numeric page label"))
+	b.ClickCallback = func() {
+		f := unison.DefaultMenuFactory()
+		m := f.NewMenu(unison.ContextMenuIDFlag, "", nil)
+		m.InsertItem(-1, f.NewItem(unison.ContextMenuIDFlag+1, i18n.Text("Add Page Range…"), unison.KeyBinding{}, nil,
+			func(_ unison.MenuItem) {
+				if p, ok := choosePageRefPDF(); ok {
+					first := 1
+					for _, one := range ref.Ranges {
+						if one.Last >= first {
+							first = one.Last + 1
+						}
+					}
+					ref.Ranges = append(ref.Ranges, &settings.PageRange{
+						First:  first,
+						Last:   first,
+						Path:   p,
+						Offset: 1 - first,
+					})
+					settings.Global().PageRefs.Set(ref)
+					d.sync()
+				}
+			}))
+		m.InsertItem(-1, f.NewItem(unison.ContextMenuIDFlag+2, i18n.Text("Add Page Label"), unison.KeyBinding{}, nil,
+			func(_ unison.MenuItem) {
+				ref.Labels = append(ref.Labels, &settings.PageLabel{Label: "i"})
+				settings.Global().PageRefs.Set(ref)
+				d.sync()
+			}))
+		m.Popup(b.RectToRoot(b.ContentRect(true)), 0)
+	}
+	b.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.MiddleAlignment,
+		VAlign: unison.MiddleAlignment,
+	})
+	d.content.AddChild(b)
+}
+
+func (d *pageRefMappingsDockable) createRangeRow(ref *settings.PageRef, r *settings.PageRange) {
+	row := d.newTargetRow()
+	firstTitle := i18n.Text("First Page")
+	row.AddChild(widget.NewFieldLeadingLabel(i18n.Text("Pages")))
+	first := widget.NewIntegerField(nil, "", firstTitle,
+		func() int { return r.First },
+		func(v int) {
+			r.First = v
+			settings.Global().PageRefs.Set(ref)
+		}, -9999, 9999, false, false)
+	first.Tooltip = unison.NewTooltipWithText(firstTitle)
+	row.AddChild(first)
+	lastTitle := i18n.Text("Last Page")
+	row.AddChild(widget.NewFieldInteriorLeadingLabel(i18n.Text("to")))
+	last := widget.NewIntegerField(nil, "", lastTitle,
+		func() int { return r.Last },
+		func(v int) {
+			r.Last = v
+			settings.Global().PageRefs.Set(ref)
+		}, -9999, 9999, false, false)
+	last.Tooltip = unison.NewTooltipWithText(lastTitle)
+	row.AddChild(last)
+	row.AddChild(widget.NewFieldInteriorLeadingLabel(i18n.Text("Offset")))
+	offset := widget.NewIntegerField(nil, "", i18n.Text("Page Offset"),
+		func() int { return r.Offset },
+		func(v int) {
+			r.Offset = v
+			settings.Global().PageRefs.Set(ref)
+		}, -9999, 9999, true, false)
+	offset.Tooltip = unison.NewTooltipWithText(i18n.Text(`The offset to apply to page numbers within this range. If the
+file begins with printed page 101, an offset of -100 is typical.`))
+	row.AddChild(offset)
+	d.addTargetFileLabel(row, r.Path)
+	chooser := unison.NewSVGButton(res.OpenFolderSVG)
+	chooser.Tooltip = unison.NewTooltipWithText(i18n.Text("Choose the file for this range"))
+	chooser.ClickCallback = func() {
+		if p, ok := choosePageRefPDF(); ok {
+			r.Path = p
+			settings.Global().PageRefs.Set(ref)
+			d.sync()
+		}
+	}
+	row.AddChild(chooser)
+	d.addTargetTrashButton(row, ref, func() {
+		for i, one := range ref.Ranges {
+			if one == r {
+				ref.Ranges = append(ref.Ranges[:i], ref.Ranges[i+1:]...)
+				break
+			}
+		}
+	})
+	d.finishTargetRow(row)
+}
+
+func (d *pageRefMappingsDockable) createLabelRow(ref *settings.PageRef, l *settings.PageLabel) {
+	row := d.newTargetRow()
+	labelTitle := i18n.Text("Page Label")
+	row.AddChild(widget.NewFieldLeadingLabel(i18n.Text("Label")))
+	label := widget.NewStringField(nil, "", labelTitle,
+		func() string { return l.Label },
+		func(s string) {
+			l.Label = s
+			settings.Global().PageRefs.Set(ref)
+		})
+	label.SetMinimumTextWidthUsing("xxviii")
+	label.Tooltip = unison.NewTooltipWithText(fmt.Sprintf(i18n.Text(`A non-numeric page label, such as the roman numeral "xii".
+With this label, the page reference "%sxii" would open the page.`), ref.ID))
+	row.AddChild(label)
+	row.AddChild(widget.NewFieldInteriorLeadingLabel(i18n.Text("is page")))
+	page := widget.NewIntegerField(nil, "", i18n.Text("PDF Page"),
+		func() int { return l.PageNumber + 1 },
+		func(v int) {
+			l.PageNumber = v - 1
+			settings.Global().PageRefs.Set(ref)
+		}, 1, 9999, false, false)
+	page.Tooltip = unison.NewTooltipWithText(i18n.Text("The page within the PDF file, where the first page is 1"))
+	row.AddChild(page)
+	row.AddChild(widget.NewFieldInteriorLeadingLabel(i18n.Text("of")))
+	popup := unison.NewPopupMenu[string]()
+	paths := ref.Paths()
+	for _, one := range paths {
+		popup.AddItem(filepath.Base(one))
+	}
+	selected := 0
+	for i, one := range paths {
+		if one == l.Path {
+			selected = i
+		}
+	}
+	popup.SelectIndex(selected)
+	popup.SelectionCallback = func(index int, _ string) {
+		if index == 0 {
+			l.Path = ""
+		} else {
+			l.Path = paths[index]
+		}
+		settings.Global().PageRefs.Set(ref)
+	}
+	row.AddChild(popup)
+	d.addTargetTrashButton(row, ref, func() {
+		for i, one := range ref.Labels {
+			if one == l {
+				ref.Labels = append(ref.Labels[:i], ref.Labels[i+1:]...)
+				break
+			}
+		}
+	})
+	d.finishTargetRow(row)
+}
+
+func (d *pageRefMappingsDockable) newTargetRow() *unison.Panel {
+	row := unison.NewPanel()
+	row.SetBorder(unison.NewEmptyBorder(unison.Insets{Left: unison.StdHSpacing * 4}))
+	row.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  5,
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	return row
+}
+
+func (d *pageRefMappingsDockable) finishTargetRow(row *unison.Panel) {
+	row.SetLayout(&unison.FlexLayout{
+		Columns:  len(row.Children()),
+		HSpacing: unison.StdHSpacing,
+	})
+	d.content.AddChild(row)
+}
+
+func (d *pageRefMappingsDockable) addTargetFileLabel(row *unison.Panel, filePath string) {
+	p := unison.NewLabel()
+	p.Text = filepath.Base(filePath)
+	p.Tooltip = unison.NewTooltipWithText(filePath)
+	p.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		VAlign: unison.MiddleAlignment,
+		HGrab:  true,
+	})
+	row.AddChild(p)
+}
+
+func (d *pageRefMappingsDockable) addTargetTrashButton(row *unison.Panel, ref *settings.PageRef, remover func()) {
+	b := unison.NewSVGButton(res.TrashSVG)
+	b.ClickCallback = func() {
+		remover()
+		settings.Global().PageRefs.Set(ref)
+		d.sync()
+	}
+	b.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.EndAlignment,
+		VAlign: unison.MiddleAlignment,
+	})
+	row.AddChild(b)
+}
+
+func choosePageRefPDF() (string, bool) {
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(false)
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions("pdf")
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	global := settings.Global()
+	dialog.SetInitialDirectory(global.LastDir(settings.DefaultLastDirKey))
+	if !dialog.RunModal() {
+		return "", false
+	}
+	p := dialog.Path()
+	global.SetLastDir(settings.DefaultLastDirKey, filepath.Dir(p))
+	return p, true
+}
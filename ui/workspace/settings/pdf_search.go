@@ -0,0 +1,342 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package settings
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/richardwilkes/gcs/v5/model/settings"
+	"github.com/richardwilkes/gcs/v5/pdf"
+	"github.com/richardwilkes/gcs/v5/res"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/gcs/v5/ui/workspace"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/txt"
+	xfs "github.com/richardwilkes/toolbox/xio/fs"
+	"github.com/richardwilkes/unison"
+)
+
+var _ unison.TabCloser = &pdfSearchDockable{}
+
+// PageRefInserter is called with a page reference to insert into an item's page reference field.
+type PageRefInserter func(ref string)
+
+type pdfSearchDockable struct {
+	unison.Panel
+	searchField *unison.Field
+	status      *unison.Label
+	content     *unison.Panel
+	scroll      *unison.ScrollPanel
+	inserter    PageRefInserter
+	text        string
+	generation  atomic.Int64
+}
+
+// PageRefSearchSources returns the search sources for each page reference mapping whose file is readable, including
+// any additional files that ranges of pages have been mapped to.
+func PageRefSearchSources() []*pdf.SearchSource {
+	s := settings.Global()
+	list := s.PageRefs.List()
+	sources := make([]*pdf.SearchSource, 0, len(list))
+	for _, one := range list {
+		if ref := s.PageRefs.Lookup(one.ID); ref != nil {
+			sources = append(sources, &pdf.SearchSource{
+				Key:    ref.ID,
+				Path:   ref.Path,
+				Offset: ref.Offset,
+			})
+			seen := map[string]bool{ref.Path: true}
+			for _, r := range ref.Ranges {
+				if !seen[r.Path] && xfs.FileIsReadable(r.Path) {
+					seen[r.Path] = true
+					sources = append(sources, &pdf.SearchSource{
+						Key:    ref.ID,
+						Path:   r.Path,
+						Offset: r.Offset,
+					})
+				}
+			}
+		}
+	}
+	return sources
+}
+
+// SuggestPageRefs searches the PDFs mapped to page reference keys for the text and returns the candidate hits, ranked
+// with the most likely candidate first. Pages with more matches are considered more likely. Like pdf.Index.Search,
+// this should not be called from the UI thread.
+func SuggestPageRefs(text string, canceled func() bool) ([]*pdf.SearchHit, error) {
+	var hits []*pdf.SearchHit
+	if err := pdf.SharedIndex().Search(PageRefSearchSources(), text, func(hit *pdf.SearchHit) {
+		hits = append(hits, hit)
+	}, nil, canceled); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return betterPageRefHit(hits[i], hits[j]) })
+	return hits, nil
+}
+
+// BestPageRefs searches the PDFs mapped to page reference keys for each of the texts in a single pass and returns the
+// most likely candidate hit for each, or nil for those that weren't found. progress, if not nil, is called after each
+// PDF has been searched. Like pdf.Index.SearchAll, this should not be called from the UI thread.
+func BestPageRefs(texts []string, progress func(done, total int), canceled func() bool) ([]*pdf.SearchHit, error) {
+	best := make([]*pdf.SearchHit, len(texts))
+	if err := pdf.SharedIndex().SearchAll(PageRefSearchSources(), texts, func(which int, hit *pdf.SearchHit) {
+		if best[which] == nil || betterPageRefHit(hit, best[which]) {
+			best[which] = hit
+		}
+	}, progress, canceled); err != nil {
+		return nil, err
+	}
+	return best, nil
+}
+
+func betterPageRefHit(hit, other *pdf.SearchHit) bool {
+	if hit.Matches != other.Matches {
+		return hit.Matches > other.Matches
+	}
+	return txt.NaturalLess(hit.PageReference(), other.PageReference(), true)
+}
+
+// AppendPageRef appends a page reference to an existing set of page references, unless it is already present.
+func AppendPageRef(existing, ref string) string {
+	for _, one := range ExtractPageReferences(existing) {
+		if strings.EqualFold(one, ref) {
+			return existing
+		}
+	}
+	if existing = strings.TrimSpace(existing); existing == "" {
+		return ref
+	}
+	return existing + ", " + ref
+}
+
+// ShowPDFSearch shows the sourcebook search, searching for the given text. If inserter is not nil, each hit will offer
+// to insert its page reference via the inserter.
+func ShowPDFSearch(text string, inserter PageRefInserter) {
+	var d *pdfSearchDockable
+	ws, _, found := workspace.Activate(func(one unison.Dockable) bool {
+		var ok bool
+		d, ok = one.(*pdfSearchDockable)
+		return ok
+	})
+	if !found {
+		if ws == nil {
+			return
+		}
+		d = newPDFSearchDockable()
+		workspace.DisplayNewDockable(nil, d)
+	}
+	d.inserter = inserter
+	if text = strings.TrimSpace(text); text != "" {
+		d.searchField.SetText(text)
+	}
+	d.search()
+}
+
+func newPDFSearchDockable() *pdfSearchDockable {
+	d := &pdfSearchDockable{}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.DividerColor, 0, unison.Insets{Bottom: 1},
+		false), unison.NewEmptyBorder(unison.StdInsets())))
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+
+	d.searchField = widget.NewSearchField()
+	searchTitle := i18n.Text("Search Sourcebooks")
+	d.searchField.Watermark = searchTitle
+	d.searchField.Tooltip = unison.NewTooltipWithText(searchTitle)
+	d.searchField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		VAlign: unison.MiddleAlignment,
+		HGrab:  true,
+	})
+	d.searchField.KeyDownCallback = func(keyCode unison.KeyCode, mod unison.Modifiers, repeat bool) bool {
+		if keyCode == unison.KeyReturn || keyCode == unison.KeyNumPadEnter {
+			d.search()
+			return true
+		}
+		return d.searchField.DefaultKeyDown(keyCode, mod, repeat)
+	}
+	toolbar.AddChild(d.searchField)
+
+	searchButton := unison.NewSVGButton(res.SearchSVG)
+	searchButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Search the PDFs mapped to page reference keys"))
+	searchButton.ClickCallback = d.search
+	toolbar.AddChild(searchButton)
+
+	d.status = unison.NewLabel()
+	d.status.Text = "-"
+	toolbar.AddChild(d.status)
+
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+	})
+
+	d.content = unison.NewPanel()
+	d.content.SetBorder(unison.NewEmptyBorder(unison.StdInsets()))
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	d.scroll = unison.NewScrollPanel()
+	d.scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		VAlign: unison.FillAlignment,
+		HGrab:  true,
+		VGrab:  true,
+	})
+	d.scroll.SetContent(d.content, unison.FillBehavior, unison.UnmodifiedBehavior)
+
+	d.AddChild(toolbar)
+	d.AddChild(d.scroll)
+	return d
+}
+
+func (d *pdfSearchDockable) search() {
+	generation := d.generation.Add(1)
+	d.text = strings.TrimSpace(d.searchField.Text())
+	d.content.RemoveAllChildren()
+	d.content.MarkForLayoutAndRedraw()
+	if d.text == "" {
+		d.setStatus("-")
+		return
+	}
+	sources := PageRefSearchSources()
+	if len(sources) == 0 {
+		d.setStatus(i18n.Text("No page reference mappings"))
+		return
+	}
+	d.setStatus(i18n.Text("Searching…"))
+	text := d.text
+	count := 0
+	go func() {
+		canceled := func() bool { return generation != d.generation.Load() }
+		err := pdf.SharedIndex().Search(sources, text, func(hit *pdf.SearchHit) {
+			unison.InvokeTask(func() {
+				if !canceled() {
+					count++
+					d.addHit(hit)
+				}
+			})
+		}, func(done, total int) {
+			unison.InvokeTask(func() {
+				if !canceled() {
+					d.setStatus(fmt.Sprintf(i18n.Text("Searched %d of %d"), done, total))
+				}
+			})
+		}, canceled)
+		unison.InvokeTask(func() {
+			if canceled() {
+				return
+			}
+			if err != nil {
+				d.setStatus(i18n.Text("Search failed"))
+				unison.ErrorDialogWithError(i18n.Text("Unable to search sourcebooks"), err)
+				return
+			}
+			d.setStatus(fmt.Sprintf(i18n.Text("%d pages found"), count))
+		})
+	}()
+}
+
+func (d *pdfSearchDockable) setStatus(text string) {
+	d.status.Text = text
+	d.status.Parent().MarkForLayoutAndRedraw()
+}
+
+func (d *pdfSearchDockable) addHit(hit *pdf.SearchHit) {
+	ref := hit.PageReference()
+	text := d.text
+
+	label := unison.NewLabel()
+	label.Text = ref
+	label.SetLayoutData(&unison.FlexLayoutData{VAlign: unison.MiddleAlignment})
+	d.content.AddChild(label)
+
+	info := unison.NewLabel()
+	info.Text = fmt.Sprintf(i18n.Text("%s, page %d (%d matches)"), filepath.Base(hit.Source.Path), hit.PageNumber+1,
+		hit.Matches)
+	info.Tooltip = unison.NewTooltipWithText(hit.Source.Path)
+	info.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		VAlign: unison.MiddleAlignment,
+		HGrab:  true,
+	})
+	d.content.AddChild(info)
+
+	openButton := unison.NewSVGButton(res.BookmarkSVG)
+	openButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Open this page"))
+	openButton.ClickCallback = func() { OpenPageReference(d.Window(), ref, text, nil) }
+	d.content.AddChild(openButton)
+
+	insertButton := unison.NewSVGButton(res.CircledAddSVG)
+	insertButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Insert this page reference into the item being edited"))
+	insertButton.ClickCallback = func() {
+		if d.inserter != nil {
+			d.inserter(ref)
+		}
+	}
+	insertButton.SetEnabled(d.inserter != nil)
+	d.content.AddChild(insertButton)
+
+	d.content.MarkForLayoutAndRedraw()
+	d.scroll.MarkForLayoutAndRedraw()
+}
+
+// TitleIcon implements unison.Dockable
+func (d *pdfSearchDockable) TitleIcon(suggestedSize unison.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  res.SearchSVG,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable
+func (d *pdfSearchDockable) Title() string {
+	return i18n.Text("Sourcebook Search")
+}
+
+// Tooltip implements unison.Dockable
+func (d *pdfSearchDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable
+func (d *pdfSearchDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser
+func (d *pdfSearchDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser
+func (d *pdfSearchDockable) AttemptClose() bool {
+	d.generation.Add(1) // Stop any search in progress
+	if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
+		dc.Close(d)
+	}
+	return true
+}
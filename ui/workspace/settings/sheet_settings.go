@@ -17,6 +17,7 @@ import (
 	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/gcs/v5/model/gurps/attribute"
 	"github.com/richardwilkes/gcs/v5/model/gurps/measure"
+	gsettings "github.com/richardwilkes/gcs/v5/model/gurps/settings"
 	"github.com/richardwilkes/gcs/v5/model/library"
 	"github.com/richardwilkes/gcs/v5/model/paper"
 	"github.com/richardwilkes/gcs/v5/model/settings"
@@ -34,6 +35,7 @@ type sheetSettingsDockable struct {
 	Dockable
 	owner                              widget.EntityPanel
 	damageProgressionPopup             *unison.PopupMenu[attribute.DamageProgression]
+	calendarPopup                      *unison.PopupMenu[string]
 	showTraitModifier                  *unison.CheckBox
 	showEquipmentModifier              *unison.CheckBox
 	showSpellAdjustments               *unison.CheckBox
@@ -43,6 +45,9 @@ type sheetSettingsDockable struct {
 	excludeUnspentPointsFromTotal      *unison.CheckBox
 	lengthUnitsPopup                   *unison.PopupMenu[measure.LengthUnits]
 	weightUnitsPopup                   *unison.PopupMenu[measure.WeightUnits]
+	volumeUnitsPopup                   *unison.PopupMenu[measure.VolumeUnits]
+	areaUnitsPopup                     *unison.PopupMenu[measure.AreaUnits]
+	displayCurrencyPopup               *unison.PopupMenu[string]
 	userDescDisplayPopup               *unison.PopupMenu[display.Option]
 	modifiersDisplayPopup              *unison.PopupMenu[display.Option]
 	notesDisplayPopup                  *unison.PopupMenu[display.Option]
@@ -120,9 +125,27 @@ func (d *sheetSettingsDockable) createDamageProgression(content *unison.Panel) {
 			d.damageProgressionPopup.Tooltip = unison.NewTooltipWithText(item.Tooltip())
 			d.settings().DamageProgression = item
 		})
+	var calendars []string
+	for _, lib := range gsettings.AvailableCalendarRefs(settings.Global().Libraries()) {
+		for _, one := range lib.List {
+			calendars = append(calendars, one.Name)
+		}
+	}
+	d.calendarPopup = createSettingPopup(d, panel, i18n.Text("Calendar"), calendars, d.calendarName(),
+		func(item string) { d.settings().CalendarName = item })
 	content.AddChild(panel)
 }
 
+func (d *sheetSettingsDockable) calendarName() string {
+	if d.owner != nil {
+		return d.owner.Entity().CalendarRef().Name
+	}
+	if name := d.settings().CalendarName; name != "" {
+		return name
+	}
+	return settings.Global().General.CalendarRef(settings.Global().Libraries()).Name
+}
+
 func (d *sheetSettingsDockable) createOptions(content *unison.Panel) {
 	s := d.settings()
 	panel := unison.NewPanel()
@@ -192,9 +215,29 @@ func (d *sheetSettingsDockable) createUnitsOfMeasurement(content *unison.Panel)
 		s.DefaultLengthUnits, func(item measure.LengthUnits) { d.settings().DefaultLengthUnits = item })
 	d.weightUnitsPopup = createSettingPopup(d, panel, i18n.Text("Length Units"), measure.AllWeightUnits,
 		s.DefaultWeightUnits, func(item measure.WeightUnits) { d.settings().DefaultWeightUnits = item })
+	d.volumeUnitsPopup = createSettingPopup(d, panel, i18n.Text("Volume Units"), measure.AllVolumeUnits,
+		s.DefaultVolumeUnits, func(item measure.VolumeUnits) { d.settings().DefaultVolumeUnits = item })
+	d.areaUnitsPopup = createSettingPopup(d, panel, i18n.Text("Area Units"), measure.AllAreaUnits,
+		s.DefaultAreaUnits, func(item measure.AreaUnits) { d.settings().DefaultAreaUnits = item })
+	currencies := gurps.Currencies()
+	d.displayCurrencyPopup = createSettingPopup(d, panel, i18n.Text("Display Currency"), currencies.IDs(),
+		d.displayCurrency(), func(item string) {
+			if currencies.Lookup("") == currencies.Lookup(item) {
+				// The base currency is stored as an empty string
+				item = ""
+			}
+			d.settings().DisplayCurrency = item
+		})
 	content.AddChild(panel)
 }
 
+func (d *sheetSettingsDockable) displayCurrency() string {
+	if c := gurps.Currencies().Lookup(d.settings().DisplayCurrency); c != nil {
+		return c.ID
+	}
+	return d.settings().DisplayCurrency
+}
+
 func (d *sheetSettingsDockable) createWhereToDisplay(content *unison.Panel) {
 	s := d.settings()
 	panel := unison.NewPanel()
@@ -349,6 +392,7 @@ func (d *sheetSettingsDockable) reset() {
 func (d *sheetSettingsDockable) sync() {
 	s := d.settings()
 	d.damageProgressionPopup.Select(s.DamageProgression)
+	d.calendarPopup.Select(d.calendarName())
 	d.showTraitModifier.State = unison.CheckStateFromBool(s.ShowTraitModifierAdj)
 	d.showEquipmentModifier.State = unison.CheckStateFromBool(s.ShowEquipmentModifierAdj)
 	d.showSpellAdjustments.State = unison.CheckStateFromBool(s.ShowSpellAdj)
@@ -358,6 +402,9 @@ func (d *sheetSettingsDockable) sync() {
 	d.excludeUnspentPointsFromTotal.State = unison.CheckStateFromBool(s.ExcludeUnspentPointsFromTotal)
 	d.lengthUnitsPopup.Select(s.DefaultLengthUnits)
 	d.weightUnitsPopup.Select(s.DefaultWeightUnits)
+	d.volumeUnitsPopup.Select(s.DefaultVolumeUnits)
+	d.areaUnitsPopup.Select(s.DefaultAreaUnits)
+	d.displayCurrencyPopup.Select(d.displayCurrency())
 	d.userDescDisplayPopup.Select(s.UserDescriptionDisplay)
 	d.modifiersDisplayPopup.Select(s.ModifiersDisplay)
 	d.notesDisplayPopup.Select(s.NotesDisplay)
@@ -0,0 +1,213 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package sheet
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+type advanceTimeUndoEdit = *unison.UndoEdit[*advanceTimeData]
+
+type advanceTimeData struct {
+	sheet        *Sheet
+	date         string
+	timeOfDay    int
+	age          string
+	costOfLiving fxp.Int
+	totalPoints  fxp.Int
+	advancements []*gurps.Advancement
+	cash         *tradeAdjuster
+	spells       *spellEnergyData
+	effects      *effectsData
+}
+
+func newAdvanceTimeData(sheet *Sheet) *advanceTimeData {
+	data := &advanceTimeData{
+		sheet:        sheet,
+		date:         sheet.entity.CurrentDate,
+		timeOfDay:    sheet.entity.TimeOfDay,
+		age:          sheet.entity.Profile.Age,
+		costOfLiving: sheet.entity.CostOfLiving,
+		totalPoints:  sheet.entity.TotalPoints,
+		advancements: append([]*gurps.Advancement(nil), sheet.entity.Advancements...),
+		spells:       newSpellEnergyData(sheet, sheet.entity),
+		effects:      newEffectsData(sheet),
+	}
+	if cash := sheet.entity.CashItem(); cash != nil {
+		data.cash = newTradeAdjuster(cash)
+	}
+	return data
+}
+
+func (a *advanceTimeData) Apply() {
+	entity := a.sheet.entity
+	entity.CurrentDate = a.date
+	entity.TimeOfDay = a.timeOfDay
+	entity.Profile.Age = a.age
+	entity.CostOfLiving = a.costOfLiving
+	entity.TotalPoints = a.totalPoints
+	entity.Advancements = append([]*gurps.Advancement(nil), a.advancements...)
+	if a.cash != nil {
+		a.cash.Apply()
+	}
+	a.spells.restore()
+	a.effects.restore()
+	entity.Recalculate()
+	a.sheet.Rebuild(true)
+}
+
+// advanceTimeUnit is a unit of time the advance time dialog can move time forward by.
+type advanceTimeUnit struct {
+	name    string
+	seconds int
+}
+
+func advanceTimeUnits() []advanceTimeUnit {
+	return []advanceTimeUnit{
+		{name: i18n.Text("Turns"), seconds: gurps.SecondsPerTurn},
+		{name: i18n.Text("Minutes"), seconds: gurps.SecondsPerMinute},
+		{name: i18n.Text("Hours"), seconds: gurps.SecondsPerHour},
+		{name: i18n.Text("Days"), seconds: gurps.SecondsPerDay},
+		{name: i18n.Text("Weeks"), seconds: gurps.SecondsPerWeek},
+	}
+}
+
+func (s *Sheet) advanceTime() {
+	entity := s.entity
+	dateText := entity.CurrentDate
+	amount := 1
+	units := advanceTimeUnits()
+	unit := units[3]
+	costOfLiving := entity.CostOfLiving
+	applyCostOfLiving := costOfLiving > 0
+	var points fxp.Int
+	var reason string
+	if !showAdvanceTimeDialog(entity, &dateText, &amount, units, &unit, &costOfLiving, &applyCostOfLiving, &points,
+		&reason) {
+		return
+	}
+	before := newAdvanceTimeData(s)
+	err := s.applyAdvanceTime(strings.TrimSpace(dateText), amount*unit.seconds, costOfLiving, applyCostOfLiving)
+	if err == nil {
+		entity.EarnPoints(points, reason)
+	}
+	if err != nil {
+		before.Apply()
+		unison.ErrorDialogWithError(i18n.Text("Unable to advance time"), err)
+		return
+	}
+	s.UndoManager().Add(&unison.UndoEdit[*advanceTimeData]{
+		ID:         unison.NextUndoID(),
+		EditName:   i18n.Text("Advance Time"),
+		UndoFunc:   func(edit advanceTimeUndoEdit) { edit.BeforeData.Apply() },
+		RedoFunc:   func(edit advanceTimeUndoEdit) { edit.AfterData.Apply() },
+		BeforeData: before,
+		AfterData:  newAdvanceTimeData(s),
+	})
+	entity.Recalculate()
+	s.Rebuild(true)
+	widget.MarkModified(s)
+}
+
+func (s *Sheet) applyAdvanceTime(dateText string, seconds int, costOfLiving fxp.Int, applyCostOfLiving bool) error {
+	entity := s.entity
+	entity.CostOfLiving = costOfLiving
+	if dateText != entity.CurrentDate {
+		if dateText == "" {
+			entity.CurrentDate = ""
+		} else {
+			date, err := entity.Calendar().ParseDate(dateText)
+			if err != nil {
+				return errs.NewWithCause(i18n.Text("Invalid current date"), err)
+			}
+			entity.SetDate(date)
+		}
+	}
+	return entity.AdvanceTime(seconds, applyCostOfLiving)
+}
+
+func showAdvanceTimeDialog(entity *gurps.Entity, dateText *string, amount *int, units []advanceTimeUnit, unit *advanceTimeUnit, costOfLiving *fxp.Int, applyCostOfLiving *bool, points *fxp.Int, reason *string) bool {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	dateTitle := i18n.Text("Current Date")
+	panel.AddChild(widget.NewFieldLeadingLabel(dateTitle))
+	dateField := widget.NewStringField(nil, "", dateTitle,
+		func() string { return *dateText },
+		func(s string) { *dateText = s })
+	dateField.Tooltip = unison.NewTooltipWithText(fmt.Sprintf(i18n.Text("For example, %s. Leave empty for no date."),
+		entity.Calendar().NewDateByDays(0).String()))
+	dateField.ValidateCallback = func() bool { return entity.ValidDate(dateField.Text()) }
+	dateField.SetMinimumTextWidthUsing("12/31/2000 AD")
+	panel.AddChild(dateField)
+	panel.AddChild(widget.NewFieldLeadingLabel(i18n.Text("Time of Day")))
+	timeOfDay := entity.FormatTimeOfDay()
+	panel.AddChild(widget.NewNonEditableField(func(field *widget.NonEditableField) { field.Text = timeOfDay }))
+	advanceTitle := i18n.Text("Advance By")
+	panel.AddChild(widget.NewFieldLeadingLabel(advanceTitle))
+	advance := unison.NewPanel()
+	advance.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+	})
+	advance.AddChild(widget.NewIntegerField(nil, "", advanceTitle,
+		func() int { return *amount },
+		func(v int) { *amount = v },
+		0, 99999, false, false))
+	popup := unison.NewPopupMenu[string]()
+	for i, one := range units {
+		popup.AddItem(one.name)
+		if one == *unit {
+			popup.SelectIndex(i)
+		}
+	}
+	popup.SelectionCallback = func(index int, _ string) { *unit = units[index] }
+	advance.AddChild(popup)
+	panel.AddChild(advance)
+	costTitle := i18n.Text("Monthly Cost of Living")
+	panel.AddChild(widget.NewFieldLeadingLabel(costTitle))
+	costField := widget.NewDecimalField(nil, "", costTitle,
+		func() fxp.Int { return *costOfLiving },
+		func(v fxp.Int) { *costOfLiving = v },
+		0, fxp.Max-1, false, false)
+	costField.Tooltip = unison.NewTooltipWithText(i18n.Text("Cost-of-living increases from self-control rolls are applied on top of this amount"))
+	panel.AddChild(costField)
+	panel.AddChild(unison.NewPanel())
+	panel.AddChild(widget.NewCheckBox(nil, "", i18n.Text("Deduct cost of living from cash"),
+		func() unison.CheckState { return unison.CheckStateFromBool(*applyCostOfLiving) },
+		func(state unison.CheckState) { *applyCostOfLiving = state == unison.OnCheckState }))
+	pointsTitle := i18n.Text("Points Earned")
+	panel.AddChild(widget.NewFieldLeadingLabel(pointsTitle))
+	pointsField := widget.NewDecimalField(nil, "", pointsTitle,
+		func() fxp.Int { return *points },
+		func(v fxp.Int) { *points = v },
+		fxp.Min, fxp.Max, false, false)
+	pointsField.Tooltip = unison.NewTooltipWithText(i18n.Text("Points to add to the total, recorded as an advancement dated with the new current date"))
+	panel.AddChild(pointsField)
+	reasonTitle := i18n.Text("Reason")
+	panel.AddChild(widget.NewFieldLeadingLabel(reasonTitle))
+	panel.AddChild(widget.NewStringField(nil, "", reasonTitle,
+		func() string { return *reason },
+		func(s string) { *reason = s }))
+	return unison.QuestionDialogWithPanel(panel) == unison.ModalResponseOK
+}
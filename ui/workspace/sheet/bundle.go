@@ -0,0 +1,147 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package sheet
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/bundle"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/gcs/v5/model/settings"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
+	wsettings "github.com/richardwilkes/gcs/v5/ui/workspace/settings"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+func (s *Sheet) exportToBundle() {
+	s.Window().ShowCursor()
+	dialog := unison.NewSaveDialog()
+	dialog.SetInitialDirectory(filepath.Dir(s.BackingFilePath()))
+	dialog.SetAllowedExtensions(library.BundleExt[1:])
+	if dialog.RunModal() {
+		unison.InvokeTaskAfter(func() {
+			if filePath, ok := unison.ValidateSaveFilePath(dialog.Path(), library.BundleExt[1:], false); ok {
+				b, err := bundle.New(s.entity, filepath.Base(s.BackingFilePath()), usedPageRefs(s.entity))
+				if err == nil {
+					err = b.Save(filePath)
+				}
+				if err != nil {
+					unison.ErrorDialogWithError(i18n.Text("Unable to export as a portable bundle!"), err)
+				}
+			}
+		}, time.Millisecond)
+	}
+}
+
+// usedPageRefs returns the page reference mappings for the keys used by the entity's page references.
+func usedPageRefs(entity *gurps.Entity) []*settings.PageRef {
+	pageRefs := &settings.Global().PageRefs
+	seen := make(map[string]bool)
+	var list []*settings.PageRef
+	add := func(refs string) {
+		for _, ref := range wsettings.ExtractPageReferences(refs) {
+			if key := wsettings.PageReferenceKey(ref); key != "" && !seen[key] {
+				seen[key] = true
+				if pageRef := pageRefs.Lookup(key); pageRef != nil {
+					list = append(list, pageRef)
+				}
+			}
+		}
+	}
+	gurps.Traverse(func(t *gurps.Trait) bool {
+		add(t.PageRef)
+		gurps.Traverse(func(m *gurps.TraitModifier) bool {
+			add(m.PageRef)
+			return false
+		}, false, false, t.Modifiers...)
+		return false
+	}, false, false, entity.Traits...)
+	gurps.Traverse(func(one *gurps.Skill) bool {
+		add(one.PageRef)
+		return false
+	}, false, false, entity.Skills...)
+	gurps.Traverse(func(one *gurps.Spell) bool {
+		add(one.PageRef)
+		return false
+	}, false, false, entity.Spells...)
+	gurps.Traverse(func(e *gurps.Equipment) bool {
+		add(e.PageRef)
+		gurps.Traverse(func(m *gurps.EquipmentModifier) bool {
+			add(m.PageRef)
+			return false
+		}, false, false, e.Modifiers...)
+		return false
+	}, false, false, append(append([]*gurps.Equipment{}, entity.CarriedEquipment...), entity.OtherEquipment...)...)
+	gurps.Traverse(func(one *gurps.Note) bool {
+		add(one.PageRef)
+		return false
+	}, false, false, entity.Notes...)
+	return list
+}
+
+// NewSheetFromBundleFile opens the sheet within a portable bundle as a new, unsaved character sheet, then offers to
+// install the library files that came with it.
+func NewSheetFromBundleFile(filePath string) (unison.Dockable, error) {
+	b, err := bundle.NewFromFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	s := NewSheet(b.SheetName, b.Entity)
+	if len(b.Files) != 0 {
+		unison.InvokeTask(func() { offerBundleInstall(b) })
+	}
+	return s, nil
+}
+
+func offerBundleInstall(b *bundle.Bundle) {
+	lib := settings.Global().Libraries().User()
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	label.Text = fmt.Sprintf(i18n.Text("Install these files from the bundle into the %s?"), lib.Title)
+	panel.AddChild(label)
+	selected := make(map[*bundle.File]bool, len(b.Files))
+	for _, one := range b.Files {
+		f := one
+		title := f.Path
+		switch exists, identical := f.Status(lib); {
+		case identical:
+			title += " " + i18n.Text("(already installed)")
+		case exists:
+			title += " " + i18n.Text("(replaces the existing file)")
+		default:
+			selected[f] = true
+		}
+		panel.AddChild(widget.NewCheckBox(nil, "", title,
+			func() unison.CheckState { return unison.CheckStateFromBool(selected[f]) },
+			func(state unison.CheckState) { selected[f] = state == unison.OnCheckState }))
+	}
+	if unison.QuestionDialogWithPanel(panel) != unison.ModalResponseOK {
+		return
+	}
+	for _, f := range b.Files {
+		if selected[f] {
+			if err := f.Install(lib); err != nil {
+				unison.ErrorDialogWithError(fmt.Sprintf(i18n.Text("Unable to install %s!"), f.Path), err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package sheet
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/gcs/v5/ui/widget/ntable"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+type tradeListUndoEdit = *unison.UndoEdit[*tradeList]
+
+type tradeList struct {
+	Owner widget.Rebuildable
+	List  []*tradeAdjuster
+}
+
+func (a *tradeList) Apply() {
+	for _, one := range a.List {
+		one.Apply()
+	}
+	entity := a.List[0].Target.OwningEntity()
+	if entity != nil {
+		entity.Recalculate()
+	}
+	widget.MarkModified(a.Owner)
+}
+
+type tradeAdjuster struct {
+	Target   *gurps.Equipment
+	Quantity fxp.Int
+	Value    fxp.Int
+}
+
+func newTradeAdjuster(target *gurps.Equipment) *tradeAdjuster {
+	return &tradeAdjuster{
+		Target:   target,
+		Quantity: target.Quantity,
+		Value:    target.Value,
+	}
+}
+
+func (a *tradeAdjuster) Apply() {
+	a.Target.Quantity = a.Quantity
+	a.Target.Value = a.Value
+}
+
+func tradeTarget(table *unison.Table[*ntable.Node[*gurps.Equipment]]) *gurps.Equipment {
+	rows := table.SelectedRows(false)
+	if len(rows) != 1 {
+		return nil
+	}
+	eqp := rows[0].Data()
+	if eqp == nil || eqp.IsCash() {
+		return nil
+	}
+	entity := eqp.OwningEntity()
+	if entity == nil || entity.CashItem() == nil {
+		return nil
+	}
+	return eqp
+}
+
+func canBuy(table *unison.Table[*ntable.Node[*gurps.Equipment]]) bool {
+	return tradeTarget(table) != nil
+}
+
+func canSell(table *unison.Table[*ntable.Node[*gurps.Equipment]]) bool {
+	eqp := tradeTarget(table)
+	return eqp != nil && eqp.Quantity > 0
+}
+
+func buyOrSell(owner widget.Rebuildable, table *unison.Table[*ntable.Node[*gurps.Equipment]], buy bool) {
+	eqp := tradeTarget(table)
+	if eqp == nil {
+		return
+	}
+	entity := eqp.OwningEntity()
+	cash := entity.CashItem()
+	quantity := fxp.One
+	maxQty := fxp.Max - 1
+	if !buy {
+		maxQty = eqp.Quantity
+		if quantity > maxQty {
+			quantity = maxQty
+		}
+	}
+	price := eqp.AdjustedValue()
+	if !showTradeDialog(eqp, buy, &quantity, &price, maxQty) || quantity <= 0 {
+		return
+	}
+	before := &tradeList{Owner: owner}
+	before.List = append(before.List, newTradeAdjuster(eqp))
+	if cash != eqp {
+		before.List = append(before.List, newTradeAdjuster(cash))
+	}
+	total := price.Mul(quantity)
+	var name string
+	if buy {
+		name = i18n.Text("Buy Equipment")
+		total = -total
+	} else {
+		name = i18n.Text("Sell Equipment")
+	}
+	if err := entity.AdjustCash(total, eqp.Currency); err != nil {
+		before.Apply()
+		unison.ErrorDialogWithError(name, err)
+		return
+	}
+	if buy {
+		eqp.Quantity += quantity
+	} else {
+		eqp.Quantity -= quantity
+	}
+	after := &tradeList{Owner: owner}
+	for _, one := range before.List {
+		after.List = append(after.List, newTradeAdjuster(one.Target))
+	}
+	if mgr := unison.UndoManagerFor(table); mgr != nil {
+		mgr.Add(&unison.UndoEdit[*tradeList]{
+			ID:         unison.NextUndoID(),
+			EditName:   name,
+			UndoFunc:   func(edit tradeListUndoEdit) { edit.BeforeData.Apply() },
+			RedoFunc:   func(edit tradeListUndoEdit) { edit.AfterData.Apply() },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	entity.Recalculate()
+	widget.MarkModified(owner)
+}
+
+func showTradeDialog(eqp *gurps.Equipment, buy bool, quantity, price *fxp.Int, maxQty fxp.Int) bool {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	if buy {
+		label.Text = fmt.Sprintf(i18n.Text("Buy %s"), eqp.Description())
+	} else {
+		label.Text = fmt.Sprintf(i18n.Text("Sell %s"), eqp.Description())
+	}
+	label.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+	panel.AddChild(label)
+	qtyTitle := i18n.Text("Quantity")
+	panel.AddChild(widget.NewFieldLeadingLabel(qtyTitle))
+	panel.AddChild(widget.NewDecimalField(nil, "", qtyTitle,
+		func() fxp.Int { return *quantity },
+		func(v fxp.Int) { *quantity = v },
+		0, maxQty, false, false))
+	priceTitle := i18n.Text("Price Each")
+	panel.AddChild(widget.NewFieldLeadingLabel(priceTitle))
+	panel.AddChild(widget.NewDecimalField(nil, "", priceTitle,
+		func() fxp.Int { return *price },
+		func(v fxp.Int) { *price = v },
+		0, fxp.Max-1, false, false))
+	panel.AddChild(widget.NewFieldLeadingLabel(i18n.Text("Available Cash")))
+	entity := eqp.OwningEntity()
+	panel.AddChild(widget.NewNonEditableField(func(field *widget.NonEditableField) {
+		field.Text = entity.FormatWealth(entity.Cash())
+		field.MarkForLayoutAndRedraw()
+	}))
+	panel.AddChild(widget.NewFieldLeadingLabel(i18n.Text("Total")))
+	panel.AddChild(widget.NewNonEditableField(func(field *widget.NonEditableField) {
+		field.Text = gurps.FormatCurrency(price.Mul(*quantity), eqp.Currency)
+		field.MarkForLayoutAndRedraw()
+	}))
+	return unison.QuestionDialogWithPanel(panel) == unison.ModalResponseOK
+}
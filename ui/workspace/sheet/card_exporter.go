@@ -0,0 +1,333 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package sheet
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/export"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/paper"
+	"github.com/richardwilkes/gcs/v5/model/theme"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/toolbox"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xmath"
+	"github.com/richardwilkes/unison"
+)
+
+const cardPadding = 4
+
+var _ unison.PageProvider = &cardExporter{}
+
+// cardExporter lays out cards in a grid on each page, leaving the cards edge to edge so they can be cut apart easily.
+// Content that doesn't fit on a card is continued on the cards that follow it.
+type cardExporter struct {
+	entity   *gurps.Entity
+	cards    []*cardFace
+	pageSize unison.Size
+	cardSize unison.Size
+	columns  int
+	rows     int
+}
+
+func newCardExporter(entity *gurps.Entity, options export.CardOptions) *cardExporter {
+	c := &cardExporter{entity: entity}
+	w, h := paper.Portrait.Dimensions(options.PaperSize.Dimensions())
+	c.pageSize = unison.NewSize(w.Pixels(), h.Pixels())
+	w, h = options.Size.Dimensions()
+	c.cardSize = unison.NewSize(w.Pixels(), h.Pixels())
+	margin := 2 * paper.Length{Length: 0.25, Units: paper.Inch}.Pixels()
+	c.columns = xmath.Max(int((c.pageSize.Width-margin)/c.cardSize.Width), 1)
+	c.rows = xmath.Max(int((c.pageSize.Height-margin)/c.cardSize.Height), 1)
+	for _, card := range export.Cards(entity, options) {
+		c.cards = append(c.cards, c.layoutCard(card)...)
+	}
+	return c
+}
+
+func (c *cardExporter) exportAsPDFFile(filePath string) error {
+	if len(c.cards) == 0 {
+		return errs.New(i18n.Text("There is nothing to put on cards."))
+	}
+	stream, err := unison.NewFileStream(filePath)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	savedColorMode := saveThemeForExport()
+	defer restoreThemeAfterExport(savedColorMode)
+	return unison.CreatePDF(stream, &unison.PDFMetaData{
+		Title:           c.entity.Profile.Name,
+		Author:          toolbox.CurrentUserName(),
+		Subject:         c.entity.Profile.Name,
+		Keywords:        "GCS Cards",
+		Creator:         "GCS",
+		RasterDPI:       300,
+		EncodingQuality: 101,
+	}, c)
+}
+
+// HasPage implements unison.PageProvider.
+func (c *cardExporter) HasPage(pageNumber int) bool {
+	return pageNumber > 0 && (pageNumber-1)*c.columns*c.rows < len(c.cards)
+}
+
+// PageSize implements unison.PageProvider.
+func (c *cardExporter) PageSize() unison.Size {
+	return c.pageSize
+}
+
+// DrawPage implements unison.PageProvider.
+func (c *cardExporter) DrawPage(gc *unison.Canvas, pageNumber int) error {
+	if !c.HasPage(pageNumber) {
+		return errs.New("invalid page number")
+	}
+	r := unison.Rect{Size: c.pageSize}
+	gc.DrawRect(r, theme.PageColor.Paint(gc, r, unison.Fill))
+	left := (c.pageSize.Width - float32(c.columns)*c.cardSize.Width) / 2
+	top := (c.pageSize.Height - float32(c.rows)*c.cardSize.Height) / 2
+	perPage := c.columns * c.rows
+	for i, card := range c.cards[(pageNumber-1)*perPage : xmath.Min(pageNumber*perPage, len(c.cards))] {
+		c.drawCard(gc, unison.Rect{
+			Point: unison.Point{
+				X: left + float32(i%c.columns)*c.cardSize.Width,
+				Y: top + float32(i/c.columns)*c.cardSize.Height,
+			},
+			Size: c.cardSize,
+		}, card)
+	}
+	return nil
+}
+
+type cardTextRole int
+
+const (
+	cardLabelRole cardTextRole = iota
+	cardFieldRole
+	cardSecondaryRole
+)
+
+// cardLine is a line of text on a card, positioned relative to the top-left corner of the card's body.
+type cardLine struct {
+	text string
+	role cardTextRole
+	x    float32
+	y    float32
+}
+
+// cardFace holds the content of a single physical card. A card whose content doesn't fit is split across several faces.
+type cardFace struct {
+	kind  string
+	title string
+	lines []cardLine
+}
+
+type cardDecorations struct {
+	title *unison.TextDecoration
+	kind  *unison.TextDecoration
+	roles [cardSecondaryRole + 1]*unison.TextDecoration
+}
+
+func newCardDecorations(onHeader, onPage *unison.Paint) *cardDecorations {
+	d := &cardDecorations{
+		title: &unison.TextDecoration{Font: theme.PageLabelPrimaryFont, Paint: onHeader},
+		kind:  &unison.TextDecoration{Font: theme.PageFooterSecondaryFont, Paint: onHeader},
+	}
+	d.roles[cardLabelRole] = &unison.TextDecoration{Font: theme.PageLabelPrimaryFont, Paint: onPage}
+	d.roles[cardFieldRole] = &unison.TextDecoration{Font: theme.PageFieldPrimaryFont, Paint: onPage}
+	d.roles[cardSecondaryRole] = &unison.TextDecoration{Font: theme.PageFieldSecondaryFont, Paint: onPage}
+	return d
+}
+
+// titleBand returns the wrapped title, the kind and the height of the band they occupy at the top of the card.
+func (c *cardExporter) titleBand(face *cardFace, d *cardDecorations) (titleLines []*unison.Text, kind *unison.Text,
+	height float32) {
+	width := c.cardSize.Width - 2
+	kind = unison.NewText(face.kind, d.kind)
+	titleLines = unison.NewTextWrappedLines(face.title, d.title, width-kind.Width()-3*cardPadding)
+	for _, line := range titleLines {
+		height += line.Height()
+	}
+	return titleLines, kind, xmath.Max(height, kind.Height()) + 2*cardPadding
+}
+
+// layoutCard positions the content of the card, splitting it across as many faces as are needed to show all of it.
+func (c *cardExporter) layoutCard(card *export.Card) []*cardFace {
+	d := newCardDecorations(nil, nil)
+	width := c.cardSize.Width - 2 - 2*cardPadding
+	var faces []*cardFace
+	var face *cardFace
+	var y, bottom float32
+	newFace := func() {
+		face = &cardFace{kind: card.Kind, title: card.Title}
+		if len(faces) != 0 {
+			face.title = fmt.Sprintf(i18n.Text("%s (continued)"), card.Title)
+		}
+		faces = append(faces, face)
+		_, _, bandHeight := c.titleBand(face, d)
+		y = 0
+		bottom = c.cardSize.Height - 2 - bandHeight - 2*cardPadding
+	}
+	newFace()
+	// gap adds vertical space, unless at the top of a face.
+	gap := func(amount float32) {
+		if len(face.lines) != 0 {
+			y += amount
+		}
+	}
+	// place reserves room for a row of the given height, moving to a new face if it won't fit on the current one.
+	place := func(height float32) float32 {
+		if y+height > bottom && len(face.lines) != 0 {
+			newFace()
+		}
+		top := y
+		y += height
+		return top
+	}
+	addLines := func(text string, role cardTextRole, x, lineWidth float32) {
+		for _, line := range unison.NewTextWrappedLines(text, d.roles[role], lineWidth) {
+			face.lines = append(face.lines, cardLine{text: line.String(), role: role, x: x, y: place(line.Height())})
+		}
+	}
+	if card.Subtitle != "" {
+		addLines(card.Subtitle, cardSecondaryRole, 0, width)
+	}
+	var labelWidth float32
+	for _, field := range card.Fields {
+		labelWidth = xmath.Max(labelWidth, unison.NewText(field.Label, d.roles[cardLabelRole]).Width())
+	}
+	valueX := labelWidth + cardPadding
+	for _, field := range card.Fields {
+		label := unison.NewText(field.Label, d.roles[cardLabelRole])
+		lines := unison.NewTextWrappedLines(field.Value, d.roles[cardFieldRole], width-valueX)
+		height := label.Height()
+		if len(lines) != 0 {
+			height = xmath.Max(height, lines[0].Height())
+		}
+		top := place(height)
+		face.lines = append(face.lines, cardLine{text: field.Label, role: cardLabelRole, y: top})
+		for i, line := range lines {
+			if i != 0 {
+				top = place(line.Height())
+			}
+			face.lines = append(face.lines, cardLine{text: line.String(), role: cardFieldRole, x: valueX, y: top})
+		}
+	}
+	for _, field := range card.Weapons {
+		gap(cardPadding / 2)
+		addLines(field.Label, cardLabelRole, 0, width)
+		addLines(field.Value, cardFieldRole, cardPadding, width-cardPadding)
+	}
+	if card.Notes != "" {
+		gap(cardPadding)
+		addLines(card.Notes, cardSecondaryRole, 0, width)
+	}
+	return faces
+}
+
+func (c *cardExporter) drawCard(gc *unison.Canvas, r unison.Rect, face *cardFace) {
+	border := theme.HeaderColor.Paint(gc, r, unison.Stroke)
+	gc.DrawRect(r, border)
+	r.X++
+	r.Y++
+	r.Width -= 2
+	r.Height -= 2
+
+	// Title band
+	d := newCardDecorations(theme.OnHeaderColor.Paint(gc, r, unison.Fill), theme.OnPageColor.Paint(gc, r, unison.Fill))
+	titleLines, kind, bandHeight := c.titleBand(face, d)
+	band := unison.Rect{Point: r.Point, Size: unison.Size{Width: r.Width, Height: bandHeight}}
+	gc.DrawRect(band, theme.HeaderColor.Paint(gc, band, unison.Fill))
+	y := band.Y + cardPadding
+	for _, line := range titleLines {
+		line.Draw(gc, band.X+cardPadding, y+line.Baseline())
+		y += line.Height()
+	}
+	kind.Draw(gc, band.Right()-(cardPadding+kind.Width()), band.Y+cardPadding+kind.Baseline())
+
+	// Body
+	x := r.X + cardPadding
+	y = band.Bottom() + cardPadding
+	for _, line := range face.lines {
+		text := unison.NewText(line.text, d.roles[line.role])
+		text.Draw(gc, x+line.x, y+line.y+text.Baseline())
+	}
+}
+
+func (s *Sheet) exportToCards() {
+	options := export.CardOptions{
+		PaperSize: s.entity.SheetSettings.Page.Size,
+		Spells:    true,
+		Powers:    true,
+		Equipment: true,
+	}
+	if !showCardOptionsDialog(&options) {
+		return
+	}
+	s.Window().ShowCursor()
+	dialog := unison.NewSaveDialog()
+	dialog.SetInitialDirectory(filepath.Dir(s.BackingFilePath()))
+	dialog.SetAllowedExtensions("pdf")
+	if dialog.RunModal() {
+		unison.InvokeTaskAfter(func() {
+			if filePath, ok := unison.ValidateSaveFilePath(dialog.Path(), "pdf", false); ok {
+				if err := newCardExporter(s.entity, options).exportAsPDFFile(filePath); err != nil {
+					unison.ErrorDialogWithError(i18n.Text("Unable to export as cards!"), err)
+				}
+			}
+		}, time.Millisecond)
+	}
+}
+
+func showCardOptionsDialog(options *export.CardOptions) bool {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.AddChild(widget.NewFieldLeadingLabel(i18n.Text("Card Size")))
+	cardPopup := unison.NewPopupMenu[paper.CardSize]()
+	for _, one := range paper.AllCardSize {
+		cardPopup.AddItem(one)
+	}
+	cardPopup.Select(options.Size)
+	cardPopup.SelectionCallback = func(_ int, size paper.CardSize) { options.Size = size }
+	panel.AddChild(cardPopup)
+	panel.AddChild(widget.NewFieldLeadingLabel(i18n.Text("Paper Size")))
+	paperPopup := unison.NewPopupMenu[paper.Size]()
+	for _, one := range paper.AllSize {
+		paperPopup.AddItem(one)
+	}
+	paperPopup.Select(options.PaperSize)
+	paperPopup.SelectionCallback = func(_ int, size paper.Size) { options.PaperSize = size }
+	panel.AddChild(paperPopup)
+	for _, one := range []struct {
+		title string
+		value *bool
+	}{
+		{title: i18n.Text("Spells"), value: &options.Spells},
+		{title: i18n.Text("Traits with weapons (powers)"), value: &options.Powers},
+		{title: i18n.Text("Equipment"), value: &options.Equipment},
+	} {
+		value := one.value
+		panel.AddChild(unison.NewPanel())
+		panel.AddChild(widget.NewCheckBox(nil, "", one.title,
+			func() unison.CheckState { return unison.CheckStateFromBool(*value) },
+			func(state unison.CheckState) { *value = state == unison.OnCheckState }))
+	}
+	return unison.QuestionDialogWithPanel(panel) == unison.ModalResponseOK
+}
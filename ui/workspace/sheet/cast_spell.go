@@ -0,0 +1,270 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package sheet
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/gcs/v5/ui/widget/ntable"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+type spellEnergyUndoEdit = *unison.UndoEdit[*spellEnergyData]
+
+type spellEnergyData struct {
+	Owner        widget.Rebuildable
+	Entity       *gurps.Entity
+	Damage       map[string]fxp.Int
+	ActiveSpells []gurps.ActiveSpell
+}
+
+func newSpellEnergyData(owner widget.Rebuildable, entity *gurps.Entity) *spellEnergyData {
+	data := &spellEnergyData{
+		Owner:        owner,
+		Entity:       entity,
+		Damage:       make(map[string]fxp.Int),
+		ActiveSpells: make([]gurps.ActiveSpell, 0, len(entity.ActiveSpells)),
+	}
+	for _, pool := range entity.EnergyPools() {
+		data.Damage[pool.AttrID] = pool.Damage
+	}
+	for _, one := range entity.ActiveSpells {
+		data.ActiveSpells = append(data.ActiveSpells, *one)
+	}
+	return data
+}
+
+func (d *spellEnergyData) Apply() {
+	d.restore()
+	widget.MarkModified(d.Owner)
+}
+
+func (d *spellEnergyData) restore() {
+	for attrID, damage := range d.Damage {
+		if attr, ok := d.Entity.Attributes.Set[attrID]; ok {
+			attr.Damage = damage
+		}
+	}
+	d.Entity.ActiveSpells = nil
+	for i := range d.ActiveSpells {
+		one := d.ActiveSpells[i]
+		d.Entity.ActiveSpells = append(d.Entity.ActiveSpells, &one)
+	}
+}
+
+func (d *spellEnergyData) addUndo(undoSource unison.Paneler, name string) {
+	if mgr := unison.UndoManagerFor(undoSource); mgr != nil {
+		mgr.Add(&unison.UndoEdit[*spellEnergyData]{
+			ID:         unison.NextUndoID(),
+			EditName:   name,
+			UndoFunc:   func(edit spellEnergyUndoEdit) { edit.BeforeData.Apply() },
+			RedoFunc:   func(edit spellEnergyUndoEdit) { edit.AfterData.Apply() },
+			BeforeData: d,
+			AfterData:  newSpellEnergyData(d.Owner, d.Entity),
+		})
+	}
+	widget.MarkModified(d.Owner)
+}
+
+func castTarget(table *unison.Table[*ntable.Node[*gurps.Spell]]) *gurps.Spell {
+	rows := table.SelectedRows(false)
+	if len(rows) != 1 {
+		return nil
+	}
+	spell := rows[0].Data()
+	if spell == nil || spell.Container() || spell.Entity == nil {
+		return nil
+	}
+	return spell
+}
+
+func canCastSpell(table *unison.Table[*ntable.Node[*gurps.Spell]]) bool {
+	return castTarget(table) != nil
+}
+
+func castSpell(owner widget.Rebuildable, table *unison.Table[*ntable.Node[*gurps.Spell]]) {
+	spell := castTarget(table)
+	if spell == nil {
+		return
+	}
+	title := fmt.Sprintf(i18n.Text("Cast %s"), spell.String())
+	cost, err := spell.EnergyCost()
+	if err != nil {
+		unison.ErrorDialogWithError(title, err)
+		return
+	}
+	entity := spell.Entity
+	pools := entity.EnergyPools()
+	if len(pools) == 0 {
+		unison.ErrorDialogWithMessage(title, i18n.Text("There are no energy pools to draw from."))
+		return
+	}
+	pool := pools[0]
+	if !showCastSpellDialog(spell, &cost, pools, &pool) {
+		return
+	}
+	before := newSpellEnergyData(owner, entity)
+	if err = entity.CastSpell(spell, pool.AttrID); err != nil {
+		before.restore()
+		unison.ErrorDialogWithError(title, err)
+		return
+	}
+	before.addUndo(table, i18n.Text("Cast Spell"))
+}
+
+func showCastSpellDialog(spell *gurps.Spell, cost *gurps.SpellCost, pools []*gurps.Attribute, pool **gurps.Attribute) bool {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	label.Text = fmt.Sprintf(i18n.Text("Cast %s"), spell.String())
+	label.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+	panel.AddChild(label)
+	panel.AddChild(widget.NewFieldLeadingLabel(i18n.Text("Cost")))
+	costText := cost.Cost.String()
+	if cost.Reduction > 0 {
+		costText = fmt.Sprintf(i18n.Text("%s (%s, -%s for skill %s)"), cost.Cost.String(), cost.BaseCost.String(),
+			cost.Reduction.String(), spell.LevelData.Level.String())
+	}
+	panel.AddChild(widget.NewNonEditableField(func(field *widget.NonEditableField) { field.Text = costText }))
+	panel.AddChild(widget.NewFieldLeadingLabel(i18n.Text("Maintenance")))
+	maintenanceText := i18n.Text("None")
+	if cost.Maintainable && cost.Duration > 0 {
+		maintenanceText = fmt.Sprintf(i18n.Text("%s every %s"), cost.Maintenance.String(),
+			gurps.FormatSpellDuration(cost.Duration))
+	}
+	panel.AddChild(widget.NewNonEditableField(func(field *widget.NonEditableField) { field.Text = maintenanceText }))
+	panel.AddChild(widget.NewFieldLeadingLabel(i18n.Text("Duration")))
+	durationText := gurps.FormatSpellDuration(cost.Duration)
+	if cost.Permanent {
+		durationText = i18n.Text("Permanent")
+	}
+	panel.AddChild(widget.NewNonEditableField(func(field *widget.NonEditableField) { field.Text = durationText }))
+	panel.AddChild(widget.NewFieldLeadingLabel(i18n.Text("Draw Energy From")))
+	popup := unison.NewPopupMenu[string]()
+	for _, one := range pools {
+		popup.AddItem(fmt.Sprintf("%s (%s/%s)", one.AttributeDef().CombinedName(), one.Current().String(),
+			one.Maximum().String()))
+	}
+	popup.SelectIndex(0)
+	popup.SelectionCallback = func(index int, _ string) { *pool = pools[index] }
+	panel.AddChild(popup)
+	return unison.QuestionDialogWithPanel(panel) == unison.ModalResponseOK
+}
+
+func (s *Sheet) showActiveSpells() {
+	before := newSpellEnergyData(s, s.entity)
+	if !showActiveSpellsDialog(s.entity) {
+		before.restore()
+		return
+	}
+	after := newSpellEnergyData(s, s.entity)
+	if !before.equal(after) {
+		before.addUndo(s, i18n.Text("Active Spells"))
+	}
+}
+
+func (d *spellEnergyData) equal(other *spellEnergyData) bool {
+	if len(d.ActiveSpells) != len(other.ActiveSpells) || len(d.Damage) != len(other.Damage) {
+		return false
+	}
+	for i := range d.ActiveSpells {
+		if d.ActiveSpells[i] != other.ActiveSpells[i] {
+			return false
+		}
+	}
+	for k, v := range d.Damage {
+		if other.Damage[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func showActiveSpellsDialog(entity *gurps.Entity) bool {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	list := unison.NewPanel()
+	list.SetLayout(&unison.FlexLayout{
+		Columns:  6,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.AddChild(list)
+	var rebuild func()
+	rebuild = func() {
+		list.RemoveAllChildren()
+		if len(entity.ActiveSpells) == 0 {
+			label := unison.NewLabel()
+			label.Text = i18n.Text("No spells are currently active.")
+			label.SetLayoutData(&unison.FlexLayoutData{HSpan: 6})
+			list.AddChild(label)
+		}
+		for _, one := range entity.ActiveSpells {
+			addActiveSpellRow(list, entity, one, rebuild)
+		}
+		list.MarkForLayoutAndRedraw()
+		if wnd := panel.Window(); wnd != nil {
+			wnd.Pack()
+		}
+	}
+	rebuild()
+	hint := unison.NewLabel()
+	hint.Text = i18n.Text("Use Advance Time to move time forward for active spells.")
+	panel.AddChild(hint)
+	return unison.QuestionDialogWithPanel(panel) == unison.ModalResponseOK
+}
+
+func addActiveSpellRow(list *unison.Panel, entity *gurps.Entity, spell *gurps.ActiveSpell, rebuild func()) {
+	name := unison.NewLabel()
+	name.Text = spell.Name
+	list.AddChild(name)
+	pool := unison.NewLabel()
+	if attr, ok := entity.Attributes.Set[spell.PoolID]; ok {
+		pool.Text = fmt.Sprintf("%s %s/%s", spell.PoolName(entity), attr.Current().String(), attr.Maximum().String())
+	} else {
+		pool.Text = spell.PoolName(entity)
+	}
+	list.AddChild(pool)
+	remaining := unison.NewLabel()
+	remaining.Text = fmt.Sprintf(i18n.Text("%s remaining"), gurps.FormatSpellDuration(spell.Remaining))
+	list.AddChild(remaining)
+	maintenance := unison.NewLabel()
+	if spell.Maintainable {
+		maintenance.Text = fmt.Sprintf(i18n.Text("Maintain: %s every %s"), spell.Maintenance.String(),
+			gurps.FormatSpellDuration(spell.Duration))
+	}
+	list.AddChild(maintenance)
+	maintain := widget.NewCheckBox(nil, "", i18n.Text("Maintain"),
+		func() unison.CheckState { return unison.CheckStateFromBool(spell.Maintain) },
+		func(state unison.CheckState) { spell.Maintain = state == unison.OnCheckState })
+	maintain.SetEnabled(spell.Maintainable)
+	list.AddChild(maintain)
+	end := unison.NewButton()
+	end.Text = i18n.Text("End")
+	end.ClickCallback = func() {
+		entity.EndSpell(spell)
+		rebuild()
+	}
+	list.AddChild(end)
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package sheet
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/gcs/v5/ui/workspace/editors"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+type effectsUndoEdit = *unison.UndoEdit[*effectsData]
+
+type effectsData struct {
+	sheet   *Sheet
+	effects []gurps.Effect
+}
+
+func newEffectsData(sheet *Sheet) *effectsData {
+	data := &effectsData{
+		sheet:   sheet,
+		effects: make([]gurps.Effect, 0, len(sheet.entity.Effects)),
+	}
+	for _, one := range sheet.entity.Effects {
+		effect := *one
+		effect.Features = one.Features.Clone()
+		data.effects = append(data.effects, effect)
+	}
+	return data
+}
+
+func (d *effectsData) Apply() {
+	d.restore()
+	d.sheet.entity.Recalculate()
+	widget.MarkModified(d.sheet)
+}
+
+func (d *effectsData) restore() {
+	entity := d.sheet.entity
+	entity.Effects = nil
+	for i := range d.effects {
+		effect := d.effects[i]
+		effect.Features = d.effects[i].Features.Clone()
+		entity.Effects = append(entity.Effects, &effect)
+	}
+}
+
+func (d *effectsData) equal(other *effectsData) bool {
+	return reflect.DeepEqual(d.effects, other.effects)
+}
+
+func (s *Sheet) showEffects() {
+	before := newEffectsData(s)
+	if !showEffectsDialog(s.entity) {
+		before.restore()
+		return
+	}
+	after := newEffectsData(s)
+	if before.equal(after) {
+		return
+	}
+	s.UndoManager().Add(&unison.UndoEdit[*effectsData]{
+		ID:         unison.NextUndoID(),
+		EditName:   i18n.Text("Effects"),
+		UndoFunc:   func(edit effectsUndoEdit) { edit.BeforeData.Apply() },
+		RedoFunc:   func(edit effectsUndoEdit) { edit.AfterData.Apply() },
+		BeforeData: before,
+		AfterData:  after,
+	})
+	s.entity.Recalculate()
+	widget.MarkModified(s)
+}
+
+func showEffectsDialog(entity *gurps.Entity) bool {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	list := unison.NewPanel()
+	list.SetLayout(&unison.FlexLayout{
+		Columns:  6,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	var rebuild func()
+	rebuild = func() {
+		list.RemoveAllChildren()
+		if len(entity.Effects) == 0 {
+			label := unison.NewLabel()
+			label.Text = i18n.Text("No effects are currently applied.")
+			label.SetLayoutData(&unison.FlexLayoutData{HSpan: 6})
+			list.AddChild(label)
+		}
+		for _, one := range entity.Effects {
+			addEffectRow(list, entity, one, rebuild)
+		}
+		list.MarkForLayoutAndRedraw()
+		if wnd := panel.Window(); wnd != nil {
+			wnd.Pack()
+		}
+	}
+	rebuild()
+	panel.AddChild(createEffectPalette(entity, rebuild))
+	panel.AddChild(list)
+	hint := unison.NewLabel()
+	hint.Text = i18n.Text("Use Advance Time to move time forward for timed effects.")
+	panel.AddChild(hint)
+	return unison.QuestionDialogWithPanel(panel) == unison.ModalResponseOK
+}
+
+func createEffectPalette(entity *gurps.Entity, rebuild func()) *unison.Panel {
+	palette := unison.NewPanel()
+	palette.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: unison.StdHSpacing,
+	})
+	palette.AddChild(widget.NewFieldLeadingLabel(i18n.Text("Common Effects")))
+	common := gurps.CommonEffects()
+	popup := unison.NewPopupMenu[string]()
+	for _, one := range common {
+		popup.AddItem(fmt.Sprintf("%s (%s)", one.Name, one.Source))
+	}
+	popup.SelectIndex(0)
+	palette.AddChild(popup)
+	b := unison.NewButton()
+	b.Text = i18n.Text("Apply")
+	b.ClickCallback = func() {
+		if index := popup.SelectedIndex(); index >= 0 && index < len(common) {
+			entity.ApplyEffect(common[index].Clone())
+			rebuild()
+		}
+	}
+	palette.AddChild(b)
+	custom := unison.NewButton()
+	custom.Text = i18n.Text("Custom…")
+	custom.ClickCallback = func() {
+		effect := gurps.NewEffect(i18n.Text("Effect"), i18n.Text("Custom"), 0, false)
+		if editors.EditEffect(entity, effect) {
+			entity.ApplyEffect(effect)
+			rebuild()
+		}
+	}
+	palette.AddChild(custom)
+	return palette
+}
+
+func addEffectRow(list *unison.Panel, entity *gurps.Entity, effect *gurps.Effect, rebuild func()) {
+	name := unison.NewLabel()
+	name.Text = effect.String()
+	list.AddChild(name)
+	source := unison.NewLabel()
+	source.Text = effect.Source
+	list.AddChild(source)
+	remaining := unison.NewLabel()
+	if effect.Timed() {
+		remaining.Text = fmt.Sprintf(i18n.Text("%s remaining"), gurps.FormatSpellDuration(effect.Remaining))
+	} else {
+		remaining.Text = i18n.Text("Until removed")
+	}
+	list.AddChild(remaining)
+	stacks := unison.NewPanel()
+	stacks.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+	})
+	if effect.Stackable {
+		for _, delta := range []int{1, -1} {
+			adjustment := delta
+			b := unison.NewButton()
+			if adjustment > 0 {
+				b.Text = "+"
+			} else {
+				b.Text = "-"
+			}
+			b.SetEnabled((adjustment > 0 && effect.CanAddStack()) || (adjustment < 0 && effect.Stacks > 1))
+			b.ClickCallback = func() {
+				effect.AddStacks(adjustment)
+				rebuild()
+			}
+			stacks.AddChild(b)
+		}
+	}
+	list.AddChild(stacks)
+	edit := unison.NewButton()
+	edit.Text = i18n.Text("Edit…")
+	edit.ClickCallback = func() {
+		if editors.EditEffect(entity, effect) {
+			rebuild()
+		}
+	}
+	list.AddChild(edit)
+	remove := unison.NewButton()
+	remove.Text = i18n.Text("Remove")
+	remove.ClickCallback = func() {
+		entity.RemoveEffect(effect)
+		rebuild()
+	}
+	list.AddChild(remove)
+}
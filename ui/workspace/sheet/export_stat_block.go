@@ -0,0 +1,65 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package sheet
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/export"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+func (s *Sheet) exportToStatBlock() {
+	options := export.StatBlockOptions{IncludePoints: true}
+	if !showStatBlockOptionsDialog(&options) {
+		return
+	}
+	s.Window().ShowCursor()
+	ext := options.Format.Extension()
+	dialog := unison.NewSaveDialog()
+	dialog.SetInitialDirectory(filepath.Dir(s.BackingFilePath()))
+	dialog.SetAllowedExtensions(ext[1:])
+	if dialog.RunModal() {
+		unison.InvokeTaskAfter(func() {
+			if filePath, ok := unison.ValidateSaveFilePath(dialog.Path(), ext[1:], false); ok {
+				if err := export.ToStatBlockFile(s.entity, options, filePath); err != nil {
+					unison.ErrorDialogWithError(i18n.Text("Unable to export as stat block!"), err)
+				}
+			}
+		}, time.Millisecond)
+	}
+}
+
+func showStatBlockOptionsDialog(options *export.StatBlockOptions) bool {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.AddChild(widget.NewFieldLeadingLabel(i18n.Text("Format")))
+	popup := unison.NewPopupMenu[export.StatBlockFormat]()
+	for _, one := range export.AllStatBlockFormats {
+		popup.AddItem(one)
+	}
+	popup.Select(options.Format)
+	popup.SelectionCallback = func(_ int, format export.StatBlockFormat) { options.Format = format }
+	panel.AddChild(popup)
+	panel.AddChild(unison.NewPanel())
+	panel.AddChild(widget.NewCheckBox(nil, "", i18n.Text("Include point costs"),
+		func() unison.CheckState { return unison.CheckStateFromBool(options.IncludePoints) },
+		func(state unison.CheckState) { options.IncludePoints = state == unison.OnCheckState }))
+	return unison.QuestionDialogWithPanel(panel) == unison.ModalResponseOK
+}
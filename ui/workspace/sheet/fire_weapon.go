@@ -0,0 +1,118 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package sheet
+
+import (
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/gcs/v5/ui/widget/ntable"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+type ammoListUndoEdit = *unison.UndoEdit[*ammoList]
+
+type ammoList struct {
+	Owner widget.Rebuildable
+	List  []*ammoAdjuster
+}
+
+func (a *ammoList) Apply() {
+	for _, one := range a.List {
+		one.Apply()
+	}
+	widget.MarkModified(a.Owner)
+}
+
+type ammoAdjuster struct {
+	Target       *gurps.Weapon
+	ShotsUsed    int
+	Ammo         *gurps.Equipment
+	AmmoQuantity fxp.Int
+}
+
+func newAmmoAdjuster(target *gurps.Weapon) *ammoAdjuster {
+	a := &ammoAdjuster{
+		Target:    target,
+		ShotsUsed: target.ShotsUsed,
+		Ammo:      target.Ammo(),
+	}
+	if a.Ammo != nil {
+		a.AmmoQuantity = a.Ammo.Quantity
+	}
+	return a
+}
+
+func (a *ammoAdjuster) Apply() {
+	a.Target.ShotsUsed = a.ShotsUsed
+	if a.Ammo != nil {
+		a.Ammo.Quantity = a.AmmoQuantity
+	}
+}
+
+func canFireWeapon(table *unison.Table[*ntable.Node[*gurps.Weapon]]) bool {
+	for _, row := range table.SelectedRows(false) {
+		if w := row.Data(); w != nil && w.CanFire(1) {
+			return true
+		}
+	}
+	return false
+}
+
+func fireWeapon(owner widget.Rebuildable, table *unison.Table[*ntable.Node[*gurps.Weapon]]) {
+	adjustAmmo(owner, table, i18n.Text("Fire"), func(w *gurps.Weapon) bool {
+		return w.Fire(1) == nil
+	})
+}
+
+func canReloadWeapon(table *unison.Table[*ntable.Node[*gurps.Weapon]]) bool {
+	for _, row := range table.SelectedRows(false) {
+		if w := row.Data(); w != nil && w.CanReload() {
+			return true
+		}
+	}
+	return false
+}
+
+func reloadWeapon(owner widget.Rebuildable, table *unison.Table[*ntable.Node[*gurps.Weapon]]) {
+	adjustAmmo(owner, table, i18n.Text("Reload"), func(w *gurps.Weapon) bool {
+		return w.Reload() == nil
+	})
+}
+
+func adjustAmmo(owner widget.Rebuildable, table *unison.Table[*ntable.Node[*gurps.Weapon]], name string, f func(w *gurps.Weapon) bool) {
+	before := &ammoList{Owner: owner}
+	after := &ammoList{Owner: owner}
+	for _, row := range table.SelectedRows(false) {
+		if w := row.Data(); w != nil {
+			adjuster := newAmmoAdjuster(w)
+			if f(w) {
+				before.List = append(before.List, adjuster)
+				after.List = append(after.List, newAmmoAdjuster(w))
+			}
+		}
+	}
+	if len(before.List) > 0 {
+		if mgr := unison.UndoManagerFor(table); mgr != nil {
+			mgr.Add(&unison.UndoEdit[*ammoList]{
+				ID:         unison.NextUndoID(),
+				EditName:   name,
+				UndoFunc:   func(edit ammoListUndoEdit) { edit.BeforeData.Apply() },
+				RedoFunc:   func(edit ammoListUndoEdit) { edit.AfterData.Apply() },
+				BeforeData: before,
+				AfterData:  after,
+			})
+		}
+		widget.MarkModified(before.Owner)
+	}
+}
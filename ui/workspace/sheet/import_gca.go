@@ -0,0 +1,33 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package sheet
+
+import (
+	"path/filepath"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps/gca"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	xfs "github.com/richardwilkes/toolbox/xio/fs"
+	"github.com/richardwilkes/unison"
+)
+
+// NewSheetFromGCAFile imports a GURPS Character Assistant file into a new, unsaved character sheet and shows the
+// conversion report.
+func NewSheetFromGCAFile(filePath string) (unison.Dockable, error) {
+	entity, report, err := gca.ImportFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	s := NewSheet(xfs.TrimExtension(filepath.Base(filePath))+library.SheetExt, entity)
+	unison.InvokeTask(func() { showImportReport(report) })
+	return s, nil
+}
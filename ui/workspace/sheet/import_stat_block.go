@@ -0,0 +1,76 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package sheet
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps/importer"
+	"github.com/richardwilkes/gcs/v5/model/gurps/statblock"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/gcs/v5/ui/workspace"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/log/jot"
+	"github.com/richardwilkes/unison"
+)
+
+// ImportStatBlock asks the user for the text of a stat block and creates a new character sheet from it.
+func ImportStatBlock() {
+	var text string
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	label.Text = i18n.Text("Paste the text of the stat block to import:")
+	panel.AddChild(label)
+	field := widget.NewMultiLineStringField(nil, "", i18n.Text("Stat Block"),
+		func() string { return text },
+		func(s string) { text = s })
+	field.SetLayoutData(&unison.FlexLayoutData{
+		SizeHint: unison.Size{Width: 500, Height: 300},
+		HAlign:   unison.FillAlignment,
+		VAlign:   unison.FillAlignment,
+		HGrab:    true,
+		VGrab:    true,
+	})
+	panel.AddChild(field)
+	if unison.QuestionDialogWithPanel(panel) != unison.ModalResponseOK || strings.TrimSpace(text) == "" {
+		return
+	}
+	entity, report := statblock.Import(text)
+	workspace.DisplayNewDockable(nil, NewSheet(entity.Profile.Name+library.SheetExt, entity))
+	showImportReport(report)
+}
+
+func showImportReport(report *importer.Report) {
+	md := widget.NewMarkdown()
+	md.SetContent(report.String(), 500)
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(md, unison.FillBehavior, unison.UnmodifiedBehavior)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		SizeHint: unison.Size{Height: 300},
+		HAlign:   unison.FillAlignment,
+		VAlign:   unison.FillAlignment,
+		HGrab:    true,
+		VGrab:    true,
+	})
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon, unison.DefaultDialogTheme.QuestionIconInk,
+		scroll, []*unison.DialogButtonInfo{unison.NewOKButtonInfo()})
+	if err != nil {
+		jot.Error(err)
+		return
+	}
+	dialog.RunModal()
+}
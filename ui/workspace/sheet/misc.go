@@ -12,6 +12,9 @@
 package sheet
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/gcs/v5/model/jio"
 	"github.com/richardwilkes/gcs/v5/ui/widget"
@@ -75,9 +78,36 @@ func NewMiscPanel(entity *gurps.Entity, targetMgr *widget.TargetMgr) *MiscPanel
 		func() string { return m.entity.Profile.PlayerName },
 		func(s string) { m.entity.Profile.PlayerName = s }))
 
+	m.AddChild(widget.NewPageLabelEnd(i18n.Text("Effects")))
+	m.AddChild(widget.NewNonEditablePageField(func(f *widget.NonEditablePageField) {
+		text, tooltip := m.effectsText()
+		f.Tooltip = unison.NewTooltipWithText(tooltip)
+		if text != f.Text {
+			f.Text = text
+			widget.MarkForLayoutWithinDockable(f)
+		}
+	}))
+
 	return m
 }
 
+func (m *MiscPanel) effectsText() (text, tooltip string) {
+	if len(m.entity.Effects) == 0 {
+		return i18n.Text("None"), i18n.Text("No effects are currently applied")
+	}
+	names := make([]string, 0, len(m.entity.Effects))
+	details := make([]string, 0, len(m.entity.Effects))
+	for _, one := range m.entity.Effects {
+		name := one.String()
+		names = append(names, name)
+		if one.Timed() {
+			name = fmt.Sprintf(i18n.Text("%s (%s remaining)"), name, gurps.FormatSpellDuration(one.Remaining))
+		}
+		details = append(details, name)
+	}
+	return strings.Join(names, ", "), strings.Join(details, "\n")
+}
+
 // UpdateModified updates the current modification timestamp.
 func (m *MiscPanel) UpdateModified() {
 	m.entity.ModifiedOn = jio.Now()
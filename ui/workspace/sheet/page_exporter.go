@@ -51,7 +51,7 @@ func newPageExporter(entity *gurps.Entity) *pageExporter {
 				case gurps.BlockLayoutMeleeKey:
 					addRowPanel(rowPanel, NewMeleeWeaponsPageList(entity), gurps.BlockLayoutMeleeKey, startAt)
 				case gurps.BlockLayoutRangedKey:
-					addRowPanel(rowPanel, NewRangedWeaponsPageList(entity), gurps.BlockLayoutRangedKey, startAt)
+					addRowPanel(rowPanel, NewRangedWeaponsPageList(nil, entity), gurps.BlockLayoutRangedKey, startAt)
 				case gurps.BlockLayoutTraitsKey:
 					addRowPanel(rowPanel, NewTraitsPageList(p, entity), gurps.BlockLayoutTraitsKey, startAt)
 				case gurps.BlockLayoutSkillsKey:
@@ -210,8 +210,8 @@ func (p *pageExporter) exportAsPDFFile(filePath string) error {
 }
 
 func (p *pageExporter) exportAsPDF(stream unison.Stream) error {
-	savedColorMode := p.saveTheme()
-	defer p.restoreTheme(savedColorMode)
+	savedColorMode := saveThemeForExport()
+	defer restoreThemeAfterExport(savedColorMode)
 	if err := unison.CreatePDF(stream, &unison.PDFMetaData{
 		Title:           p.entity.Profile.Name,
 		Author:          toolbox.CurrentUserName(),
@@ -246,8 +246,8 @@ func (p *pageExporter) exportAsJPEGs(filePathBase string) error {
 
 func (p *pageExporter) exportAsImages(filePathBase, extension string, f func(img *unison.Image) ([]byte, error)) error {
 	filePathBase = strings.TrimSuffix(filePathBase, extension)
-	savedColorMode := p.saveTheme()
-	defer p.restoreTheme(savedColorMode)
+	savedColorMode := saveThemeForExport()
+	defer restoreThemeAfterExport(savedColorMode)
 	resolution := settings.Global().General.ImageResolution
 	pageNumber := 1
 	for p.HasPage(pageNumber) {
@@ -274,7 +274,8 @@ func (p *pageExporter) exportAsImages(filePathBase, extension string, f func(img
 	return nil
 }
 
-func (p *pageExporter) saveTheme() unison.ColorMode {
+// saveThemeForExport switches to the light color mode, which is always used for exports, returning the prior mode.
+func saveThemeForExport() unison.ColorMode {
 	savedColorMode := unison.CurrentColorMode()
 	unison.SetColorMode(unison.LightColorMode)
 	unison.ThemeChanged()
@@ -282,7 +283,8 @@ func (p *pageExporter) saveTheme() unison.ColorMode {
 	return savedColorMode
 }
 
-func (p *pageExporter) restoreTheme(colorMode unison.ColorMode) {
+// restoreThemeAfterExport restores the color mode that was in effect before saveThemeForExport() was called.
+func restoreThemeAfterExport(colorMode unison.ColorMode) {
 	unison.SetColorMode(colorMode)
 	unison.ThemeChanged()
 	unison.RebuildDynamicColors()
@@ -54,6 +54,8 @@ func NewCarriedEquipmentPageList(owner widget.Rebuildable, provider gurps.ListPr
 	p.installDecrementQuantityHandler(owner)
 	p.installIncrementUsesHandler(owner)
 	p.installDecrementUsesHandler(owner)
+	p.installBuyHandler(owner)
+	p.installSellHandler(owner)
 	p.installIncrementTechLevelHandler(owner)
 	p.installDecrementTechLevelHandler(owner)
 	p.installConvertToContainerHandler(owner)
@@ -67,6 +69,8 @@ func NewOtherEquipmentPageList(owner widget.Rebuildable, provider gurps.ListProv
 	p.installDecrementQuantityHandler(owner)
 	p.installIncrementUsesHandler(owner)
 	p.installDecrementUsesHandler(owner)
+	p.installBuyHandler(owner)
+	p.installSellHandler(owner)
 	p.installIncrementTechLevelHandler(owner)
 	p.installDecrementTechLevelHandler(owner)
 	p.installConvertToContainerHandler(owner)
@@ -92,6 +96,7 @@ func NewSpellsPageList(owner widget.Rebuildable, provider gurps.SpellListProvide
 	p.installDecrementPointsHandler(owner)
 	p.installIncrementSkillHandler(owner)
 	p.installDecrementSkillHandler(owner)
+	p.installCastSpellHandler(owner)
 	return p
 }
 
@@ -115,9 +120,15 @@ func NewMeleeWeaponsPageList(entity *gurps.Entity) *PageList[*gurps.Weapon] {
 	return newPageList(nil, editors.NewWeaponsProvider(entity, weapon.Melee, true))
 }
 
-// NewRangedWeaponsPageList creates the ranged weapons page list.
-func NewRangedWeaponsPageList(entity *gurps.Entity) *PageList[*gurps.Weapon] {
-	return newPageList(nil, editors.NewWeaponsProvider(entity, weapon.Ranged, true))
+// NewRangedWeaponsPageList creates the ranged weapons page list. If owner is non-nil, the fire and reload actions will
+// be enabled.
+func NewRangedWeaponsPageList(owner widget.Rebuildable, entity *gurps.Entity) *PageList[*gurps.Weapon] {
+	p := newPageList(nil, editors.NewWeaponsProvider(entity, weapon.Ranged, true))
+	if owner != nil {
+		p.installFireWeaponHandler(owner)
+		p.installReloadWeaponHandler(owner)
+	}
+	return p
 }
 
 func newPageList[T gurps.NodeTypes](owner widget.Rebuildable, provider ntable.TableProvider[T]) *PageList[T] {
@@ -264,6 +275,46 @@ func (p *PageList[T]) installDecrementUsesHandler(owner widget.Rebuildable) {
 	}
 }
 
+func (p *PageList[T]) installBuyHandler(owner widget.Rebuildable) {
+	if t, ok := (any(p.Table)).(*unison.Table[*ntable.Node[*gurps.Equipment]]); ok {
+		p.InstallCmdHandlers(constants.BuyItemID,
+			func(_ any) bool { return canBuy(t) },
+			func(_ any) { buyOrSell(owner, t, true) })
+	}
+}
+
+func (p *PageList[T]) installSellHandler(owner widget.Rebuildable) {
+	if t, ok := (any(p.Table)).(*unison.Table[*ntable.Node[*gurps.Equipment]]); ok {
+		p.InstallCmdHandlers(constants.SellItemID,
+			func(_ any) bool { return canSell(t) },
+			func(_ any) { buyOrSell(owner, t, false) })
+	}
+}
+
+func (p *PageList[T]) installFireWeaponHandler(owner widget.Rebuildable) {
+	if t, ok := (any(p.Table)).(*unison.Table[*ntable.Node[*gurps.Weapon]]); ok {
+		p.InstallCmdHandlers(constants.FireWeaponItemID,
+			func(_ any) bool { return canFireWeapon(t) },
+			func(_ any) { fireWeapon(owner, t) })
+	}
+}
+
+func (p *PageList[T]) installReloadWeaponHandler(owner widget.Rebuildable) {
+	if t, ok := (any(p.Table)).(*unison.Table[*ntable.Node[*gurps.Weapon]]); ok {
+		p.InstallCmdHandlers(constants.ReloadWeaponItemID,
+			func(_ any) bool { return canReloadWeapon(t) },
+			func(_ any) { reloadWeapon(owner, t) })
+	}
+}
+
+func (p *PageList[T]) installCastSpellHandler(owner widget.Rebuildable) {
+	if t, ok := (any(p.Table)).(*unison.Table[*ntable.Node[*gurps.Spell]]); ok {
+		p.InstallCmdHandlers(constants.CastSpellItemID,
+			func(_ any) bool { return canCastSpell(t) },
+			func(_ any) { castSpell(owner, t) })
+	}
+}
+
 func (p *PageList[T]) installIncrementSkillHandler(owner widget.Rebuildable) {
 	p.InstallCmdHandlers(constants.IncrementSkillLevelItemID,
 		func(_ any) bool { return canAdjustSkillLevel(p.Table, true) },
@@ -13,6 +13,7 @@ package sheet
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/gurps"
@@ -70,7 +71,7 @@ func NewPointsPanel(entity *gurps.Entity, targetMgr *widget.TargetMgr) *PointsPa
 		HAlign: unison.FillAlignment,
 		VAlign: unison.MiddleAlignment,
 	})
-	p.unspent.Tooltip = unison.NewTooltipWithText(i18n.Text("Points earned but not yet spent"))
+	p.updateUnspentTooltip()
 	p.AddChild(p.unspent)
 	p.AddChild(widget.NewPageLabel(i18n.Text("Unspent")))
 	p.addPointsField(widget.NewNonEditablePageFieldEnd(func(f *widget.NonEditablePageField) {
@@ -123,6 +124,18 @@ func NewPointsPanel(entity *gurps.Entity, targetMgr *widget.TargetMgr) *PointsPa
 	return p
 }
 
+// updateUnspentTooltip sets the tooltip of the unspent points field, listing the advancements the points were earned
+// from.
+func (p *PointsPanel) updateUnspentTooltip() {
+	var buffer strings.Builder
+	buffer.WriteString(i18n.Text("Points earned but not yet spent"))
+	for _, one := range p.entity.Advancements {
+		buffer.WriteString("\n")
+		buffer.WriteString(one.String())
+	}
+	p.unspent.Tooltip = unison.NewTooltipWithText(buffer.String())
+}
+
 func (p *PointsPanel) addPointsField(field *widget.NonEditablePageField, title, tooltip string) {
 	field.Tooltip = unison.NewTooltipWithText(tooltip)
 	p.AddChild(field)
@@ -134,6 +147,7 @@ func (p *PointsPanel) addPointsField(field *widget.NonEditablePageField, title,
 // Sync the panel to the current data.
 func (p *PointsPanel) Sync() {
 	p.unspent.Sync()
+	p.updateUnspentTooltip()
 	var overallTotal string
 	if p.entity.SheetSettings.ExcludeUnspentPointsFromTotal {
 		overallTotal = p.entity.SpentPoints().String()
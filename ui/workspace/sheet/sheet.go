@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/richardwilkes/gcs/v5/constants"
+	"github.com/richardwilkes/gcs/v5/model/export"
 	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/gcs/v5/model/gurps/gid"
 	gsettings "github.com/richardwilkes/gcs/v5/model/gurps/settings"
@@ -266,11 +267,20 @@ func NewSheet(filePath string, entity *gurps.Entity) *Sheet {
 				return s.Traits.provider.RootRows()
 			}, gurps.NewNaturalAttacks(s.entity, nil))
 	})
+	s.InstallCmdHandlers(constants.AdvanceTimeItemID, unison.AlwaysEnabled, func(_ any) { s.advanceTime() })
+	s.InstallCmdHandlers(constants.ActiveSpellsItemID, unison.AlwaysEnabled, func(_ any) { s.showActiveSpells() })
+	s.InstallCmdHandlers(constants.EffectsItemID, unison.AlwaysEnabled, func(_ any) { s.showEffects() })
 	s.InstallCmdHandlers(constants.SwapDefaultsItemID, s.canSwapDefaults, s.swapDefaults)
 	s.InstallCmdHandlers(constants.ExportAsPDFItemID, unison.AlwaysEnabled, func(_ any) { s.exportToPDF() })
 	s.InstallCmdHandlers(constants.ExportAsWEBPItemID, unison.AlwaysEnabled, func(_ any) { s.exportToWEBP() })
 	s.InstallCmdHandlers(constants.ExportAsPNGItemID, unison.AlwaysEnabled, func(_ any) { s.exportToPNG() })
 	s.InstallCmdHandlers(constants.ExportAsJPEGItemID, unison.AlwaysEnabled, func(_ any) { s.exportToJPEG() })
+	s.InstallCmdHandlers(constants.ExportAsStatBlockItemID, unison.AlwaysEnabled, func(_ any) { s.exportToStatBlock() })
+	s.InstallCmdHandlers(constants.ExportAsFantasyGroundsItemID, unison.AlwaysEnabled,
+		func(_ any) { s.exportToFantasyGrounds() })
+	s.InstallCmdHandlers(constants.ExportAsHTMLItemID, unison.AlwaysEnabled, func(_ any) { s.exportToHTML() })
+	s.InstallCmdHandlers(constants.ExportAsCardsItemID, unison.AlwaysEnabled, func(_ any) { s.exportToCards() })
+	s.InstallCmdHandlers(constants.ExportAsBundleItemID, unison.AlwaysEnabled, func(_ any) { s.exportToBundle() })
 	s.InstallCmdHandlers(constants.PrintItemID, unison.AlwaysEnabled, func(_ any) { s.print() })
 
 	return s
@@ -521,6 +531,38 @@ func (s *Sheet) exportToJPEG() {
 	}
 }
 
+func (s *Sheet) exportToFantasyGrounds() {
+	s.Window().ShowCursor()
+	dialog := unison.NewSaveDialog()
+	dialog.SetInitialDirectory(filepath.Dir(s.BackingFilePath()))
+	dialog.SetAllowedExtensions("xml")
+	if dialog.RunModal() {
+		unison.InvokeTaskAfter(func() {
+			if filePath, ok := unison.ValidateSaveFilePath(dialog.Path(), "xml", false); ok {
+				if err := export.ToFantasyGroundsFile(s.entity, filePath); err != nil {
+					unison.ErrorDialogWithError(i18n.Text("Unable to export as Fantasy Grounds XML!"), err)
+				}
+			}
+		}, time.Millisecond)
+	}
+}
+
+func (s *Sheet) exportToHTML() {
+	s.Window().ShowCursor()
+	dialog := unison.NewSaveDialog()
+	dialog.SetInitialDirectory(filepath.Dir(s.BackingFilePath()))
+	dialog.SetAllowedExtensions("html")
+	if dialog.RunModal() {
+		unison.InvokeTaskAfter(func() {
+			if filePath, ok := unison.ValidateSaveFilePath(dialog.Path(), "html", false); ok {
+				if err := export.ToHTMLFile(s.entity, filePath); err != nil {
+					unison.ErrorDialogWithError(i18n.Text("Unable to export as HTML!"), err)
+				}
+			}
+		}, time.Millisecond)
+	}
+}
+
 func (s *Sheet) createLists() {
 	children := s.content.Children()
 	if len(children) == 0 {
@@ -576,7 +618,7 @@ func (s *Sheet) createLists() {
 				rowPanel.AddChild(s.MeleeWeapons)
 			case gurps.BlockLayoutRangedKey:
 				if s.RangedWeapons == nil {
-					s.RangedWeapons = NewRangedWeaponsPageList(s.entity)
+					s.RangedWeapons = NewRangedWeaponsPageList(s, s.entity)
 				} else {
 					s.RangedWeapons.Sync()
 				}
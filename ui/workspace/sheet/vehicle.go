@@ -0,0 +1,552 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package sheet
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/richardwilkes/gcs/v5/constants"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	gsettings "github.com/richardwilkes/gcs/v5/model/gurps/settings"
+	"github.com/richardwilkes/gcs/v5/model/gurps/weapon"
+	"github.com/richardwilkes/gcs/v5/model/library"
+	"github.com/richardwilkes/gcs/v5/model/settings"
+	"github.com/richardwilkes/gcs/v5/model/theme"
+	"github.com/richardwilkes/gcs/v5/res"
+	"github.com/richardwilkes/gcs/v5/ui/widget"
+	"github.com/richardwilkes/gcs/v5/ui/workspace"
+	"github.com/richardwilkes/gcs/v5/ui/workspace/editors"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/log/jot"
+	"github.com/richardwilkes/toolbox/xio/fs"
+	"github.com/richardwilkes/unison"
+)
+
+var (
+	_ workspace.FileBackedDockable = &Vehicle{}
+	_ unison.UndoManagerProvider   = &Vehicle{}
+	_ widget.ModifiableRoot        = &Vehicle{}
+	_ widget.Rebuildable           = &Vehicle{}
+	_ widget.DockableKind          = &Vehicle{}
+	_ unison.TabCloser             = &Vehicle{}
+)
+
+// Vehicle holds the view for a GURPS vehicle.
+type Vehicle struct {
+	unison.Panel
+	path              string
+	targetMgr         *widget.TargetMgr
+	undoMgr           *unison.UndoManager
+	toolbar           *unison.Panel
+	scroll            *unison.ScrollPanel
+	vehicle           *gurps.Vehicle
+	crc               uint64
+	scale             int
+	scaleField        *widget.PercentageField
+	needsSaveAsPrompt bool
+}
+
+// NewVehicleFromFile loads a GURPS vehicle file and creates a new unison.Dockable for it.
+func NewVehicleFromFile(filePath string) (unison.Dockable, error) {
+	vehicle, err := gurps.NewVehicleFromFile(os.DirFS(filepath.Dir(filePath)), filepath.Base(filePath))
+	if err != nil {
+		return nil, err
+	}
+	vehicle.SetFilePath(filePath)
+	v := NewVehicle(filePath, vehicle)
+	v.needsSaveAsPrompt = false
+	return v, nil
+}
+
+// NewVehicle creates a new unison.Dockable for GURPS vehicle files.
+func NewVehicle(filePath string, vehicle *gurps.Vehicle) *Vehicle {
+	d := &Vehicle{
+		path:              filePath,
+		undoMgr:           unison.NewUndoManager(200, func(err error) { jot.Error(err) }),
+		scroll:            unison.NewScrollPanel(),
+		vehicle:           vehicle,
+		scale:             settings.Global().General.InitialSheetUIScale,
+		crc:               vehicle.CRC64(),
+		needsSaveAsPrompt: true,
+	}
+	d.Self = d
+	d.targetMgr = widget.NewTargetMgr(d)
+	d.SetLayout(&unison.FlexLayout{
+		Columns: 1,
+		HAlign:  unison.FillAlignment,
+		VAlign:  unison.FillAlignment,
+	})
+	d.MouseDownCallback = func(_ unison.Point, _, _ int, _ unison.Modifiers) bool {
+		d.RequestFocus()
+		return false
+	}
+
+	d.scroll.SetContent(d.createContent(), unison.FillBehavior, unison.UnmodifiedBehavior)
+	d.scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		VAlign: unison.FillAlignment,
+		HGrab:  true,
+		VGrab:  true,
+	})
+	d.scroll.DrawCallback = func(gc *unison.Canvas, rect unison.Rect) {
+		gc.DrawRect(rect, theme.PageVoidColor.Paint(gc, rect, unison.Fill))
+	}
+
+	scaleTitle := i18n.Text("Scale")
+	d.scaleField = widget.NewPercentageField(nil, "", scaleTitle,
+		func() int { return d.scale },
+		func(v int) {
+			d.scale = v
+			d.applyScale()
+		}, gsettings.InitialUIScaleMin, gsettings.InitialUIScaleMax, false, false)
+	d.scaleField.SetMarksModified(false)
+	d.scaleField.Tooltip = unison.NewTooltipWithText(scaleTitle)
+
+	d.toolbar = unison.NewPanel()
+	d.toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.DividerColor, 0, unison.Insets{Bottom: 1},
+		false), unison.NewEmptyBorder(unison.StdInsets())))
+	d.toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	d.toolbar.AddChild(d.scaleField)
+	d.toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(d.toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+	})
+
+	d.AddChild(d.toolbar)
+	d.AddChild(d.scroll)
+
+	d.applyScale()
+
+	d.InstallCmdHandlers(constants.SaveItemID, func(_ any) bool { return d.Modified() }, func(_ any) { d.save(false) })
+	d.InstallCmdHandlers(constants.SaveAsItemID, unison.AlwaysEnabled, func(_ any) { d.save(true) })
+
+	return d
+}
+
+// Entity implements gurps.EntityProvider. Returns the character sheet of the vehicle's gunner, if any.
+func (d *Vehicle) Entity() *gurps.Entity {
+	return d.vehicle.Entity()
+}
+
+// DockableKind implements widget.DockableKind
+func (d *Vehicle) DockableKind() string {
+	return widget.VehicleDockableKind
+}
+
+func (d *Vehicle) applyScale() {
+	d.scroll.Content().AsPanel().SetScale(float32(d.scale) / 100)
+	d.scroll.Sync()
+}
+
+// UndoManager implements undo.Provider
+func (d *Vehicle) UndoManager() *unison.UndoManager {
+	return d.undoMgr
+}
+
+// TitleIcon implements workspace.FileBackedDockable
+func (d *Vehicle) TitleIcon(suggestedSize unison.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  library.FileInfoFor(d.path).SVG,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements workspace.FileBackedDockable
+func (d *Vehicle) Title() string {
+	return fs.BaseName(d.path)
+}
+
+func (d *Vehicle) String() string {
+	return d.Title()
+}
+
+// Tooltip implements workspace.FileBackedDockable
+func (d *Vehicle) Tooltip() string {
+	return d.path
+}
+
+// BackingFilePath implements workspace.FileBackedDockable
+func (d *Vehicle) BackingFilePath() string {
+	return d.path
+}
+
+// SetBackingFilePath implements workspace.FileBackedDockable
+func (d *Vehicle) SetBackingFilePath(p string) {
+	d.path = p
+	if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
+		dc.UpdateTitle(d)
+	}
+}
+
+// Modified implements workspace.FileBackedDockable
+func (d *Vehicle) Modified() bool {
+	return d.crc != d.vehicle.CRC64()
+}
+
+// MarkModified implements widget.ModifiableRoot.
+func (d *Vehicle) MarkModified(_ unison.Paneler) {
+	if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
+		dc.UpdateTitle(d)
+	}
+}
+
+// MayAttemptClose implements unison.TabCloser
+func (d *Vehicle) MayAttemptClose() bool {
+	return workspace.MayAttemptCloseOfGroup(d)
+}
+
+// AttemptClose implements unison.TabCloser
+func (d *Vehicle) AttemptClose() bool {
+	if !workspace.CloseGroup(d) {
+		return false
+	}
+	if d.Modified() {
+		switch unison.YesNoCancelDialog(fmt.Sprintf(i18n.Text("Save changes made to\n%s?"), d.Title()), "") {
+		case unison.ModalResponseDiscard:
+		case unison.ModalResponseOK:
+			if !d.save(false) {
+				return false
+			}
+		case unison.ModalResponseCancel:
+			return false
+		}
+	}
+	if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
+		dc.Close(d)
+	}
+	return true
+}
+
+func (d *Vehicle) save(forceSaveAs bool) bool {
+	success := false
+	if forceSaveAs || d.needsSaveAsPrompt {
+		success = workspace.SaveDockableAs(d, library.VehiclesExt, d.vehicle.Save, func(path string) {
+			d.crc = d.vehicle.CRC64()
+			d.path = path
+		})
+	} else {
+		success = workspace.SaveDockable(d, d.vehicle.Save, func() { d.crc = d.vehicle.CRC64() })
+	}
+	if success {
+		d.needsSaveAsPrompt = false
+	}
+	return success
+}
+
+// Rebuild implements widget.Rebuildable.
+func (d *Vehicle) Rebuild(full bool) {
+	h, v := d.scroll.Position()
+	focusRefKey := d.targetMgr.CurrentFocusRef()
+	if full {
+		d.scroll.SetContent(d.createContent(), unison.FillBehavior, unison.UnmodifiedBehavior)
+		d.applyScale()
+	}
+	widget.DeepSync(d)
+	if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
+		dc.UpdateTitle(d)
+	}
+	d.targetMgr.ReacquireFocus(focusRefKey, d.toolbar, d.scroll.Content())
+	d.scroll.SetPosition(h, v)
+}
+
+func (d *Vehicle) createContent() unison.Paneler {
+	content := unison.NewPanel()
+	content.SetBorder(unison.NewEmptyBorder(unison.NewUniformInsets(16)))
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	content.DrawCallback = func(gc *unison.Canvas, rect unison.Rect) {
+		gc.DrawRect(rect, theme.PageColor.Paint(gc, rect, unison.Fill))
+	}
+	content.AddChild(d.createIdentitySection())
+	content.AddChild(d.createStatsSection())
+	content.AddChild(d.createCrewSection())
+	for _, wt := range weapon.AllType {
+		section := newVehicleSection(wt.AltString(), 1)
+		section.AddChild(editors.NewWeaponsPanel(d, d.vehicle, wt, &d.vehicle.WeaponList))
+		content.AddChild(section)
+	}
+	notes := newVehicleSection(i18n.Text("Notes"), 1)
+	field := widget.NewMultiLineStringField(d.targetMgr, "vehicle:notes", i18n.Text("Notes"),
+		func() string { return d.vehicle.UserNotes },
+		func(s string) { d.vehicle.UserNotes = s })
+	field.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	notes.AddChild(field)
+	content.AddChild(notes)
+	return content
+}
+
+func (d *Vehicle) createIdentitySection() *unison.Panel {
+	section := newVehicleSection(i18n.Text("Identity"), 4)
+	d.addStringField(section, "vehicle:name", i18n.Text("Name"), "", &d.vehicle.Name, true)
+	d.addStringField(section, "vehicle:tl", i18n.Text("TL"), i18n.Text("Tech Level"), &d.vehicle.TechLevel, false)
+	d.addStringField(section, "vehicle:locations", i18n.Text("Locations"),
+		i18n.Text("The hit location codes for the vehicle, such as \"G3W\""), &d.vehicle.Locations, true)
+	d.addStringField(section, "vehicle:ref", i18n.Text("Page Reference"), gurps.PageRefTooltipText,
+		&d.vehicle.PageRef, false)
+	return section
+}
+
+func (d *Vehicle) createStatsSection() *unison.Panel {
+	section := newVehicleSection(i18n.Text("Statistics"), 8)
+	d.addIntegerField(section, "vehicle:st", i18n.Text("ST"), i18n.Text("Strength"), &d.vehicle.ST, 0, 99999, false)
+	d.addIntegerField(section, "vehicle:hp", i18n.Text("HP"), i18n.Text("Hit Points"), &d.vehicle.HP, 0, 99999, false)
+	d.addIntegerField(section, "vehicle:hnd", i18n.Text("Hnd"), i18n.Text("Handling"), &d.vehicle.Handling, -10,
+		10, true)
+	d.addIntegerField(section, "vehicle:sr", i18n.Text("SR"), i18n.Text("Stability Rating"), &d.vehicle.Stability, 0,
+		10, false)
+	d.addIntegerField(section, "vehicle:ht", i18n.Text("HT"), i18n.Text("Health"), &d.vehicle.HT, 0, 99, false)
+	d.addStringField(section, "vehicle:htcodes", i18n.Text("HT Codes"),
+		i18n.Text("Codes following HT, such as \"f\" for flammable or \"c\" for combustible"), &d.vehicle.HTCodes,
+		false)
+	d.addIntegerField(section, "vehicle:accel", i18n.Text("Accel"), i18n.Text("Acceleration"),
+		&d.vehicle.Acceleration, 0, 99999, false)
+	d.addIntegerField(section, "vehicle:speed", i18n.Text("Top Speed"), i18n.Text("Top Speed"), &d.vehicle.TopSpeed,
+		0, 99999, false)
+	d.addDecimalField(section, "vehicle:lwt", i18n.Text("LWt"), i18n.Text("Loaded Weight, in tons"),
+		&d.vehicle.LoadedWeight)
+	d.addDecimalField(section, "vehicle:load", i18n.Text("Load"),
+		i18n.Text("Load, in tons, including occupants"), &d.vehicle.Load)
+	d.addIntegerField(section, "vehicle:sm", i18n.Text("SM"), i18n.Text("Size Modifier"), &d.vehicle.SizeModifier,
+		-20, 20, true)
+	d.addStringField(section, "vehicle:occ", i18n.Text("Occ"),
+		i18n.Text("Occupancy, such as \"1+3\" for a crew of one and three passengers"), &d.vehicle.Occupancy, false)
+	d.addStringField(section, "vehicle:dr", i18n.Text("DR"), i18n.Text("Damage Resistance"), &d.vehicle.DR, false)
+	d.addIntegerField(section, "vehicle:range", i18n.Text("Range"), i18n.Text("Range, in miles"), &d.vehicle.Range,
+		0, 999999, false)
+	d.addDecimalField(section, "vehicle:cost", i18n.Text("Cost"), i18n.Text("Cost"), &d.vehicle.Cost)
+	return section
+}
+
+func (d *Vehicle) createCrewSection() *unison.Panel {
+	section := newVehicleSection(i18n.Text("Crew"), 1)
+	addButton := unison.NewSVGButton(res.CircledAddSVG)
+	addButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Add a crew member"))
+	addButton.ClickCallback = func() {
+		d.changeCrew(i18n.Text("Add Crew Member"), func() {
+			d.vehicle.SetCrew(append(d.vehicle.Crew, &gurps.VehicleCrew{Role: i18n.Text("Crew")}))
+		})
+	}
+	section.AddChild(addButton)
+	for i, one := range d.vehicle.Crew {
+		section.AddChild(d.createCrewRow(i, one))
+	}
+	return section
+}
+
+func (d *Vehicle) createCrewRow(index int, crew *gurps.VehicleCrew) *unison.Panel {
+	row := unison.NewPanel()
+	row.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	roleTitle := i18n.Text("Role")
+	row.AddChild(widget.NewFieldLeadingLabel(roleTitle))
+	role := widget.NewStringField(d.targetMgr, fmt.Sprintf("vehicle:crew:%d:role", index), roleTitle,
+		func() string { return crew.Role },
+		func(s string) { crew.Role = s })
+	role.SetMinimumTextWidthUsing("Commander")
+	row.AddChild(role)
+	nameTitle := i18n.Text("Name")
+	row.AddChild(widget.NewFieldInteriorLeadingLabel(nameTitle))
+	name := widget.NewStringField(d.targetMgr, fmt.Sprintf("vehicle:crew:%d:name", index), nameTitle,
+		func() string { return crew.Name },
+		func(s string) { crew.Name = s })
+	name.Watermark = crew.DisplayName()
+	name.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	row.AddChild(name)
+	sheetLabel := unison.NewLabel()
+	if crew.Path == "" {
+		sheetLabel.Text = i18n.Text("No character sheet")
+	} else {
+		sheetLabel.Text = filepath.Base(crew.ResolvedPath())
+		if crew.Entity() == nil {
+			sheetLabel.Text = fmt.Sprintf(i18n.Text("%s (unavailable)"), sheetLabel.Text)
+		}
+		sheetLabel.Tooltip = unison.NewTooltipWithText(crew.ResolvedPath())
+	}
+	row.AddChild(sheetLabel)
+	chooser := unison.NewSVGButton(res.OpenFolderSVG)
+	chooser.Tooltip = unison.NewTooltipWithText(i18n.Text("Choose the character sheet for this crew member"))
+	chooser.ClickCallback = func() {
+		if p, ok := chooseCrewSheet(); ok {
+			d.changeCrew(i18n.Text("Choose Crew Character Sheet"), func() {
+				crew.SetResolvedPath(p)
+				crew.Reload()
+			})
+		}
+	}
+	row.AddChild(chooser)
+	gunner := unison.NewCheckBox()
+	gunner.Text = i18n.Text("Gunner")
+	gunner.State = unison.CheckStateFromBool(crew.Gunner)
+	gunner.ClickCallback = func() {
+		d.changeCrew(i18n.Text("Change Gunner"), func() {
+			if crew.Gunner {
+				crew.Gunner = false
+			} else {
+				d.vehicle.SetGunner(crew)
+			}
+		})
+	}
+	gunner.Tooltip = unison.NewTooltipWithText(i18n.Text("The skills of the gunner's character sheet are used to determine the skill levels of the vehicle's weapons"))
+	row.AddChild(gunner)
+	trash := unison.NewSVGButton(res.TrashSVG)
+	trash.Tooltip = unison.NewTooltipWithText(i18n.Text("Remove this crew member"))
+	trash.ClickCallback = func() {
+		d.changeCrew(i18n.Text("Remove Crew Member"), func() {
+			list := make([]*gurps.VehicleCrew, 0, len(d.vehicle.Crew))
+			for _, one := range d.vehicle.Crew {
+				if one != crew {
+					list = append(list, one)
+				}
+			}
+			d.vehicle.SetCrew(list)
+		})
+	}
+	row.AddChild(trash)
+	row.SetLayout(&unison.FlexLayout{
+		Columns:  len(row.Children()),
+		HSpacing: unison.StdHSpacing,
+	})
+	return row
+}
+
+type crewUndoEdit = *unison.UndoEdit[[]*gurps.VehicleCrew]
+
+// changeCrew applies a change to the crew, recording it for undo.
+func (d *Vehicle) changeCrew(name string, change func()) {
+	before := d.vehicle.CloneCrew()
+	change()
+	d.undoMgr.Add(&unison.UndoEdit[[]*gurps.VehicleCrew]{
+		ID:         unison.NextUndoID(),
+		EditName:   name,
+		UndoFunc:   func(edit crewUndoEdit) { d.restoreCrew(edit.BeforeData) },
+		RedoFunc:   func(edit crewUndoEdit) { d.restoreCrew(edit.AfterData) },
+		BeforeData: before,
+		AfterData:  d.vehicle.CloneCrew(),
+	})
+	d.crewChanged()
+}
+
+func (d *Vehicle) restoreCrew(crew []*gurps.VehicleCrew) {
+	list := make([]*gurps.VehicleCrew, len(crew))
+	for i, one := range crew {
+		list[i] = one.Clone()
+	}
+	d.vehicle.SetCrew(list)
+	d.crewChanged()
+}
+
+// crewChanged rebuilds the content, since changes to the crew may alter the gunner and therefore the resolved values of
+// the vehicle's weapons. This is deferred, as the change is typically triggered by a control that will be discarded.
+func (d *Vehicle) crewChanged() {
+	d.MarkModified(nil)
+	unison.InvokeTask(func() { d.Rebuild(true) })
+}
+
+func (d *Vehicle) addStringField(parent *unison.Panel, key, labelText, tooltip string, fieldData *string, grab bool) {
+	d.addLabel(parent, labelText, tooltip)
+	field := widget.NewStringField(d.targetMgr, key, labelText,
+		func() string { return *fieldData },
+		func(s string) { *fieldData = s })
+	if tooltip != "" {
+		field.Tooltip = unison.NewTooltipWithText(tooltip)
+	}
+	if grab {
+		field.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: unison.FillAlignment,
+			HGrab:  true,
+		})
+	}
+	parent.AddChild(field)
+}
+
+func (d *Vehicle) addIntegerField(parent *unison.Panel, key, labelText, tooltip string, fieldData *int, min, max int, forceSign bool) {
+	d.addLabel(parent, labelText, tooltip)
+	field := widget.NewIntegerField(d.targetMgr, key, labelText,
+		func() int { return *fieldData },
+		func(v int) { *fieldData = v },
+		min, max, forceSign, false)
+	if tooltip != "" {
+		field.Tooltip = unison.NewTooltipWithText(tooltip)
+	}
+	parent.AddChild(field)
+}
+
+func (d *Vehicle) addDecimalField(parent *unison.Panel, key, labelText, tooltip string, fieldData *fxp.Int) {
+	d.addLabel(parent, labelText, tooltip)
+	field := widget.NewDecimalField(d.targetMgr, key, labelText,
+		func() fxp.Int { return *fieldData },
+		func(v fxp.Int) { *fieldData = v },
+		0, fxp.Max, false, false)
+	if tooltip != "" {
+		field.Tooltip = unison.NewTooltipWithText(tooltip)
+	}
+	parent.AddChild(field)
+}
+
+func (d *Vehicle) addLabel(parent *unison.Panel, labelText, tooltip string) {
+	label := widget.NewFieldLeadingLabel(labelText)
+	if tooltip != "" {
+		label.Tooltip = unison.NewTooltipWithText(tooltip)
+	}
+	parent.AddChild(label)
+}
+
+func newVehicleSection(title string, columns int) *unison.Panel {
+	section := unison.NewPanel()
+	section.SetLayout(&unison.FlexLayout{
+		Columns:  columns,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	section.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  2,
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	section.SetBorder(unison.NewCompoundBorder(&widget.TitledBorder{Title: title},
+		unison.NewEmptyBorder(unison.NewUniformInsets(4))))
+	return section
+}
+
+func chooseCrewSheet() (string, bool) {
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(false)
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions(library.SheetExt)
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	global := settings.Global()
+	dialog.SetInitialDirectory(global.LastDir(settings.DefaultLastDirKey))
+	if !dialog.RunModal() {
+		return "", false
+	}
+	p := dialog.Path()
+	global.SetLastDir(settings.DefaultLastDirKey, filepath.Dir(p))
+	return p, true
+}